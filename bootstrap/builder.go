@@ -2,6 +2,8 @@ package bootstrap
 
 import (
 	"taskgo/bootstrap/load"
+	"taskgo/internal/deps"
+	"taskgo/internal/services"
 	"taskgo/pkg/ioc"
 	"taskgo/pkg/utils"
 
@@ -73,13 +75,39 @@ func (b *appBuilder) LoadNotify() *appBuilder {
 	return b
 }
 
+func (b *appBuilder) LoadMailer() *appBuilder {
+	load.InitMailer(b.container)
+	b.runActions()
+	return b
+}
+
+func (b *appBuilder) LoadSMS() *appBuilder {
+	load.InitSMS(b.container)
+	b.runActions()
+	return b
+}
+
+func (b *appBuilder) LoadEvents() *appBuilder {
+	load.InitEvents(b.container)
+	b.runActions()
+	return b
+}
+
 func (b *appBuilder) LoadWebsocketServer() *appBuilder {
 	load.InitWebsocketServer(b.container)
+	load.InitWebsocketRelay(b.container)
 	b.runActions()
 	registerWebSocketsChannels(b.container)
 	return b
 }
 
+// LoadWebsocketRelay loads only the relay to the websocket clients (processes without websocket clients e.g. the workers)
+func (b *appBuilder) LoadWebsocketRelay() *appBuilder {
+	load.InitWebsocketRelay(b.container)
+	b.runActions()
+	return b
+}
+
 func (b *appBuilder) runActions() {
 	for _, fn := range b.actions {
 		fn()
@@ -97,6 +125,8 @@ func (b *appBuilder) mustLoadEnvFile() {
 func (b *appBuilder) Boot() *appBuilder {
 	registerServiceProviders(b.container)
 	b.container.Bootstrap()
+	registerOrderStatusHooks(deps.App[*services.OrderStateMachine]())
+	registerNotifyHooks()
 	b.runActions()
 	return b
 }
@@ -9,7 +9,10 @@ import (
 	"taskgo/internal/adapters"
 	"taskgo/internal/api/routes"
 	"taskgo/internal/deps"
+	"taskgo/internal/tasks"
 	chainq "taskgo/pkg/asynq_chain"
+	middlewareq "taskgo/pkg/asynq_middleware"
+	schedulerq "taskgo/pkg/asynq_scheduler"
 	"taskgo/pkg/ioc"
 	"taskgo/pkg/utils"
 	"time"
@@ -103,8 +106,21 @@ func GetRegisteredTaskHandlers() map[string]asynq.Handler {
 			adapters.NewLoggerAdapter(deps.Log().Log()),
 		)
 
+		// Check chains control (cancel, pause, resume) before running each step
+		orchestrator.SetControl(
+			tasks.ChainControl(),
+			deps.Config().GetDuration("queue.chain.pause_poll_interval", 30*time.Second),
+		)
+
+		// Skip the already completed steps (asynq is at-least-once)
+		orchestrator.SetStepTracker(tasks.ChainStepTracker())
+
 		// Get all individual task handlers
+		// with the task type / queue limits (applied also when the handler runs as a chain step)
 		individualHandlers := registerTaskHandlers()
+		for taskType, handler := range individualHandlers {
+			individualHandlers[taskType] = middlewareq.Wrap(handler, tasks.Limiter().Middleware())
+		}
 
 		// Auto-register all individual handlers with the orchestrator
 		for taskType, handler := range individualHandlers {
@@ -123,3 +139,13 @@ func GetRegisteredTaskHandlers() map[string]asynq.Handler {
 
 	return registeredTasks
 }
+
+// GetRegisteredTaskMiddleware returns the middleware applied on all the tasks
+func GetRegisteredTaskMiddleware() []asynq.MiddlewareFunc {
+	return registerTaskMiddleware()
+}
+
+// GetRegisteredSchedules returns all the recurring tasks schedules
+func GetRegisteredSchedules() []schedulerq.Entry {
+	return registerSchedules()
+}
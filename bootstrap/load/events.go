@@ -0,0 +1,42 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"taskgo/internal/deps"
+	"taskgo/pkg/events"
+	"taskgo/pkg/ioc"
+	"taskgo/pkg/utils"
+)
+
+func InitEvents(c *ioc.Container) {
+	err := ioc.Singleton(c, func(c *ioc.Container) (events.Publisher, error) {
+		cfg := deps.Config()
+
+		if !cfg.GetBool("events.enabled", true) {
+			// Events are dropped
+			return events.PublisherFunc(func(ctx context.Context, events ...events.Event) error { return nil }), nil
+		}
+
+		switch driver := cfg.GetString("events.driver", "redis"); driver {
+		case "memory":
+			return events.NewMemoryBus(), nil
+		case "redis":
+			cache := deps.Cache()
+			if cache == nil || cache.Redis == nil {
+				return nil, errors.New("events redis driver requires the redis cache connection")
+			}
+			return events.NewRedisStreamPublisher(
+				cache.Redis,
+				cfg.GetString("events.redis.stream", "taskgo:events"),
+				int64(cfg.GetInt("events.redis.max_len", 100000)),
+			), nil
+		default:
+			return nil, errors.New("unsupported events driver: " + driver)
+		}
+	})
+
+	if err != nil {
+		utils.PrintErr("Failed to load events module in the ioc container: " + err.Error())
+	}
+}
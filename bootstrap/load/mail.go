@@ -0,0 +1,49 @@
+package load
+
+import (
+	"errors"
+	netMail "net/mail"
+	"taskgo/internal/deps"
+	"taskgo/internal/notification"
+	"taskgo/pkg/ioc"
+	"taskgo/pkg/mail"
+	"taskgo/pkg/utils"
+)
+
+func InitMailer(c *ioc.Container) {
+	err := ioc.Singleton(c, func(c *ioc.Container) (*mail.Mailer, error) {
+		cfg := deps.Config()
+
+		var transport mail.Transport
+		switch driver := cfg.GetString("mail.driver", "file"); driver {
+		case "smtp":
+			transport = mail.NewSMTPTransport(
+				cfg.GetString("mail.smtp.host", "localhost"),
+				cfg.GetInt("mail.smtp.port", 1025),
+				cfg.GetString("mail.smtp.username", ""),
+				cfg.GetString("mail.smtp.password", ""),
+			)
+		case "file":
+			transport = mail.NewFileTransport(cfg.GetString("mail.file.path", "storage/mail"))
+		case "memory":
+			transport = mail.NewMemoryTransport()
+		default:
+			return nil, errors.New("unsupported mail driver: " + driver)
+		}
+
+		from := netMail.Address{
+			Name:    cfg.GetString("mail.from.name", "TaskGo"),
+			Address: cfg.GetString("mail.from.address", "no-reply@taskgo.local"),
+		}
+
+		return mail.NewMailer(
+			transport,
+			from.String(),
+			mail.NewTemplates(notification.MailTemplates(), cfg.GetString("mail.layout", "default")),
+		), nil
+	})
+
+	if err != nil {
+		utils.PrintErr("Failed to load mail module in the ioc container: " + err.Error())
+	}
+}
@@ -25,7 +25,7 @@ func InitNotify(c *ioc.Container, channelsHandlers map[string]notify.Notificatio
 			client = queue.Client
 		}
 
-		notify := notify.New(logger, client, asynq.Queue(tasks.QueueNotifications), asynq.Unique(5*time.Minute), asynq.MaxRetry(3))
+		notify := notify.New(logger, client, asynq.Queue(tasks.QueueNotifications), asynq.Unique(5*time.Minute), tasks.RetryPolicies().MaxRetry(notify.TypeSendNotification, tasks.QueueNotifications))
 		notify.RegisterChannels(channelsHandlers)
 		return notify, nil
 	})
@@ -30,11 +30,11 @@ func InitRedisCache(c *ioc.Container) {
 func InitRedisQueue(c *ioc.Container) {
 	// load queue connection
 	err := ioc.Singleton(c, func(c *ioc.Container) (*deps.QueueClient, error) {
-		queueClient, err := queueClient("redis.connections.queue")
+		queueClient, inspector, err := queueClient("redis.connections.queue")
 		if err != nil {
 			return nil, err
 		}
-		return deps.NewQueueClient(queueClient), nil
+		return deps.NewQueueClient(queueClient, inspector), nil
 	})
 
 	if err != nil {
@@ -62,18 +62,18 @@ func cacheClient(key string) (*redis.Client, error) {
 	return client, nil
 }
 
-// loads the queue connection from the config
-func queueClient(key string) (*asynq.Client, error) {
+// loads the queue connection (client + inspector) from the config
+func queueClient(key string) (*asynq.Client, *asynq.Inspector, error) {
 	ops, err := utils.GetRedisQueueClientOptionsForAsynq(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	client := asynq.NewClient(ops)
 
 	if err := client.Ping(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return client, nil
+	return client, asynq.NewInspector(ops), nil
 }
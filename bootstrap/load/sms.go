@@ -0,0 +1,33 @@
+package load
+
+import (
+	"errors"
+	"taskgo/internal/deps"
+	"taskgo/pkg/ioc"
+	"taskgo/pkg/sms"
+	"taskgo/pkg/utils"
+)
+
+func InitSMS(c *ioc.Container) {
+	err := ioc.Singleton(c, func(c *ioc.Container) (*sms.Client, error) {
+		cfg := deps.Config()
+
+		var provider sms.Provider
+		switch driver := cfg.GetString("sms.driver", "fake"); driver {
+		case "fake":
+			provider = sms.NewFakeProvider()
+		default:
+			return nil, errors.New("unsupported sms driver: " + driver)
+		}
+
+		return sms.NewClient(
+			provider,
+			cfg.GetString("sms.from", "TaskGo"),
+			cfg.GetInt("sms.max_segments", 3),
+		), nil
+	})
+
+	if err != nil {
+		utils.PrintErr("Failed to load sms module in the ioc container: " + err.Error())
+	}
+}
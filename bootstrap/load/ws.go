@@ -1,11 +1,13 @@
 package load
 
 import (
+	"errors"
 	"net/http"
 	"taskgo/internal/deps"
 	"taskgo/pkg/ioc"
 	"taskgo/pkg/utils"
 	"taskgo/pkg/ws"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -21,11 +23,30 @@ func InitWebsocketServer(c *ioc.Container) {
 		}
 
 		upgrader := websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool { return checkOrigin(r, origin) },
-			// HandshakeTimeout: 1 * time.Second,
+			CheckOrigin:      func(r *http.Request) bool { return checkOrigin(r, origin) },
+			HandshakeTimeout: 10 * time.Second,
 		}
 
-		return ws.NewWsServer(ws.NewHub(), upgrader), nil
+		cfg := deps.Config()
+		hub := ws.NewHubWithOptions(ws.Options{
+			WriteWait:        cfg.GetDuration("websocket.connection.write_wait", 10*time.Second),
+			PongWait:         cfg.GetDuration("websocket.connection.pong_wait", 60*time.Second),
+			PingPeriod:       cfg.GetDuration("websocket.connection.ping_period", 54*time.Second),
+			MaxMessageSize:   int64(cfg.GetInt("websocket.connection.max_message_size", 8*1024)),
+			SendBuffer:       cfg.GetInt("websocket.connection.send_buffer", 256),
+			CloseGracePeriod: cfg.GetDuration("websocket.connection.close_grace_period", 5*time.Second),
+		})
+		backplane, err := websocketBackplane()
+		if err != nil {
+			return nil, err
+		}
+		if backplane != nil {
+			if err := hub.UseBackplane(backplane); err != nil {
+				return nil, err
+			}
+		}
+
+		return ws.NewWsServer(hub, upgrader), nil
 	})
 
 	if err != nil {
@@ -33,6 +54,47 @@ func InitWebsocketServer(c *ioc.Container) {
 	}
 }
 
+// InitWebsocketRelay loads the relay publishing the messages to the websocket clients
+// (the websocket server hub if it's loaded otherwise the backplane)
+func InitWebsocketRelay(c *ioc.Container) {
+	err := ioc.Singleton(c, func(c *ioc.Container) (ws.Relay, error) {
+		if server, err := ioc.AppMake[*ws.Server](); err == nil {
+			return server.Hub, nil
+		}
+
+		backplane, err := websocketBackplane()
+		if err != nil {
+			return nil, err
+		}
+		if backplane == nil {
+			return nil, errors.New("websocket relay requires the redis backplane out of the websocket server")
+		}
+		return backplane, nil
+	})
+
+	if err != nil {
+		utils.PrintErr("Failed to load websocket relay module in the ioc container : " + err.Error())
+	}
+}
+
+// websocketBackplane returns the configured backplane (nil for the memory driver)
+func websocketBackplane() (ws.Backplane, error) {
+	cfg := deps.Config()
+
+	switch driver := cfg.GetString("websocket.backplane.driver", "redis"); driver {
+	case "memory":
+		return nil, nil
+	case "redis":
+		cache := deps.Cache()
+		if cache == nil || cache.Redis == nil {
+			return nil, errors.New("websocket redis backplane requires the redis cache connection")
+		}
+		return ws.NewRedisBackplane(cache.Redis, cfg.GetString("websocket.backplane.channel", "taskgo:ws")), nil
+	default:
+		return nil, errors.New("unsupported websocket backplane driver: " + driver)
+	}
+}
+
 func checkOrigin(r *http.Request, origin []string) bool {
 	if len(origin) > 0 {
 		if origin[0] == "*" {
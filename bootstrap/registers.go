@@ -1,18 +1,28 @@
 package bootstrap
 
 import (
+	"context"
 	"sync"
+	"taskgo/internal/adapters"
 	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/events"
+	"taskgo/internal/notification"
 	"taskgo/internal/notification/handlers"
 	"taskgo/internal/providers"
 	"taskgo/internal/rules"
+	"taskgo/internal/services"
 	"taskgo/internal/tasks"
+	middlewareq "taskgo/pkg/asynq_middleware"
+	schedulerq "taskgo/pkg/asynq_scheduler"
 	"taskgo/pkg/ioc"
 	"taskgo/pkg/notify"
 	"taskgo/pkg/ws"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
 )
 
 /*
@@ -32,20 +42,122 @@ var registeredRules = map[string]validator.Func{
 	"egyptian_phone": rules.EgyptianPhone,
 }
 
+// registerTaskMiddleware defines the middleware applied on all the tasks processed by the worker
+func registerTaskMiddleware() []asynq.MiddlewareFunc {
+	return []asynq.MiddlewareFunc{
+		middlewareq.Tracing(tasks.TraceIDFromTask),
+		middlewareq.Recover(adapters.NewLoggerAdapter(deps.Log().Channel("queue_log"))),
+	}
+}
+
 // registerTaskHandlers defines all individual task handlers
+// handlers opt in to their own middleware with middlewareq.Wrap (also applied when executed as chain steps)
 func registerTaskHandlers() map[string]asynq.Handler {
 	return map[string]asynq.Handler{
-		tasks.TypeProcessPayment:   deps.App[*tasks.ProcessPaymentHandler](),
+		tasks.TypeProcessPayment: middlewareq.Wrap(deps.App[*tasks.ProcessPaymentHandler](),
+			middlewareq.Timeout(30*time.Second),
+		),
+		// Not wrapped with middlewareq.Transaction, it doesn't write to the database
 		tasks.TypeInventoryCheck:   deps.App[*tasks.InventoryCheckHandler](),
 		tasks.TypeSendNotification: deps.App[*notify.NotificationHandler](),
+		tasks.TypeWebhookDelivery:  deps.App[*tasks.WebhookDeliveryHandler](),
+		tasks.TypeGenerateReport:   deps.App[*tasks.GenerateReportHandler](),
+		tasks.TypeNightlyReport:    deps.App[*tasks.NightlyReportHandler](),
+		tasks.TypeApplySalesRollup: deps.App[*tasks.ApplySalesRollupHandler](),
 		//...
 	}
 }
 
+// registerSchedules defines all recurring tasks (enqueued by the workers scheduler leader)
+// the notification digests aren't scheduled (each batch enqueues its own delayed task at the end of its window),
+// the reservation expiry and the inventory sync aren't either (the reservations have no expiry and there is no external inventory to sync)
+func registerSchedules() []schedulerq.Entry {
+	cfg := deps.Config()
+
+	var entries []schedulerq.Entry
+	if cfg.GetBool("reports.nightly.enabled", true) {
+		entries = append(entries, schedulerq.Entry{
+			Name:     "nightly sales report",
+			Cronspec: cfg.GetString("reports.nightly.cronspec", "0 2 * * *"),
+			Task:     tasks.NewNightlyReportTask(cfg.GetString("reports.nightly.format", "xlsx")),
+		})
+	}
+	//...
+
+	return entries
+}
+
+// registerNotifyHooks routes the notifications by the users preferences, records their deliveries
+// and accumulates the digests in the redis cache (if the notify module is loaded)
+func registerNotifyHooks() {
+	n, err := ioc.AppMake[*notify.Notify]()
+	if err != nil {
+		return
+	}
+	n.UseRouter(deps.App[*services.NotificationPreferenceService]())
+	n.UseDeliveryRecorder(deps.App[*services.NotificationDeliveryService]())
+
+	// The digest notifications are sent on their own without the redis cache
+	cfg := deps.Config()
+	cache, err := ioc.AppMake[*deps.CacheClient]()
+	if err != nil || cache.Redis == nil || !cfg.GetBool("notifications.digests.enabled", true) {
+		return
+	}
+	store := notify.NewRedisDigestStore(cache.Redis, cfg.GetString("notifications.digests.prefix", "notify:digests:"), cfg.GetDuration("notifications.digests.retention", 24*time.Hour))
+	n.UseDigests(store, notification.DigestSummarizers())
+}
+
+// registerOrderStatusHooks defines the hooks running after each order status transition
+func registerOrderStatusHooks(stateMachine *services.OrderStateMachine) {
+	// Publish the status change domain event
+	stateMachine.OnTransition(func(ctx context.Context, change services.OrderStatusChange) error {
+		event, err := events.OrderStatusChanged(change.Order, change.From, change.Reason)
+		events.Publish(ctx, event, err)
+		return nil
+	})
+
+	// Deliver the order.<status> event to the partners webhooks, the deliveries are written to the outbox
+	// in the status transaction so a queue outage doesn't drop them (enqueued by the outbox relay)
+	stateMachine.OnTransitionTx(func(ctx context.Context, tx *gorm.DB, change services.OrderStatusChange) error {
+		event, err := events.OrderStatusEntered(change.Order, change.From, change.Reason)
+		if err != nil {
+			return err
+		}
+		return tasks.AddWebhookEvent(ctx, tx, event)
+	})
+
+	// Drop the remaining steps of the order processing chain (e.g. the payment of an order cancelled mid-chain)
+	stateMachine.OnEnter(enums.OrderStatusCancelled, func(ctx context.Context, change services.OrderStatusChange) error {
+		return deps.App[*services.ChainService]().CancelChain(ctx, tasks.OrderProcessingChainID(change.Order.ID))
+	})
+
+	// Count the order in the daily sales rollups, the task is written to the outbox in the status transaction
+	// so a failure is retried by the queue instead of leaving the rollups wrong (enqueued by the outbox relay)
+	applySalesRollup := func(ctx context.Context, tx *gorm.DB, change services.OrderStatusChange) error {
+		message, err := tasks.NewApplySalesRollupTask(change.Order.ID).Message()
+		if err != nil {
+			return err
+		}
+		_, err = deps.App[*services.OutboxService]().Add(tx, message)
+		return err
+	}
+	stateMachine.OnEnterTx(enums.OrderStatusConfirmed, applySalesRollup)
+	stateMachine.OnEnterTx(enums.OrderStatusCancelled, applySalesRollup)
+	stateMachine.OnEnterTx(enums.OrderStatusRefunded, applySalesRollup)
+
+	// Notify the customer (confirmed, payment failed, shipped, delivered, cancelled, refunded)
+	stateMachine.OnTransition(func(ctx context.Context, change services.OrderStatusChange) error {
+		return deps.App[*services.OrderNotificationService]().NotifyStatusChange(ctx, change)
+	})
+}
+
 // registerNotificationsHandlers defines all individual notification handlers
 func registerNotifyChannelsHandlers() map[string]notify.NotificationChannelHandler {
 	return map[string]notify.NotificationChannelHandler{
 		"database": handlers.DatabaseChannelHandler,
+		"ws":       handlers.WebSocketChannelHandler,
+		"email":    handlers.EmailChannelHandler,
+		"sms":      handlers.SMSChannelHandler,
 	}
 }
 
@@ -61,6 +173,17 @@ func registerServiceProviders(c *ioc.Container) {
 func registerWebSocketsChannels(c *ioc.Container) {
 	hub := deps.WS().Hub
 
+	// Register the user channel (notifications pushed by the server only)
+	hub.RegisterChannel(&ws.ChannelPolicy{
+		Pattern: "user.*",
+		CanRead: func(userID, channel string) bool {
+			return channel == handlers.UserChannel(userID)
+		},
+		CanWrite: func(userID, channel string) bool {
+			return false
+		},
+	})
+
 	// Register the user notifications websocket channel
 	hub.RegisterChannel(&ws.ChannelPolicy{
 		Pattern: "user_notifications.*",
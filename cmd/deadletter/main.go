@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"taskgo/bootstrap"
+	"taskgo/internal/deps"
+	"taskgo/internal/filters"
+	"taskgo/internal/services"
+	"taskgo/pkg/enums"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Dead Letter CLI
+|------------------------------------------
+|	go run ./cmd/deadletter list [-status=archived] [-type=process:payment] [-page=1] [-per-page=20]
+|	go run ./cmd/deadletter show <id>
+|	go run ./cmd/deadletter replay [-payload=edited_payload.json] <id> [id...]
+|	go run ./cmd/deadletter discard <id> [id...]
+|------------------------------------------
+*/
+
+const usage = "Usage: deadletter list|show|replay|discard [flags] [ids...]"
+
+func main() {
+	bootstrap.NewAppBuilder(".env").
+		LoadConfig().
+		LoadLogger().
+		LoadDatabase().
+		LoadRedisCache().
+		LoadRedisQueue().
+		Boot()
+
+	err := run()
+
+	// Shutdown before exiting (os.Exit doesn't run the deferred calls)
+	bootstrap.Shutdown()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, enums.Red.Value()+err.Error()+enums.Reset.Value())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if len(os.Args) < 2 {
+		return errors.New(usage)
+	}
+
+	failedTaskService := deps.App[*services.FailedTaskService]()
+	if failedTaskService == nil {
+		return errors.New("failed task service is not loaded")
+	}
+
+	ctx := context.Background()
+	command, args := os.Args[1], os.Args[2:]
+
+	switch command {
+	case "list":
+		return listFailedTasks(ctx, failedTaskService, args)
+	case "show":
+		return showFailedTask(ctx, failedTaskService, args)
+	case "replay":
+		return replayFailedTasks(ctx, failedTaskService, args)
+	case "discard":
+		return discardFailedTasks(ctx, failedTaskService, args)
+	default:
+		return errors.New(usage)
+	}
+}
+
+func listFailedTasks(ctx context.Context, s *services.FailedTaskService, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	status := fs.String("status", "archived", "failed tasks status (archived, replayed, discarded)")
+	taskType := fs.String("type", "", "task type")
+	queue := fs.String("queue", "", "queue name")
+	page := fs.Int("page", 1, "page")
+	perPage := fs.Int("per-page", 20, "failed tasks per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	failedTasks, total, err := s.GetPaginatedFailedTasks(ctx, &filters.FailedTaskFilters{
+		Status:   *status,
+		TaskType: *taskType,
+		Queue:    *queue,
+		Page:     *page,
+		PerPage:  *perPage,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTASK TYPE\tQUEUE\tCHAIN STEP\tSTATUS\tFAILED AT\tLAST ERROR")
+	for _, failedTask := range failedTasks {
+		chainStep := "-"
+		if failedTask.ChainStep != nil {
+			chainStep = fmt.Sprintf("%s#%d", failedTask.ChainID, *failedTask.ChainStep)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			failedTask.ID,
+			failedTask.TaskType,
+			failedTask.Queue,
+			chainStep,
+			failedTask.Status,
+			failedTask.FailedAt.Format(time.RFC3339),
+			failedTask.LastError,
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\nShowing %d of %d failed tasks\n", len(failedTasks), total)
+	return nil
+}
+
+func showFailedTask(ctx context.Context, s *services.FailedTaskService, args []string) error {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return errors.New("Usage: deadletter show <id>")
+	}
+
+	failedTask, err := s.GetFailedTaskById(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+
+	out, _ := json.MarshalIndent(map[string]any{
+		"id":               failedTask.ID,
+		"task_id":          failedTask.TaskID,
+		"task_type":        failedTask.TaskType,
+		"queue":            failedTask.Queue,
+		"status":           failedTask.Status,
+		"chain_id":         failedTask.ChainID,
+		"chain_step":       failedTask.ChainStep,
+		"retry_count":      failedTask.RetryCount,
+		"max_retry":        failedTask.MaxRetry,
+		"failed_at":        failedTask.FailedAt,
+		"last_error":       failedTask.LastError,
+		"error_chain":      json.RawMessage(failedTask.ErrorChain),
+		"attempts":         json.RawMessage(failedTask.Attempts),
+		"payload":          json.RawMessage(failedTask.Payload),
+		"payload_encoding": failedTask.PayloadEncoding,
+	}, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
+
+func replayFailedTasks(ctx context.Context, s *services.FailedTaskService, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	payloadFile := fs.String("payload", "", "json file with the edited payload (single task only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var payload json.RawMessage
+	if *payloadFile != "" {
+		content, err := os.ReadFile(*payloadFile)
+		if err != nil {
+			return err
+		}
+		payload = content
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	results, err := s.ReplayFailedTasks(ctx, ids, payload)
+	if err != nil {
+		return err
+	}
+	printResults("replayed", results)
+	return nil
+}
+
+func discardFailedTasks(ctx context.Context, s *services.FailedTaskService, args []string) error {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+
+	results, err := s.DiscardFailedTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	printResults("discarded", results)
+	return nil
+}
+
+func printResults(action string, results []services.ReplayResult) {
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("%s#%d: %s%s\n", enums.Red.Value(), result.ID, result.Error, enums.Reset.Value())
+			continue
+		}
+		fmt.Printf("%s#%d %s (task id: %s)%s\n", enums.Green.Value(), result.ID, action, result.TaskID, enums.Reset.Value())
+	}
+}
+
+func parseIDs(args []string) ([]uint, error) {
+	ids := make([]uint, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil || id == 0 {
+			return nil, errors.New("invalid failed task id: " + arg)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
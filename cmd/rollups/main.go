@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"taskgo/bootstrap"
+	"taskgo/internal/deps"
+	"taskgo/internal/services"
+	"taskgo/pkg/enums"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Sales Rollups CLI
+|------------------------------------------
+|	go run ./cmd/rollups rebuild
+|	go run ./cmd/rollups apply <order id> [order id...]
+|------------------------------------------
+*/
+
+const usage = "Usage: rollups rebuild|apply [order ids...]"
+
+func main() {
+	bootstrap.NewAppBuilder(".env").
+		LoadConfig().
+		LoadLogger().
+		LoadDatabase().
+		Boot()
+
+	defer bootstrap.Shutdown()
+
+	if len(os.Args) < 2 {
+		fatal(usage)
+	}
+
+	salesRollupService := deps.App[*services.SalesRollupService]()
+	if salesRollupService == nil {
+		fatal("sales rollup service is not loaded")
+	}
+
+	ctx := context.Background()
+	command, args := os.Args[1], os.Args[2:]
+
+	switch command {
+	case "rebuild":
+		rebuildRollups(ctx, salesRollupService)
+	case "apply":
+		applyOrders(ctx, salesRollupService, args)
+	default:
+		fatal(usage)
+	}
+}
+
+func rebuildRollups(ctx context.Context, s *services.SalesRollupService) {
+	start := time.Now()
+	fmt.Printf("Rebuilding the sales rollups (time zone %s)...\n", s.Timezone())
+
+	err := s.Rebuild(ctx, func(applied int) {
+		fmt.Printf("  %d orders applied\n", applied)
+	})
+	if err != nil {
+		fatal(err.Error())
+	}
+
+	fmt.Printf("%sSales rollups rebuilt in %s%s\n", enums.Green.Value(), time.Since(start).Round(time.Millisecond), enums.Reset.Value())
+}
+
+func applyOrders(ctx context.Context, s *services.SalesRollupService, args []string) {
+	if len(args) == 0 {
+		fatal("Usage: rollups apply <order id> [order id...]")
+	}
+
+	for _, arg := range args {
+		var id uint
+		if _, err := fmt.Sscan(arg, &id); err != nil || id == 0 {
+			fatal("invalid order id: " + arg)
+		}
+
+		if err := s.ApplyOrder(ctx, id); err != nil {
+			fmt.Printf("%s#%d: %s%s\n", enums.Red.Value(), id, err.Error(), enums.Reset.Value())
+			continue
+		}
+		fmt.Printf("%s#%d applied%s\n", enums.Green.Value(), id, enums.Reset.Value())
+	}
+}
+
+func fatal(msg string) {
+	log.Fatal(enums.Red.Value() + msg + enums.Reset.Value())
+}
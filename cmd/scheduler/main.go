@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"taskgo/bootstrap"
+	"taskgo/internal/deps"
+	schedulerq "taskgo/pkg/asynq_scheduler"
+	"taskgo/pkg/enums"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Scheduler CLI
+|------------------------------------------
+|	go run ./cmd/scheduler list [count]   -> upcoming runs of the schedules defined in bootstrap/registers.go
+|------------------------------------------
+*/
+
+func main() {
+	bootstrap.NewAppBuilder(".env").
+		LoadConfig().
+		LoadLogger().
+		Boot()
+
+	if len(os.Args) < 2 || os.Args[1] != "list" {
+		log.Fatal(enums.Red.Value() + "Usage: scheduler list [count]" + enums.Reset.Value())
+		return
+	}
+
+	count := 1
+	if len(os.Args) > 2 {
+		n, err := strconv.Atoi(os.Args[2])
+		if err != nil || n <= 0 {
+			log.Fatal(enums.Red.Value() + "count must be a positive number" + enums.Reset.Value())
+			return
+		}
+		count = n
+	}
+
+	location, err := time.LoadLocation(deps.Config().GetString("queue.scheduler.timezone", "UTC"))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	runs, err := schedulerq.UpcomingRuns(bootstrap.GetRegisteredSchedules(), time.Now(), count, location)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No schedules registered")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NEXT RUN\tNAME\tTASK TYPE\tCRONSPEC")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", run.NextRun.Format(time.RFC3339), run.Name, run.TaskType, run.Cronspec)
+	}
+	w.Flush()
+}
@@ -33,7 +33,10 @@ func main() {
 		LoadRedisCache().
 		LoadRedisQueue().
 		LoadWebsocketServer().
+		LoadMailer().
+		LoadSMS().
 		LoadNotify().
+		LoadEvents().
 		Boot()
 
 	// Start the application (HTTP server)
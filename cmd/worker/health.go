@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"taskgo/internal/deps"
+	metricsq "taskgo/pkg/asynq_metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Worker health server
+|------------------------------------------
+|	GET /livez    -> the worker process is alive
+|	GET /readyz   -> redis (queue) and database are reachable (checked every health_check.interval)
+|	GET /metrics  -> tasks metrics in the prometheus text format
+|------------------------------------------
+*/
+
+// tasksMetrics is collected by wrapHandlerWithLogging
+var tasksMetrics = metricsq.NewCollector("taskgo_worker")
+
+// runHealthServer runs the worker health server until the context is cancelled
+func runHealthServer(ctx context.Context, redisOpt *redis.Options) <-chan struct{} {
+	done := make(chan struct{})
+	cfg := deps.Config()
+
+	if !cfg.GetBool("queue.consumer.health_check.enabled", true) {
+		close(done)
+		return done
+	}
+
+	address := cfg.GetString("queue.consumer.health_check.address", ":9090")
+	interval := cfg.GetDuration("queue.consumer.health_check.interval", 30*time.Second)
+
+	redisClient := redis.NewClient(redisOpt)
+	readiness := &metricsq.Readiness{}
+
+	srv := &http.Server{
+		Addr:              address,
+		Handler:           metricsq.NewHealthHandler(tasksMetrics, readiness),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Starting worker health server on %s ...", address)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println("Worker health server error:", err)
+		}
+	}()
+
+	go func() {
+		defer close(done)
+		defer redisClient.Close()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			readiness.Set(checkReadiness(ctx, redisClient))
+
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = srv.Shutdown(shutdownCtx)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+// checkReadiness checks the worker dependencies (redis, database)
+func checkReadiness(ctx context.Context, redisClient *redis.Client) (bool, map[string]string) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ready := true
+	checks := map[string]string{"redis": "ok", "database": "ok"}
+
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		ready, checks["redis"] = false, err.Error()
+	}
+
+	sqlDB, err := deps.Gorm().DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		ready, checks["database"] = false, err.Error()
+	}
+
+	return ready, checks
+}
@@ -7,7 +7,13 @@ import (
 	"log"
 	"strconv"
 	"taskgo/bootstrap"
+	"taskgo/internal/adapters"
 	"taskgo/internal/deps"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	limitq "taskgo/pkg/asynq_limit"
+	middlewareq "taskgo/pkg/asynq_middleware"
+	schedulerq "taskgo/pkg/asynq_scheduler"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -23,7 +29,11 @@ func main() {
 		LoadValidator().
 		LoadRedisCache().
 		LoadRedisQueue().
+		LoadWebsocketRelay().
+		LoadMailer().
+		LoadSMS().
 		LoadNotify().
+		LoadEvents().
 		Boot()
 
 	// Initialize and run task worker
@@ -56,16 +66,26 @@ func runTaskWorker() {
 
 	// Server configuration
 	serverConfig := asynq.Config{
-		Concurrency:    concurrency,
-		Queues:         queues,
-		RetryDelayFunc: asynq.DefaultRetryDelayFunc,
+		Concurrency: concurrency,
+		Queues:      queues,
+		// throttled tasks (over the concurrency / rate limits) are re-scheduled without counting a failure
+		// the others use constant, exponential or jitter backoff by task type / queue
+		IsFailure:      limitq.IsFailure,
+		RetryDelayFunc: limitq.RetryDelayFunc(tasks.RetryPolicies().RetryDelayFunc()),
 
 		ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+			// Get retry information from context
+			retryCount, _ := asynq.GetRetryCount(ctx)
+			maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+			// Throttled tasks are not failures, unless it's their last attempt
+			// (asynq archives the task once its retries are exhausted whatever IsFailure returns)
+			if limitq.IsRateLimitError(err) && retryCount < maxRetry {
+				return
+			}
+
 			// Check if we should log failed tasks
 			if cfg.GetBool("queue.consumer.logging.log_failed_tasks", true) {
-				// Get retry information from context
-				retryCount, _ := asynq.GetRetryCount(ctx)
-				maxRetry, _ := asynq.GetMaxRetry(ctx)
 				taskID, _ := asynq.GetTaskID(ctx)
 
 				deps.Log().Channel("queue_log").Error("Task processing failed",
@@ -76,20 +96,30 @@ func runTaskWorker() {
 					zap.Error(err),
 				)
 			}
+
+			// Persist the archived tasks (dead letter) so they can be replayed or discarded later
+			if failedTaskService := deps.App[*services.FailedTaskService](); failedTaskService != nil && cfg.GetBool("queue.dead_letter.enabled", true) {
+				if err := failedTaskService.RecordFailure(ctx, task, err); err != nil {
+					deps.Log().Channel("queue_log").Error("Failed to record failed task", zap.String("task_type", task.Type()), zap.Error(err))
+				}
+			}
 		}),
 	}
 
-	server := asynq.NewServer(asynq.RedisClientOpt{
+	asynqRedisOpt := asynq.RedisClientOpt{
 		Addr:      redisOpt.Addr,
 		Username:  redisOpt.Username,
 		Password:  redisOpt.Password,
 		DB:        redisOpt.DB,
 		PoolSize:  redisOpt.PoolSize,
 		TLSConfig: redisOpt.TLSConfig,
-	}, serverConfig)
+	}
+
+	server := asynq.NewServer(asynqRedisOpt, serverConfig)
 
 	// Register task handlers
 	mux := asynq.NewServeMux()
+	mux.Use(bootstrap.GetRegisteredTaskMiddleware()...)
 
 	// Get registered task handlers
 	handlers := bootstrap.GetRegisteredTaskHandlers()
@@ -105,21 +135,135 @@ func runTaskWorker() {
 	log.Printf("Starting task worker with %d concurrency...", concurrency)
 	log.Printf("Queue priorities: %+v", queues)
 
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+
+	// Run the recurring tasks scheduler (only the leader worker enqueues the tasks)
+	schedulerDone := runScheduler(backgroundCtx, asynqRedisOpt, redisOpt)
+
+	// Run the health server (liveness, readiness and metrics)
+	healthDone := runHealthServer(backgroundCtx, redisOpt)
+
+	// Run the outbox relay (enqueues the tasks written to the outbox and not enqueued yet)
+	outboxDone := runOutboxRelay(backgroundCtx)
+
 	// Run server (blocking) - asynq handles graceful shutdown internally
 	if err := server.Run(mux); err != nil {
 		log.Fatal("Task server error:", err)
 	}
+
+	stopBackground()
+	<-schedulerDone
+	<-healthDone
+	<-outboxDone
 }
 
+// runScheduler runs the leader safe recurring tasks scheduler in the background
+func runScheduler(ctx context.Context, asynqRedisOpt asynq.RedisClientOpt, redisOpt *redis.Options) <-chan struct{} {
+	done := make(chan struct{})
+	cfg := deps.Config()
+
+	entries := bootstrap.GetRegisteredSchedules()
+	if !cfg.GetBool("queue.scheduler.enabled", true) || len(entries) == 0 {
+		log.Printf("Recurring tasks scheduler is disabled or has no schedules")
+		close(done)
+		return done
+	}
+
+	location, err := time.LoadLocation(cfg.GetString("queue.scheduler.timezone", "UTC"))
+	if err != nil {
+		log.Fatal("Invalid scheduler timezone:", err)
+	}
+
+	lockClient := redis.NewClient(redisOpt)
+
+	scheduler := schedulerq.New(asynqRedisOpt, lockClient, adapters.NewLoggerAdapter(deps.Log().Channel("queue_log")), schedulerq.Options{
+		Location: location,
+		LockTTL:  cfg.GetDuration("queue.scheduler.leader_lock_ttl", 30*time.Second),
+	})
+
+	if err := scheduler.Register(entries...); err != nil {
+		log.Fatal("Failed to register schedules:", err)
+	}
+
+	log.Printf("Starting recurring tasks scheduler with %d schedules...", len(entries))
+
+	go func() {
+		defer close(done)
+		defer lockClient.Close()
+		scheduler.Run(ctx)
+	}()
+
+	return done
+}
+
+// runOutboxRelay enqueues the pending outbox messages in the background (safe to run in all the workers)
+func runOutboxRelay(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	cfg := deps.Config()
+
+	outboxService := deps.App[*services.OutboxService]()
+	if !cfg.GetBool("queue.outbox.relay_enabled", true) || outboxService == nil {
+		log.Printf("Outbox relay is disabled")
+		close(done)
+		return done
+	}
+
+	interval := cfg.GetDuration("queue.outbox.poll_interval", 2*time.Second)
+	batchSize := cfg.GetInt("queue.outbox.batch_size", 100)
+
+	log.Printf("Starting outbox relay every %s...", interval)
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// Keep relaying while the batches are full
+			dispatched, err := outboxService.Relay(ctx, batchSize)
+			if err != nil {
+				deps.Log().Channel("queue_log").Error("Outbox relay failed", zap.Error(err))
+			}
+
+			if err == nil && dispatched == batchSize {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+var errTaskPanic = errors.New("task handler panic")
+
 // Wrap handler with logging based on configuration
 func wrapHandlerWithLogging(handler asynq.Handler, taskType string) asynq.Handler {
 	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
 		start := time.Now()
+		done := tasksMetrics.Start(taskType)
+
+		// Count the task as failed if the handler panics (recovered by the middleware)
+		finished := false
+		defer func() {
+			if !finished {
+				done(errTaskPanic)
+			}
+		}()
 
 		// Execute the actual handler
-		err := handler.ProcessTask(ctx, task)
+		queue, _ := asynq.GetQueueName(ctx)
+		err := tasks.RetryPolicies().Classify(queue, handler.ProcessTask(ctx, task))
 
 		duration := time.Since(start)
+		finished = true
+		done(err)
 
 		if err != nil {
 			// Error logging is handled by ErrorHandler
@@ -133,6 +277,7 @@ func wrapHandlerWithLogging(handler asynq.Handler, taskType string) asynq.Handle
 			deps.Log().Channel("queue_log").Info("Task completed successfully",
 				zap.String("task_type", taskType),
 				zap.String("task_id", taskID),
+				zap.String("trace_id", middlewareq.TraceID(ctx)),
 				zap.Duration("duration", duration),
 			)
 		}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminChainHandler struct {
+	Handler
+	chainService *services.ChainService
+}
+
+// NewAdminChainHandler return a new AdminChainHandler
+func NewAdminChainHandler(chainService *services.ChainService) *AdminChainHandler {
+	return &AdminChainHandler{
+		chainService: chainService,
+	}
+}
+
+// @Summary     Get chain state
+// @Description Get the control state of a task chain (running, paused, cancelled)
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      string                          true  "Chain ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Chain state retrieved successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/{id} [get]
+func (h *AdminChainHandler) GetChainState(gin *gin.Context) error {
+	chainID := gin.Param("id")
+
+	state, allPaused, err := h.chainService.GetChainState(gin.Request.Context(), chainID)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Chain state retrieved successfully", map[string]any{
+		"chain_id":   chainID,
+		"state":      state,
+		"all_paused": allPaused,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Cancel chain
+// @Description Cancel a task chain, the remaining steps will not run
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      string                          true  "Chain ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Chain cancelled successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/{id}/cancel [put]
+func (h *AdminChainHandler) CancelChain(gin *gin.Context) error {
+	chainID := gin.Param("id")
+
+	if err := h.chainService.CancelChain(gin.Request.Context(), chainID); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Chain cancelled successfully", map[string]any{"chain_id": chainID}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Cancel order processing chain
+// @Description Cancel the processing chain of an order, the remaining steps (e.g. payment) will not run
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Order ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Order processing chain cancelled successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/orders/{id}/chain/cancel [put]
+func (h *AdminChainHandler) CancelOrderChain(gin *gin.Context) error {
+	orderID, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequestError("Invalid order id", "BadRequestError: Failed to parse order id", err)
+	}
+
+	chainID := tasks.OrderProcessingChainID(uint(orderID))
+	if err := h.chainService.CancelChain(gin.Request.Context(), chainID); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Order processing chain cancelled successfully", map[string]any{
+		"order_id": orderID,
+		"chain_id": chainID,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Pause chain
+// @Description Pause a task chain before running its next step
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      string                          true  "Chain ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Chain paused successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/{id}/pause [put]
+func (h *AdminChainHandler) PauseChain(gin *gin.Context) error {
+	chainID := gin.Param("id")
+
+	if err := h.chainService.PauseChain(gin.Request.Context(), chainID); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Chain paused successfully", map[string]any{"chain_id": chainID}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Resume chain
+// @Description Resume a paused task chain
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      string                          true  "Chain ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Chain resumed successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/{id}/resume [put]
+func (h *AdminChainHandler) ResumeChain(gin *gin.Context) error {
+	chainID := gin.Param("id")
+
+	if err := h.chainService.ResumeChain(gin.Request.Context(), chainID); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Chain resumed successfully", map[string]any{"chain_id": chainID}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Pause all chains
+// @Description Pause all the task chains (e.g. during payment provider outage)
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "All chains paused successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/pause [put]
+func (h *AdminChainHandler) PauseAllChains(gin *gin.Context) error {
+	if err := h.chainService.PauseAllChains(gin.Request.Context()); err != nil {
+		return err
+	}
+
+	response.Json(gin, "All chains paused successfully", nil, http.StatusOK)
+	return nil
+}
+
+// @Summary     Resume all chains
+// @Description Resume all the task chains paused by pause all
+// @Tags        Admin Chains
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "All chains resumed successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/chains/resume [put]
+func (h *AdminChainHandler) ResumeAllChains(gin *gin.Context) error {
+	if err := h.chainService.ResumeAllChains(gin.Request.Context()); err != nil {
+		return err
+	}
+
+	response.Json(gin, "All chains resumed successfully", nil, http.StatusOK)
+	return nil
+}
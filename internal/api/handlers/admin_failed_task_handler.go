@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/filters"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminFailedTaskHandler struct {
+	Handler
+	failedTaskService *services.FailedTaskService
+}
+
+// NewAdminFailedTaskHandler return a new AdminFailedTaskHandler
+func NewAdminFailedTaskHandler(failedTaskService *services.FailedTaskService) *AdminFailedTaskHandler {
+	return &AdminFailedTaskHandler{
+		failedTaskService: failedTaskService,
+	}
+}
+
+// @Summary     List failed tasks
+// @Description Retrieves a paginated list of the dead-lettered tasks (latest failures first)
+// @Tags        Admin Failed Tasks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request    query     filters.FailedTaskFilters        true  "Filter and pagination"
+//
+// @Success     200        {object}  response.SuccessResponse         "Failed tasks retrieved successfully"
+// @Failure     400        {object}  response.BadRequestResponse      "Bad Request"
+// @Failure     401        {object}  response.UnauthorizedResponse    "Unauthorized Action"
+// @Failure     500        {object}  response.ServerErrorResponse     "Internal Server Error"
+//
+// @Router      /admin/failed-tasks [get]
+func (h *AdminFailedTaskHandler) ListFailedTasks(gin *gin.Context) error {
+	var failedTaskFilters filters.FailedTaskFilters
+
+	// Bind URL query parameters to filters struct
+	if err := gin.ShouldBindQuery(&failedTaskFilters); err != nil {
+		return errors.NewBadRequestError("", "BadRequestError: Failed to bind URL query parameters to filters struct", err)
+	}
+
+	failedTasks, total, err := h.failedTaskService.GetPaginatedFailedTasks(gin.Request.Context(), &failedTaskFilters)
+	if err != nil {
+		return err
+	}
+
+	var totalPages int
+	if failedTaskFilters.PerPage > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(failedTaskFilters.PerPage)))
+	}
+
+	data := make([]map[string]any, len(failedTasks))
+	for i, failedTask := range failedTasks {
+		data[i] = failedTaskData(failedTask)
+	}
+
+	response.Json(gin, "Failed tasks retrieved successfully", map[string]any{
+		"failed_tasks": data,
+		"meta": map[string]any{
+			"total":       total,
+			"page":        failedTaskFilters.Page,
+			"limit":       failedTaskFilters.PerPage,
+			"total_pages": totalPages,
+			"next_page":   failedTaskFilters.Page + 1,
+			"prev_page":   failedTaskFilters.Page - 1,
+		},
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Get failed task
+// @Description Get a dead-lettered task with its payload, error chain and attempts history
+// @Tags        Admin Failed Tasks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Failed task ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Failed task retrieved successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Failed task not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/failed-tasks/{id} [get]
+func (h *AdminFailedTaskHandler) GetFailedTask(gin *gin.Context) error {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequestError("Invalid failed task id", "BadRequestError: invalid failed task id", err)
+	}
+
+	failedTask, err := h.failedTaskService.GetFailedTaskById(gin.Request.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Failed task retrieved successfully", map[string]any{
+		"failed_task": failedTaskData(failedTask),
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Replay failed tasks
+// @Description Enqueue the archived failed tasks again, the payload can be edited when replaying a single task.
+// @Description Failed chain steps are replayed from the failed step.
+// @Tags        Admin Failed Tasks
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  body      requests.ReplayFailedTasksRequest   true  "Replay failed tasks request body"
+//
+// @Success     200      {object}  response.SuccessResponse            "Failed tasks replayed"
+// @Failure     400      {object}  response.BadRequestResponse         "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse       "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse           "Failed tasks not found"
+// @Failure     422      {object}  response.ValidationErrorResponse    "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse        "Internal Server Error"
+//
+// @Router      /admin/failed-tasks/replay [post]
+func (h *AdminFailedTaskHandler) ReplayFailedTasks(gin *gin.Context) error {
+	var req requests.ReplayFailedTasksRequest
+
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	results, err := h.failedTaskService.ReplayFailedTasks(gin.Request.Context(), req.IDs, req.Payload)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Failed tasks replayed", map[string]any{
+		"results": results,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Discard failed tasks
+// @Description Remove the archived failed tasks from the queue, they will never run again
+// @Tags        Admin Failed Tasks
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  body      requests.DiscardFailedTasksRequest  true  "Discard failed tasks request body"
+//
+// @Success     200      {object}  response.SuccessResponse            "Failed tasks discarded"
+// @Failure     400      {object}  response.BadRequestResponse         "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse       "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse           "Failed tasks not found"
+// @Failure     422      {object}  response.ValidationErrorResponse    "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse        "Internal Server Error"
+//
+// @Router      /admin/failed-tasks/discard [post]
+func (h *AdminFailedTaskHandler) DiscardFailedTasks(gin *gin.Context) error {
+	var req requests.DiscardFailedTasksRequest
+
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	results, err := h.failedTaskService.DiscardFailedTasks(gin.Request.Context(), req.IDs)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Failed tasks discarded", map[string]any{
+		"results": results,
+	}, http.StatusOK)
+	return nil
+}
+
+// failedTaskData returns the failed task with its json columns decoded
+func failedTaskData(failedTask *models.FailedTask) map[string]any {
+	return map[string]any{
+		"id":               failedTask.ID,
+		"task_id":          failedTask.TaskID,
+		"task_type":        failedTask.TaskType,
+		"queue":            failedTask.Queue,
+		"payload":          rawJson(failedTask.Payload),
+		"payload_encoding": failedTask.PayloadEncoding,
+		"last_error":       failedTask.LastError,
+		"error_chain":      rawJson(failedTask.ErrorChain),
+		"attempts":         rawJson(failedTask.Attempts),
+		"retry_count":      failedTask.RetryCount,
+		"max_retry":        failedTask.MaxRetry,
+		"chain_id":         failedTask.ChainID,
+		"chain_step":       failedTask.ChainStep,
+		"status":           failedTask.Status,
+		"replay_count":     failedTask.ReplayCount,
+		"replayed_task_id": failedTask.ReplayedTaskID,
+		"failed_at":        failedTask.FailedAt,
+		"replayed_at":      failedTask.ReplayedAt,
+		"discarded_at":     failedTask.DiscardedAt,
+	}
+}
+
+func rawJson(value string) json.RawMessage {
+	if value == "" || !json.Valid([]byte(value)) {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(value)
+}
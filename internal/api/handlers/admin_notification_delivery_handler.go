@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"taskgo/internal/database/models"
+	"taskgo/internal/filters"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminNotificationDeliveryHandler struct {
+	Handler
+	notificationDeliveryService *services.NotificationDeliveryService
+}
+
+// NewAdminNotificationDeliveryHandler return a new AdminNotificationDeliveryHandler
+func NewAdminNotificationDeliveryHandler(notificationDeliveryService *services.NotificationDeliveryService) *AdminNotificationDeliveryHandler {
+	return &AdminNotificationDeliveryHandler{
+		notificationDeliveryService: notificationDeliveryService,
+	}
+}
+
+// @Summary     List notification deliveries
+// @Description Retrieves the notifications deliveries per channel (filter with status=failed for the failed ones)
+// @Tags        Admin Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  query     filters.NotificationDeliveryFilters  true  "Filter and pagination"
+//
+// @Success     200      {object}  response.SuccessResponse             "Notification deliveries retrieved successfully"
+// @Failure     400      {object}  response.BadRequestResponse          "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse        "Unauthorized Action"
+// @Failure     500      {object}  response.ServerErrorResponse         "Internal Server Error"
+//
+// @Router      /admin/notifications/deliveries [get]
+func (h *AdminNotificationDeliveryHandler) ListDeliveries(gin *gin.Context) error {
+	var deliveryFilters filters.NotificationDeliveryFilters
+
+	// Bind URL query parameters to filters struct
+	if err := gin.ShouldBindQuery(&deliveryFilters); err != nil {
+		return errors.NewBadRequestError("", "BadRequestError: Failed to bind URL query parameters to filters struct", err)
+	}
+
+	deliveries, total, err := h.notificationDeliveryService.GetPaginatedDeliveries(gin.Request.Context(), &deliveryFilters)
+	if err != nil {
+		return err
+	}
+
+	data := make([]map[string]any, len(deliveries))
+	for i, delivery := range deliveries {
+		data[i] = deliveryData(delivery)
+	}
+
+	var totalPages int
+	if deliveryFilters.PerPage > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(deliveryFilters.PerPage)))
+	}
+
+	response.Json(gin, "Notification deliveries retrieved successfully", map[string]any{
+		"deliveries": data,
+		"meta": map[string]any{
+			"total":       total,
+			"page":        deliveryFilters.Page,
+			"limit":       deliveryFilters.PerPage,
+			"total_pages": totalPages,
+			"next_page":   deliveryFilters.Page + 1,
+			"prev_page":   deliveryFilters.Page - 1,
+		},
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Resend notification delivery
+// @Description Dispatches the failed notification delivery again on its channel with its recorded data
+// @Tags        Admin Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                                true  "Delivery ID"
+//
+// @Success     200  {object}  response.SuccessResponse           "Notification delivery resent successfully"
+// @Failure     400  {object}  response.BadRequestResponse        "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse      "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse          "Delivery not found"
+// @Failure     422  {object}  response.ValidationErrorResponse   "Delivery is not failed"
+// @Failure     500  {object}  response.ServerErrorResponse       "Internal Server Error"
+//
+// @Router      /admin/notifications/deliveries/{id}/resend [post]
+func (h *AdminNotificationDeliveryHandler) ResendDelivery(gin *gin.Context) error {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequestError("Invalid delivery id", "BadRequestError: invalid delivery id", err)
+	}
+
+	delivery, err := h.notificationDeliveryService.Resend(gin.Request.Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Notification delivery resent successfully", map[string]any{
+		"delivery": deliveryData(delivery),
+	}, http.StatusOK)
+	return nil
+}
+
+// deliveryData returns the delivery with its data decoded
+func deliveryData(delivery *models.NotificationDelivery) map[string]any {
+	return map[string]any{
+		"id":                delivery.ID,
+		"notification_id":   delivery.NotificationID,
+		"notification_type": delivery.NotificationType,
+		"notifiable_type":   delivery.NotifiableType,
+		"notifiable_id":     delivery.NotifiableID,
+		"channel":           delivery.Channel,
+		"status":            delivery.Status,
+		"attempts":          delivery.Attempts,
+		"redeliveries":      delivery.Redeliveries,
+		"last_error":        delivery.LastError,
+		"sent_at":           delivery.SentAt,
+		"failed_at":         delivery.FailedAt,
+		"data":              rawJson(delivery.Data),
+		"created_at":        delivery.CreatedAt,
+		"updated_at":        delivery.UpdatedAt,
+	}
+}
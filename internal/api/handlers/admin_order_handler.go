@@ -1,6 +1,14 @@
 package handlers
 
 import (
+	"net/http"
+	"strconv"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
 	"taskgo/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +16,17 @@ import (
 
 // TODO implement this handler
 type AdminOrderHandler struct {
+	Handler
+	orderService  *services.OrderService
+	reportService *services.ReportService
+}
+
+// NewAdminOrderHandler return a new AdminOrderHandler
+func NewAdminOrderHandler(orderService *services.OrderService, reportService *services.ReportService) *AdminOrderHandler {
+	return &AdminOrderHandler{
+		orderService:  orderService,
+		reportService: reportService,
+	}
 }
 
 func (h *AdminOrderHandler) ListAllOrders(c *gin.Context) {
@@ -24,19 +43,84 @@ func (h *AdminOrderHandler) GetOrderDetails(c *gin.Context) {
 	}, 200)
 }
 
-func (h *AdminOrderHandler) UpdateOrderStatus(c *gin.Context) {
-	response.Json(c, "Order status updated successfully", gin.H{
-		"order_id": c.Param("id"),
-		"status":   "Shipped",
-	}, 200)
+// @Summary     Update order status
+// @Description Move the order to a new status (only the allowed transitions, e.g. shipped -> delivered)
+// @Tags        Admin Orders
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id       path      int                                 true  "Order ID"
+// @Param       request  body      requests.UpdateOrderStatusRequest   true  "Update order status request body"
+//
+// @Success     200      {object}  response.SuccessResponse            "Order status updated successfully"
+// @Failure     400      {object}  response.BadRequestResponse         "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse       "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse           "Order not found"
+// @Failure     422      {object}  response.ValidationErrorResponse    "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse        "Internal Server Error"
+//
+// @Router      /admin/orders/{id}/status [put]
+func (h *AdminOrderHandler) UpdateOrderStatus(gin *gin.Context) error {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequestError("Invalid order id", "BadRequestError: invalid order id", err)
+	}
+
+	var req requests.UpdateOrderStatusRequest
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	order, err := h.orderService.UpdateStatus(gin.Request.Context(), uint(id), enums.OrderStatus(req.Status), req.Reason)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Order status updated successfully", map[string]any{
+		"order_id": order.ID,
+		"status":   order.Status,
+	}, http.StatusOK)
+	return nil
 }
 
-func (h *AdminOrderHandler) DailySalesReport(c *gin.Context) {
-	response.Json(c, "Daily sales report generated successfully", gin.H{
-		"date":   "2023-10-01",
-		"sales":  1500.00,
-		"orders": 30,
-	}, 200)
+// @Summary     Sales report
+// @Description Revenue, orders count, average order value, units per product and category, cancellations and refunds
+// @Description of the orders created in the date range, grouped by day, week or month in the report time zone
+// @Tags        Admin Reports
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  query     filters.SalesReportFilters        true  "Report range and grouping"
+//
+// @Success     200      {object}  response.SuccessResponse          "Sales report generated successfully"
+// @Failure     400      {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     422      {object}  response.ValidationErrorResponse  "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/reports/daily [get]
+func (h *AdminOrderHandler) DailySalesReport(gin *gin.Context) error {
+	var reportFilters filters.SalesReportFilters
+
+	// Bind URL query parameters to filters struct
+	if err := gin.ShouldBindQuery(&reportFilters); err != nil {
+		return errors.NewBadRequestError("", "BadRequestError: Failed to bind URL query parameters to filters struct", err)
+	}
+
+	report, err := h.reportService.SalesReport(gin.Request.Context(), &reportFilters)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Sales report generated successfully", map[string]any{
+		"report": report,
+	}, http.StatusOK)
+	return nil
 }
 
 func (h *AdminOrderHandler) LowStockAlerts(c *gin.Context) {
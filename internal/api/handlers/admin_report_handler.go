@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/helpers"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	chainq "taskgo/pkg/asynq_chain"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminReportHandler struct {
+	Handler
+	reportService *services.ReportService
+}
+
+// NewAdminReportHandler return a new AdminReportHandler
+func NewAdminReportHandler(reportService *services.ReportService) *AdminReportHandler {
+	return &AdminReportHandler{
+		reportService: reportService,
+	}
+}
+
+// @Summary     Create report job
+// @Description Queue a report generated by the workers (low queue) and exported as CSV or XLSX.
+// @Description Poll the report job or wait for the report notification (database, ws) then download the file.
+// @Tags        Admin Reports
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  body      requests.CreateReportJobRequest   true  "Create report job request body"
+//
+// @Success     202      {object}  response.SuccessResponse          "Report queued"
+// @Failure     400      {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     422      {object}  response.ValidationErrorResponse  "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/reports [post]
+func (h *AdminReportHandler) CreateReportJob(gin *gin.Context) error {
+	var req requests.CreateReportJobRequest
+
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	authUser, authorizeErr := helpers.GetAuthUser(gin)
+	if authorizeErr != nil {
+		return authorizeErr
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	job, err := h.reportService.CreateReportJob(gin.Request.Context(), authUser.ID, &req, func(job *models.ReportJob) (*chainq.TaskMessage, error) {
+		return tasks.NewGenerateReportTask(job.ID).Message()
+	})
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Report queued", map[string]any{
+		"report": reportJobData(job),
+	}, http.StatusAccepted)
+	return nil
+}
+
+// @Summary     Get report job
+// @Description Get the report job status (queued, running, done, failed) and its download url once done
+// @Tags        Admin Reports
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Report job ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Report retrieved successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Report not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/reports/{id} [get]
+func (h *AdminReportHandler) GetReportJob(gin *gin.Context) error {
+	id, err := reportJobID(gin)
+	if err != nil {
+		return err
+	}
+
+	job, err := h.reportService.GetReportJobById(gin.Request.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Report retrieved successfully", map[string]any{
+		"report": reportJobData(job),
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Download report
+// @Description Download the file of a done report job
+// @Tags        Admin Reports
+// @Produce     octet-stream
+// @Security    BearerAuth
+//
+// @Param       id   path      int                               true  "Report job ID"
+//
+// @Success     200  {file}    file                              "Report file"
+// @Failure     400  {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse         "Report not found"
+// @Failure     422  {object}  response.ValidationErrorResponse  "Report is not done"
+// @Failure     500  {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/reports/{id}/download [get]
+func (h *AdminReportHandler) DownloadReport(gin *gin.Context) error {
+	id, err := reportJobID(gin)
+	if err != nil {
+		return err
+	}
+
+	job, file, err := h.reportService.OpenReportFile(gin.Request.Context(), id)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	contentType := "text/csv"
+	if job.Format == enums.ReportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	gin.Header("Content-Type", contentType)
+	gin.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, job.FileName))
+	gin.Header("Content-Length", strconv.FormatInt(job.FileSize, 10))
+	gin.Status(http.StatusOK)
+	_, _ = io.Copy(gin.Writer, file)
+	return nil
+}
+
+func reportJobID(gin *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError("Invalid report id", "BadRequestError: invalid report id", err)
+	}
+	return uint(id), nil
+}
+
+// reportJobData returns the report job with its params decoded and its download url once done
+func reportJobData(job *models.ReportJob) map[string]any {
+	data := map[string]any{
+		"id":          job.ID,
+		"type":        job.Type,
+		"format":      job.Format,
+		"params":      rawJson(job.Params),
+		"status":      job.Status,
+		"attempts":    job.Attempts,
+		"error":       job.Error,
+		"created_at":  job.CreatedAt,
+		"started_at":  job.StartedAt,
+		"finished_at": job.FinishedAt,
+	}
+
+	if job.Status == enums.ReportJobStatusDone {
+		data["file_name"] = job.FileName
+		data["file_size"] = job.FileSize
+		data["download_url"] = fmt.Sprintf("/api/v1/admin/reports/%d/download", job.ID)
+	}
+	return data
+}
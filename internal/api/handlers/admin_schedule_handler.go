@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"taskgo/internal/services"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminScheduleHandler struct {
+	Handler
+	scheduleService *services.ScheduleService
+}
+
+// NewAdminScheduleHandler return a new AdminScheduleHandler
+func NewAdminScheduleHandler(scheduleService *services.ScheduleService) *AdminScheduleHandler {
+	return &AdminScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// @Summary     List schedules
+// @Description List the recurring tasks registered by the workers scheduler with their next run
+// @Tags        Admin Schedules
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "Schedules retrieved successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/schedules [get]
+func (h *AdminScheduleHandler) ListSchedules(gin *gin.Context) error {
+	runs, err := h.scheduleService.ListUpcomingRuns()
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Schedules retrieved successfully", map[string]any{
+		"schedules": runs,
+	}, http.StatusOK)
+	return nil
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/filters"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminWebhookHandler struct {
+	Handler
+	webhookService *services.WebhookService
+}
+
+// NewAdminWebhookHandler return a new AdminWebhookHandler
+func NewAdminWebhookHandler(webhookService *services.WebhookService) *AdminWebhookHandler {
+	return &AdminWebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// @Summary     List webhooks
+// @Description Retrieves all the partners webhook subscriptions
+// @Tags        Admin Webhooks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "Webhooks retrieved successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/webhooks [get]
+func (h *AdminWebhookHandler) ListWebhooks(gin *gin.Context) error {
+	subscriptions, err := h.webhookService.GetSubscriptions(gin.Request.Context())
+	if err != nil {
+		return err
+	}
+
+	data := make([]map[string]any, len(subscriptions))
+	for i, subscription := range subscriptions {
+		data[i] = webhookData(subscription)
+	}
+
+	response.Json(gin, "Webhooks retrieved successfully", map[string]any{
+		"webhooks": data,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Create webhook
+// @Description Subscribe a partner endpoint to the events (e.g. order.shipped, order.* or *).
+// @Description The signing secret is generated if not provided, it's only returned on creation.
+// @Tags        Admin Webhooks
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  body      requests.CreateWebhookRequest     true  "Create webhook request body"
+//
+// @Success     201      {object}  response.SuccessResponse          "Webhook created successfully"
+// @Failure     400      {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     422      {object}  response.ValidationErrorResponse  "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/webhooks [post]
+func (h *AdminWebhookHandler) CreateWebhook(gin *gin.Context) error {
+	var req requests.CreateWebhookRequest
+
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(gin.Request.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	data := webhookData(subscription)
+	data["secret"] = subscription.Secret
+
+	response.Json(gin, "Webhook created successfully", map[string]any{
+		"webhook": data,
+	}, http.StatusCreated)
+	return nil
+}
+
+// @Summary     Get webhook
+// @Description Get a webhook subscription
+// @Tags        Admin Webhooks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Webhook ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Webhook retrieved successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Webhook not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/webhooks/{id} [get]
+func (h *AdminWebhookHandler) GetWebhook(gin *gin.Context) error {
+	id, err := webhookID(gin)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := h.webhookService.GetSubscriptionById(gin.Request.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Webhook retrieved successfully", map[string]any{
+		"webhook": webhookData(subscription),
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Update webhook
+// @Description Update the sent fields of a webhook subscription, set active to true to enable a disabled webhook
+// @Tags        Admin Webhooks
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id       path      int                               true  "Webhook ID"
+// @Param       request  body      requests.UpdateWebhookRequest     true  "Update webhook request body"
+//
+// @Success     200      {object}  response.SuccessResponse          "Webhook updated successfully"
+// @Failure     400      {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse         "Webhook not found"
+// @Failure     422      {object}  response.ValidationErrorResponse  "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/webhooks/{id} [put]
+func (h *AdminWebhookHandler) UpdateWebhook(gin *gin.Context) error {
+	id, err := webhookID(gin)
+	if err != nil {
+		return err
+	}
+
+	var req requests.UpdateWebhookRequest
+
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(gin.Request.Context(), id, &req)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Webhook updated successfully", map[string]any{
+		"webhook": webhookData(subscription),
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Delete webhook
+// @Description Delete a webhook subscription, its pending deliveries are skipped
+// @Tags        Admin Webhooks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Webhook ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Webhook deleted successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Webhook not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /admin/webhooks/{id} [delete]
+func (h *AdminWebhookHandler) DeleteWebhook(gin *gin.Context) error {
+	id, err := webhookID(gin)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhookService.DeleteSubscription(gin.Request.Context(), id); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Webhook deleted successfully", nil, http.StatusOK)
+	return nil
+}
+
+// @Summary     List webhook deliveries
+// @Description Retrieves the delivery log of a webhook subscription (latest attempts first)
+// @Tags        Admin Webhooks
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id       path      int                               true  "Webhook ID"
+// @Param       request  query     filters.WebhookDeliveryFilters    true  "Filter and pagination"
+//
+// @Success     200      {object}  response.SuccessResponse          "Webhook deliveries retrieved successfully"
+// @Failure     400      {object}  response.BadRequestResponse       "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse     "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse         "Webhook not found"
+// @Failure     500      {object}  response.ServerErrorResponse      "Internal Server Error"
+//
+// @Router      /admin/webhooks/{id}/deliveries [get]
+func (h *AdminWebhookHandler) ListWebhookDeliveries(gin *gin.Context) error {
+	id, err := webhookID(gin)
+	if err != nil {
+		return err
+	}
+
+	var deliveryFilters filters.WebhookDeliveryFilters
+
+	// Bind URL query parameters to filters struct
+	if err := gin.ShouldBindQuery(&deliveryFilters); err != nil {
+		return errors.NewBadRequestError("", "BadRequestError: Failed to bind URL query parameters to filters struct", err)
+	}
+
+	deliveries, total, err := h.webhookService.GetPaginatedDeliveries(gin.Request.Context(), id, &deliveryFilters)
+	if err != nil {
+		return err
+	}
+
+	var totalPages int
+	if deliveryFilters.PerPage > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(deliveryFilters.PerPage)))
+	}
+
+	response.Json(gin, "Webhook deliveries retrieved successfully", map[string]any{
+		"deliveries": deliveries,
+		"meta": map[string]any{
+			"total":       total,
+			"page":        deliveryFilters.Page,
+			"limit":       deliveryFilters.PerPage,
+			"total_pages": totalPages,
+			"next_page":   deliveryFilters.Page + 1,
+			"prev_page":   deliveryFilters.Page - 1,
+		},
+	}, http.StatusOK)
+	return nil
+}
+
+func webhookID(gin *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError("Invalid webhook id", "BadRequestError: invalid webhook id", err)
+	}
+	return uint(id), nil
+}
+
+// webhookData returns the webhook subscription with its events decoded (the secret is never listed)
+func webhookData(subscription *models.WebhookSubscription) map[string]any {
+	return map[string]any{
+		"id":               subscription.ID,
+		"name":             subscription.Name,
+		"url":              subscription.URL,
+		"events":           rawJson(subscription.Events),
+		"active":           subscription.Active,
+		"failure_count":    subscription.FailureCount,
+		"disabled_at":      subscription.DisabledAt,
+		"disabled_reason":  subscription.DisabledReason,
+		"last_delivery_at": subscription.LastDeliveryAt,
+		"created_at":       subscription.CreatedAt,
+		"updated_at":       subscription.UpdatedAt,
+	}
+}
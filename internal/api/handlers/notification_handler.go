@@ -5,6 +5,7 @@ import (
 	"taskgo/internal/deps"
 	"taskgo/internal/helpers"
 	"taskgo/internal/notification"
+	notificationHandlers "taskgo/internal/notification/handlers"
 	"taskgo/pkg/errors"
 	"taskgo/pkg/response"
 	"taskgo/pkg/ws"
@@ -49,12 +50,10 @@ func (h *NotificationHandler) WsUserNotificationHandler(c *gin.Context) {
 	}
 
 	// Create new websocket client
-	client := &ws.Client{
-		Conn:   conn,
-		UserID: userId,
-		Send:   make(chan []byte, 256),
-		Subs:   make(map[string]bool),
-	}
+	client := h.ws.Hub.NewClient(conn, userId)
+
+	// The client receives its notifications without subscribing
+	h.ws.Hub.Subscribe(client, notificationHandlers.UserChannel(userId))
 
 	client.Listen(h.ws.Hub)
 }
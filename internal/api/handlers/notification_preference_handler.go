@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/helpers"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationPreferenceHandler struct {
+	Handler
+	notificationPreferenceService *services.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceHandler return a new NotificationPreferenceHandler
+func NewNotificationPreferenceHandler(notificationPreferenceService *services.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		notificationPreferenceService: notificationPreferenceService,
+	}
+}
+
+// @Summary     List notifications preferences
+// @Description Retrieves the authenticated user channels and quiet hours of every notification type
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "Notifications preferences retrieved successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /notifications/preferences [get]
+func (h *NotificationPreferenceHandler) ListPreferences(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := h.notificationPreferenceService.Preferences(gin.Request.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	data := make([]map[string]any, len(preferences))
+	for i, preference := range preferences {
+		data[i] = map[string]any{
+			"type":      preference.Type,
+			"mandatory": preference.Type.Mandatory(),
+			"channels": map[string]bool{
+				"database": preference.Database,
+				"ws":       preference.WebSocket,
+				"email":    preference.Email,
+				"sms":      preference.SMS,
+			},
+			"quiet_hours_start": preference.QuietHoursStart,
+			"quiet_hours_end":   preference.QuietHoursEnd,
+			"timezone":          preference.Timezone,
+		}
+	}
+
+	response.Json(gin, "Notifications preferences retrieved successfully", map[string]any{
+		"preferences": data,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Update notification preference
+// @Description Toggles the channels and sets the quiet hours (sms and email are delayed to their end) of a notification type, the mandatory types can't be changed
+// @Tags        Notifications
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       type     path      string                                         true  "Notification type (order, payment, system, inventory)"
+// @Param       request  body      requests.UpdateNotificationPreferenceRequest   true  "Preference changes"
+//
+// @Success     200      {object}  response.SuccessResponse                       "Notification preference updated successfully"
+// @Failure     400      {object}  response.BadRequestResponse                    "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse                  "Unauthorized Action"
+// @Failure     404      {object}  response.NotFoundResponse                      "Notification type not found"
+// @Failure     422      {object}  response.ValidationErrorResponse               "Validation Error"
+// @Failure     500      {object}  response.ServerErrorResponse                   "Internal Server Error"
+//
+// @Router      /notifications/preferences/{type} [put]
+func (h *NotificationPreferenceHandler) UpdatePreference(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	var req requests.UpdateNotificationPreferenceRequest
+	if err := h.BindBodyAndExtractToRequest(gin, &req); err != nil {
+		return errors.NewBadRequestBindingError("", "BadRequestBindingError: Failed to bind request body to request struct", err)
+	}
+
+	if err := deps.Validator().ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	preference, err := h.notificationPreferenceService.UpdatePreference(gin.Request.Context(), user, enums.NotificationType(gin.Param("type")), &req)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Notification preference updated successfully", map[string]any{
+		"preference": preference,
+	}, http.StatusOK)
+	return nil
+}
@@ -1,21 +1,23 @@
 package handlers
 
 import (
+	"net/http"
+	"strconv"
 	"taskgo/internal/api/requests"
 	"taskgo/internal/api/responses"
+	"taskgo/internal/database/models"
 	"taskgo/internal/deps"
 	"taskgo/internal/helpers"
-	"taskgo/internal/notification"
 	"taskgo/internal/policies"
 	"taskgo/internal/services"
 	"taskgo/internal/tasks"
+	chainq "taskgo/pkg/asynq_chain"
 	"taskgo/pkg/errors"
 	"taskgo/pkg/logger"
 	"taskgo/pkg/response"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
 type OrderHandler struct {
@@ -70,36 +72,22 @@ func (h *OrderHandler) CreateOrder(gin *gin.Context) error {
 		return err
 	}
 
-	order, err := h.orderService.CreateOrder(gin.Request.Context(), &req)
-	if err != nil {
-		return err
-	}
-
 	// Async chain of tasks -> inventory check -> process payment -> order fulfillment -> after that other tasks are independent (notifications, reporting) can be handled in another way
-	err = tasks.Chain().
-		Then(tasks.NewInventoryCheckTask(order.ID)).
-		Then(tasks.NewProcessPaymentTask(order.ID)).
-		OnQueue(tasks.QueueOrderProcessingChain).
-		MaxRetries(3).
-		Timeout(3 * time.Minute).
-		OnSuccess(func(result interface{}) error {
-			h.log.Channel("default").Info("Order processing chain completed", zap.Uint("order_id", order.ID))
-			// Should dispatch notification task
-			err := deps.Notify().Send(notification.NewOrderCreatedNotification(order.ID), authUser)
-			if err != nil {
-				h.log.Channel("default").Error("Failed to dispatch notification task", zap.Uint("order_id", order.ID), zap.Error(err))
-				return err
-			}
-			return nil
-		}).
-		OnFailure(func(err error) error {
-			h.log.Channel("default").Error("Order processing chain failed", zap.Uint("order_id", order.ID), zap.Error(err))
-			return nil
-		}).
-		Dispatch()
-
+	// the chain is written to the outbox with the order so the order is never left without its processing chain
+	// (the customer is notified by the order service and the order status hooks, the chain callbacks don't survive the outbox)
+	order, err := h.orderService.CreateOrder(gin.Request.Context(), &req, func(order *models.Order) (*chainq.TaskMessage, error) {
+		return tasks.Chain().
+			WithID(tasks.OrderProcessingChainID(order.ID)). // known id so the chain can be cancelled / paused by order
+			WithTraceID(gin.GetHeader("X-Request-ID")).     // trace the chain steps back to the request
+			Then(tasks.NewInventoryCheckTask(order.ID)).
+			Then(tasks.NewProcessPaymentTask(order.ID)).
+			OnQueue(tasks.QueueOrderProcessingChain).
+			MaxRetries(tasks.ChainMaxRetry(tasks.QueueOrderProcessingChain)).
+			Timeout(3 * time.Minute).
+			Build()
+	})
 	if err != nil {
-		return errors.NewServerError("Internal Server Error: Failed to process the order.", "Internal Server Error: Failed to dispatch order processing chain", err)
+		return err
 	}
 
 	responses.SendCreateOrderResponse(gin, order)
@@ -120,10 +108,43 @@ func (h *OrderHandler) ListUserOrders(c *gin.Context) {
 	}, 200)
 }
 
-func (h *OrderHandler) CancelOrder(c *gin.Context) {
-	response.Json(c, "Order cancelled successfully", gin.H{
-		"order_id": c.Param("id"),
-	}, 200)
+// @Summary     Cancel order
+// @Description Cancel an order of the authenticated user (pending or confirmed), its remaining processing steps (e.g. payment) will not run
+// @Tags        Orders
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                                 true  "Order ID"
+//
+// @Success     200  {object}  response.SuccessResponse            "Order cancelled successfully"
+// @Failure     400  {object}  response.BadRequestResponse         "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse       "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse           "Order not found"
+// @Failure     422  {object}  response.ValidationErrorResponse    "Validation Error"
+// @Failure     500  {object}  response.ServerErrorResponse        "Internal Server Error"
+//
+// @Router      /orders/{id}/cancel [put]
+func (h *OrderHandler) CancelOrder(gin *gin.Context) error {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequestError("Invalid order id", "BadRequestError: invalid order id", err)
+	}
+
+	authUser, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	order, err := h.orderService.CancelOrder(gin.Request.Context(), uint(id), authUser.ID)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Order cancelled successfully", map[string]any{
+		"order_id": order.ID,
+		"status":   order.Status,
+	}, http.StatusOK)
+	return nil
 }
 
 func (h *OrderHandler) GetOrderStatus(c *gin.Context) {
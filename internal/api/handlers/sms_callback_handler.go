@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"taskgo/internal/services"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SmsCallbackHandler struct {
+	Handler
+	smsService *services.SmsService
+}
+
+// NewSmsCallbackHandler return a new SmsCallbackHandler
+func NewSmsCallbackHandler(smsService *services.SmsService) *SmsCallbackHandler {
+	return &SmsCallbackHandler{
+		smsService: smsService,
+	}
+}
+
+// @Summary     SMS delivery status callback
+// @Description Receives the sms provider delivery status reports (the token is the configured sms callback token)
+// @Tags        Callbacks
+// @Accept      json
+// @Produce     json
+//
+// @Param       X-Callback-Token  header    string                          false  "Callback token (or the token query)"
+// @Param       token             query     string                          false  "Callback token"
+//
+// @Success     200               {object}  response.SuccessResponse        "Status reports applied successfully"
+// @Failure     400               {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401               {object}  response.UnauthorizedResponse   "Invalid callback token"
+// @Failure     500               {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /callbacks/sms/status [post]
+func (h *SmsCallbackHandler) StatusCallback(gin *gin.Context) error {
+	token := gin.GetHeader("X-Callback-Token")
+	if token == "" {
+		token = gin.Query("token")
+	}
+
+	applied, err := h.smsService.HandleStatusCallback(gin.Request.Context(), token, gin.Request)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Status reports applied successfully", map[string]any{
+		"applied": applied,
+	}, http.StatusOK)
+	return nil
+}
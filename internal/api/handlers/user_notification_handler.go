@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"taskgo/internal/database/models"
+	"taskgo/internal/filters"
+	"taskgo/internal/helpers"
+	"taskgo/internal/services"
+	"taskgo/pkg/errors"
+	"taskgo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserNotificationHandler struct {
+	Handler
+	notificationService *services.NotificationService
+}
+
+// NewUserNotificationHandler return a new UserNotificationHandler
+func NewUserNotificationHandler(notificationService *services.NotificationService) *UserNotificationHandler {
+	return &UserNotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// @Summary     List notifications
+// @Description Retrieves the authenticated user notifications (latest first), filter with unread=true for the unread ones
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       request  query     filters.NotificationFilters      true  "Filter and pagination"
+//
+// @Success     200      {object}  response.SuccessResponse         "Notifications retrieved successfully"
+// @Failure     400      {object}  response.BadRequestResponse      "Bad Request"
+// @Failure     401      {object}  response.UnauthorizedResponse    "Unauthorized Action"
+// @Failure     500      {object}  response.ServerErrorResponse     "Internal Server Error"
+//
+// @Router      /notifications [get]
+func (h *UserNotificationHandler) ListNotifications(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	var notificationFilters filters.NotificationFilters
+
+	// Bind URL query parameters to filters struct
+	if err := gin.ShouldBindQuery(&notificationFilters); err != nil {
+		return errors.NewBadRequestError("", "BadRequestError: Failed to bind URL query parameters to filters struct", err)
+	}
+
+	notifications, total, err := h.notificationService.GetPaginatedNotifications(gin.Request.Context(), user, &notificationFilters)
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.notificationService.UnreadCount(gin.Request.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	data := make([]map[string]any, len(notifications))
+	for i, notification := range notifications {
+		data[i] = notificationData(notification)
+	}
+
+	var totalPages int
+	if notificationFilters.PerPage > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(notificationFilters.PerPage)))
+	}
+
+	response.Json(gin, "Notifications retrieved successfully", map[string]any{
+		"notifications": data,
+		"unread_count":  unread,
+		"meta": map[string]any{
+			"total":       total,
+			"page":        notificationFilters.Page,
+			"limit":       notificationFilters.PerPage,
+			"total_pages": totalPages,
+			"next_page":   notificationFilters.Page + 1,
+			"prev_page":   notificationFilters.Page - 1,
+		},
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Unread notifications count
+// @Description Counts the authenticated user unread notifications
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "Unread notifications counted successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /notifications/unread-count [get]
+func (h *UserNotificationHandler) UnreadCount(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.notificationService.UnreadCount(gin.Request.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Unread notifications counted successfully", map[string]any{
+		"unread_count": unread,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Mark notification read
+// @Description Marks the authenticated user notification read (pushed to the user websocket channel)
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Notification ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Notification marked read successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Notification not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /notifications/{id}/read [put]
+func (h *UserNotificationHandler) MarkNotificationRead(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	id, err := notificationID(gin)
+	if err != nil {
+		return err
+	}
+
+	notification, err := h.notificationService.MarkAsRead(gin.Request.Context(), user, id)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Notification marked read successfully", map[string]any{
+		"notification": notificationData(notification),
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Mark all notifications read
+// @Description Marks all the authenticated user unread notifications read (pushed to the user websocket channel)
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Success     200  {object}  response.SuccessResponse        "Notifications marked read successfully"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /notifications/read-all [put]
+func (h *UserNotificationHandler) MarkAllNotificationsRead(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.notificationService.MarkAllAsRead(gin.Request.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	response.Json(gin, "Notifications marked read successfully", map[string]any{
+		"marked_read": count,
+	}, http.StatusOK)
+	return nil
+}
+
+// @Summary     Delete notification
+// @Description Deletes the authenticated user notification
+// @Tags        Notifications
+// @Produce     json
+// @Security    BearerAuth
+//
+// @Param       id   path      int                             true  "Notification ID"
+//
+// @Success     200  {object}  response.SuccessResponse        "Notification deleted successfully"
+// @Failure     400  {object}  response.BadRequestResponse     "Bad Request"
+// @Failure     401  {object}  response.UnauthorizedResponse   "Unauthorized Action"
+// @Failure     404  {object}  response.NotFoundResponse       "Notification not found"
+// @Failure     500  {object}  response.ServerErrorResponse    "Internal Server Error"
+//
+// @Router      /notifications/{id} [delete]
+func (h *UserNotificationHandler) DeleteNotification(gin *gin.Context) error {
+	user, err := helpers.GetAuthUser(gin)
+	if err != nil {
+		return err
+	}
+
+	id, err := notificationID(gin)
+	if err != nil {
+		return err
+	}
+
+	if err := h.notificationService.DeleteNotification(gin.Request.Context(), user, id); err != nil {
+		return err
+	}
+
+	response.Json(gin, "Notification deleted successfully", nil, http.StatusOK)
+	return nil
+}
+
+func notificationID(gin *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(gin.Param("id"), 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError("Invalid notification id", "BadRequestError: invalid notification id", err)
+	}
+	return uint(id), nil
+}
+
+// notificationData returns the notification with its data decoded
+func notificationData(notification *models.Notification) map[string]any {
+	return map[string]any{
+		"id":         notification.ID,
+		"type":       notification.Type,
+		"status":     notification.Status,
+		"data":       rawJson(notification.Data),
+		"read":       notification.ReadAt != nil,
+		"read_at":    notification.ReadAt,
+		"created_at": notification.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package requests
+
+import "encoding/json"
+
+type ReplayFailedTasksRequest struct {
+	IDs     []uint          `json:"ids" validate:"required,min=1,dive,gt=0"`
+	Payload json.RawMessage `json:"payload,omitempty" swaggertype:"object"` // Edited payload (only when replaying a single task)
+	Request
+}
+
+func (r *ReplayFailedTasksRequest) Messages() map[string]string {
+	return map[string]string{
+		"ids.required": "At least one failed task id is required",
+		"ids.min":      "At least one failed task id is required",
+		"ids.gt":       "Failed task id must be greater than 0",
+	}
+}
+
+type DiscardFailedTasksRequest struct {
+	IDs []uint `json:"ids" validate:"required,min=1,dive,gt=0"`
+	Request
+}
+
+func (r *DiscardFailedTasksRequest) Messages() map[string]string {
+	return map[string]string{
+		"ids.required": "At least one failed task id is required",
+		"ids.min":      "At least one failed task id is required",
+		"ids.gt":       "Failed task id must be greater than 0",
+	}
+}
@@ -0,0 +1,21 @@
+package requests
+
+// UpdateNotificationPreferenceRequest changes the sent fields only (empty quiet hours remove them)
+type UpdateNotificationPreferenceRequest struct {
+	Database        *bool   `json:"database,omitempty"`
+	WebSocket       *bool   `json:"ws,omitempty"`
+	Email           *bool   `json:"email,omitempty"`
+	SMS             *bool   `json:"sms,omitempty"`
+	QuietHoursStart *string `json:"quiet_hours_start,omitempty" validate:"omitempty,datetime=15:04"`
+	QuietHoursEnd   *string `json:"quiet_hours_end,omitempty" validate:"omitempty,datetime=15:04"`
+	Timezone        *string `json:"timezone,omitempty" validate:"omitempty,max=64"`
+	Request
+}
+
+func (r *UpdateNotificationPreferenceRequest) Messages() map[string]string {
+	return map[string]string{
+		"quiet_hours_start.datetime": "Quiet hours start must be a time (HH:MM)",
+		"quiet_hours_end.datetime":   "Quiet hours end must be a time (HH:MM)",
+		"timezone.max":               "Timezone must be at most 64 characters",
+	}
+}
@@ -35,3 +35,17 @@ func (r *CreateOrderRequest) Messages() map[string]string {
 		"payment_method.oneof":      "Payment method must be one of credit_card, paypal, bank_transfer, cash_on_delivery",
 	}
 }
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=confirmed processing shipped delivered cancelled refunded"`
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
+	Request
+}
+
+func (r *UpdateOrderStatusRequest) Messages() map[string]string {
+	return map[string]string{
+		"status.required": "Status is required",
+		"status.oneof":    "Status must be one of confirmed, processing, shipped, delivered, cancelled, refunded",
+		"reason.max":      "Reason must be at most 500 characters",
+	}
+}
@@ -0,0 +1,24 @@
+package requests
+
+type CreateReportJobRequest struct {
+	Type     string `json:"type" validate:"required,oneof=sales"`
+	Format   string `json:"format" validate:"required,oneof=csv xlsx"`
+	From     string `json:"from,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	To       string `json:"to,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	GroupBy  string `json:"group_by,omitempty" validate:"omitempty,oneof=day week month"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,max=64"`
+	Request
+}
+
+func (r *CreateReportJobRequest) Messages() map[string]string {
+	return map[string]string{
+		"type.required":   "Report type is required",
+		"type.oneof":      "Report type must be sales",
+		"format.required": "Format is required",
+		"format.oneof":    "Format must be one of csv, xlsx",
+		"from.datetime":   "From must be a date (YYYY-MM-DD)",
+		"to.datetime":     "To must be a date (YYYY-MM-DD)",
+		"group_by.oneof":  "Group by must be one of day, week, month",
+		"timezone.max":    "Timezone must be at most 64 characters",
+	}
+}
@@ -0,0 +1,47 @@
+package requests
+
+type CreateWebhookRequest struct {
+	Name   string   `json:"name" validate:"required,min=2,max=100"`
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"` // Generated if not provided
+	Events []string `json:"events" validate:"required,min=1,dive,required,max=100"`
+	Request
+}
+
+func (r *CreateWebhookRequest) Messages() map[string]string {
+	return map[string]string{
+		"name.required":   "Name is required",
+		"name.min":        "Name must be at least 2 characters",
+		"name.max":        "Name must be at most 100 characters",
+		"url.required":    "URL is required",
+		"url.url":         "URL must be a valid URL",
+		"url.max":         "URL must be at most 2048 characters",
+		"secret.min":      "Secret must be at least 16 characters",
+		"secret.max":      "Secret must be at most 255 characters",
+		"events.required": "At least one event is required",
+		"events.min":      "At least one event is required",
+		"events.max":      "Event must be at most 100 characters",
+	}
+}
+
+type UpdateWebhookRequest struct {
+	Name   *string  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	URL    *string  `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Secret *string  `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	Events []string `json:"events,omitempty" validate:"omitempty,min=1,dive,required,max=100"`
+	Active *bool    `json:"active,omitempty"` // Re-enabling resets the consecutive failures
+	Request
+}
+
+func (r *UpdateWebhookRequest) Messages() map[string]string {
+	return map[string]string{
+		"name.min":   "Name must be at least 2 characters",
+		"name.max":   "Name must be at most 100 characters",
+		"url.url":    "URL must be a valid URL",
+		"url.max":    "URL must be at most 2048 characters",
+		"secret.min": "Secret must be at least 16 characters",
+		"secret.max": "Secret must be at most 255 characters",
+		"events.min": "At least one event is required",
+		"events.max": "Event must be at most 100 characters",
+	}
+}
@@ -46,6 +46,10 @@ func RegisterRoutes() *gin.Engine {
 	api.GET("/products/:id", middleware.HandleErrors(productHandler.GetProduct)) // Done
 	api.GET("/products/:id/inventory", productHandler.CheckInventory)            // Skipped
 
+	// Providers Callbacks (authenticated by their token)
+	smsCallbackHandler := deps.App[*handlers.SmsCallbackHandler]()
+	api.POST("/callbacks/sms/status", middleware.HandleErrors(smsCallbackHandler.StatusCallback))
+
 	// Protected routes with auth middleware
 	api.Use(middleware.Auth())
 	{
@@ -53,12 +57,53 @@ func RegisterRoutes() *gin.Engine {
 		adminApi := api.Group("/admin", middleware.AdminOnly())
 		{
 			// Admin Order Management
-			adminOrderHandler := handlers.AdminOrderHandler{}
+			adminOrderHandler := deps.App[*handlers.AdminOrderHandler]()
 			adminApi.GET("/orders", adminOrderHandler.ListAllOrders)
-			adminApi.PUT("/orders/:id/status", adminOrderHandler.UpdateOrderStatus)
-			adminApi.GET("/reports/daily", adminOrderHandler.DailySalesReport)
+			adminApi.PUT("/orders/:id/status", middleware.HandleErrors(adminOrderHandler.UpdateOrderStatus))
+			adminApi.GET("/reports/daily", middleware.HandleErrors(adminOrderHandler.DailySalesReport))
 			adminApi.GET("/inventory/low-stock", adminOrderHandler.LowStockAlerts)
 
+			// Admin Reports Exports (generated by the workers)
+			adminReportHandler := deps.App[*handlers.AdminReportHandler]()
+			adminApi.POST("/reports", middleware.HandleErrors(adminReportHandler.CreateReportJob))
+			adminApi.GET("/reports/:id", middleware.HandleErrors(adminReportHandler.GetReportJob))
+			adminApi.GET("/reports/:id/download", middleware.HandleErrors(adminReportHandler.DownloadReport))
+
+			// Admin Chains Control (cancel, pause, resume)
+			adminChainHandler := deps.App[*handlers.AdminChainHandler]()
+			adminApi.PUT("/orders/:id/chain/cancel", middleware.HandleErrors(adminChainHandler.CancelOrderChain))
+			adminApi.PUT("/chains/pause", middleware.HandleErrors(adminChainHandler.PauseAllChains))
+			adminApi.PUT("/chains/resume", middleware.HandleErrors(adminChainHandler.ResumeAllChains))
+			adminApi.GET("/chains/:id", middleware.HandleErrors(adminChainHandler.GetChainState))
+			adminApi.PUT("/chains/:id/cancel", middleware.HandleErrors(adminChainHandler.CancelChain))
+			adminApi.PUT("/chains/:id/pause", middleware.HandleErrors(adminChainHandler.PauseChain))
+			adminApi.PUT("/chains/:id/resume", middleware.HandleErrors(adminChainHandler.ResumeChain))
+
+			// Admin Recurring Tasks Schedules
+			adminScheduleHandler := deps.App[*handlers.AdminScheduleHandler]()
+			adminApi.GET("/schedules", middleware.HandleErrors(adminScheduleHandler.ListSchedules))
+
+			// Admin Dead Letter (failed tasks)
+			adminFailedTaskHandler := deps.App[*handlers.AdminFailedTaskHandler]()
+			adminApi.GET("/failed-tasks", middleware.HandleErrors(adminFailedTaskHandler.ListFailedTasks))
+			adminApi.GET("/failed-tasks/:id", middleware.HandleErrors(adminFailedTaskHandler.GetFailedTask))
+			adminApi.POST("/failed-tasks/replay", middleware.HandleErrors(adminFailedTaskHandler.ReplayFailedTasks))
+			adminApi.POST("/failed-tasks/discard", middleware.HandleErrors(adminFailedTaskHandler.DiscardFailedTasks))
+
+			// Admin Partners Webhooks
+			adminWebhookHandler := deps.App[*handlers.AdminWebhookHandler]()
+			adminApi.GET("/webhooks", middleware.HandleErrors(adminWebhookHandler.ListWebhooks))
+			adminApi.POST("/webhooks", middleware.HandleErrors(adminWebhookHandler.CreateWebhook))
+			adminApi.GET("/webhooks/:id", middleware.HandleErrors(adminWebhookHandler.GetWebhook))
+			adminApi.PUT("/webhooks/:id", middleware.HandleErrors(adminWebhookHandler.UpdateWebhook))
+			adminApi.DELETE("/webhooks/:id", middleware.HandleErrors(adminWebhookHandler.DeleteWebhook))
+			adminApi.GET("/webhooks/:id/deliveries", middleware.HandleErrors(adminWebhookHandler.ListWebhookDeliveries))
+
+			// Admin Notifications Deliveries (failed ones resending)
+			adminNotificationDeliveryHandler := deps.App[*handlers.AdminNotificationDeliveryHandler]()
+			adminApi.GET("/notifications/deliveries", middleware.HandleErrors(adminNotificationDeliveryHandler.ListDeliveries))
+			adminApi.POST("/notifications/deliveries/:id/resend", middleware.HandleErrors(adminNotificationDeliveryHandler.ResendDelivery))
+
 			// Should make inventory management
 			// ...
 		}
@@ -76,8 +121,21 @@ func RegisterRoutes() *gin.Engine {
 		api.POST("/orders", middleware.HandleErrors(orderHandler.CreateOrder)) // Working on it
 		api.GET("/orders", orderHandler.ListUserOrders)
 		api.GET("/orders/:id", orderHandler.GetOrder)
-		api.PUT("/orders/:id/cancel", orderHandler.CancelOrder)
+		api.PUT("/orders/:id/cancel", middleware.HandleErrors(orderHandler.CancelOrder))
 		api.GET("/orders/:id/status", orderHandler.GetOrderStatus)
+
+		// User Notifications Inbox
+		userNotificationHandler := deps.App[*handlers.UserNotificationHandler]()
+		api.GET("/notifications", middleware.HandleErrors(userNotificationHandler.ListNotifications))
+		api.GET("/notifications/unread-count", middleware.HandleErrors(userNotificationHandler.UnreadCount))
+		api.PUT("/notifications/read-all", middleware.HandleErrors(userNotificationHandler.MarkAllNotificationsRead))
+		api.PUT("/notifications/:id/read", middleware.HandleErrors(userNotificationHandler.MarkNotificationRead))
+		api.DELETE("/notifications/:id", middleware.HandleErrors(userNotificationHandler.DeleteNotification))
+
+		// User Notifications Preferences
+		notificationPreferenceHandler := deps.App[*handlers.NotificationPreferenceHandler]()
+		api.GET("/notifications/preferences", middleware.HandleErrors(notificationPreferenceHandler.ListPreferences))
+		api.PUT("/notifications/preferences/:type", middleware.HandleErrors(notificationPreferenceHandler.UpdatePreference))
 	}
 
 	return r
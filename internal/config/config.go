@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -112,8 +113,13 @@ func (c *Config) GetBool(key string, defaultVal bool) bool {
 	if err != nil {
 		return defaultVal
 	}
-	if boolean, ok := val.(bool); ok {
-		return boolean
+	switch v := val.(type) {
+	case bool:
+		return v
+	case string: // values loaded from env are strings
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			return parsed
+		}
 	}
 	return defaultVal
 }
@@ -145,8 +151,17 @@ func (c *Config) GetInt(key string, defaultVal int) int {
 	if err != nil {
 		return defaultVal
 	}
-	if v, ok := val.(int); ok {
+	switch v := val.(type) {
+	case int:
 		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string: // values loaded from env are strings
+		if parsed, err := strconv.Atoi(v); err == nil {
+			return parsed
+		}
 	}
 	return defaultVal
 }
@@ -156,8 +171,13 @@ func (c *Config) GetDuration(key string, defaultVal time.Duration) time.Duration
 	if err != nil {
 		return defaultVal
 	}
-	if time, ok := val.(time.Duration); ok {
-		return time
+	switch v := val.(type) {
+	case time.Duration:
+		return v
+	case string: // values loaded from env are strings (e.g. "30s")
+		if parsed, err := time.ParseDuration(v); err == nil {
+			return parsed
+		}
 	}
 	return defaultVal
 }
@@ -0,0 +1,20 @@
+package config
+
+func init() {
+	Register(eventsConfig)
+}
+
+// Domain events configuration
+func eventsConfig(cfg *Config) {
+	cfg.Set("events", map[string]any{
+		"enabled": Env("EVENTS_ENABLED", true),
+
+		// memory (in process subscribers only) | redis (redis stream consumed by the other services)
+		"driver": Env("EVENTS_DRIVER", "redis"),
+
+		"redis": map[string]any{
+			"stream":  Env("EVENTS_REDIS_STREAM", "taskgo:events"),
+			"max_len": Env("EVENTS_REDIS_MAX_LEN", 100000), // approximate cap of the stream length (0 = no cap)
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package config
+
+func init() {
+	Register(mailConfig)
+}
+
+// Mail configuration (email notifications)
+func mailConfig(cfg *Config) {
+	cfg.Set("mail", map[string]any{
+		// smtp | file (.eml files for dev) | memory (captured outbox for the tests)
+		"driver": Env("MAIL_DRIVER", "file"),
+
+		"from": map[string]any{
+			"address": Env("MAIL_FROM_ADDRESS", "no-reply@taskgo.local"),
+			"name":    Env("MAIL_FROM_NAME", "TaskGo"),
+		},
+
+		// The local stand-in is mailpit (docker compose): smtp on 1025, inbox on http://localhost:8025
+		"smtp": map[string]any{
+			"host":     Env("MAIL_HOST", "localhost"),
+			"port":     Env("MAIL_PORT", 1025),
+			"username": Env("MAIL_USERNAME", ""),
+			"password": Env("MAIL_PASSWORD", ""),
+		},
+
+		"file": map[string]any{
+			"path": Env("MAIL_FILE_PATH", "storage/mail"),
+		},
+
+		"layout": Env("MAIL_LAYOUT", "default"),
+	})
+}
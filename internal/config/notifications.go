@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+func init() {
+	Register(notificationsConfig)
+}
+
+// Notifications configuration (users preferences and digests)
+func notificationsConfig(cfg *Config) {
+	cfg.Set("notifications", map[string]any{
+		"preferences": map[string]any{
+			// quiet hours timezone of the users who didn't set one
+			"timezone": Env("NOTIFICATIONS_TIMEZONE", "Africa/Cairo"),
+
+			// the channels delayed to the end of the quiet hours (the in-app channels are never delayed)
+			"quiet_channels": []string{"sms", "email"},
+		},
+
+		// The digest notifications are accumulated per recipient and sent as a single summary at the end of their window (0 = sent on their own)
+		"digests": map[string]any{
+			"enabled":   Env("NOTIFICATIONS_DIGESTS_ENABLED", true),
+			"prefix":    "notify:digests:",
+			"retention": 24 * time.Hour, // how long the batches entries are kept after their window (read by the retried digests)
+
+			"low_stock":  map[string]any{"window": Env("NOTIFICATIONS_LOW_STOCK_DIGEST_WINDOW", time.Hour)},
+			"new_orders": map[string]any{"window": Env("NOTIFICATIONS_NEW_ORDERS_DIGEST_WINDOW", 15*time.Minute)},
+		},
+	})
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 func init() {
 	Register(queueConfig)
 }
@@ -11,6 +13,36 @@ func queueConfig(cfg *Config) {
 		"default":  Env("QUEUE_DEFAULT", "redis"),
 		"enabled":  Env("QUEUE_ACTIVE", true),
 		"required": Env("QUEUE_REQUIRED", false), // Required for app to run
+
+		// Chains configuration
+		"chain": map[string]any{
+			"pause_poll_interval": 30 * time.Second,   // delay before checking a paused chain again
+			"state_ttl":           7 * 24 * time.Hour, // how long the chain control flags (cancelled, paused) are kept
+		},
+
+		// Recurring tasks scheduler (runs inside the workers, only the leader enqueues the tasks)
+		"scheduler": map[string]any{
+			"enabled":         Env("QUEUE_SCHEDULER_ENABLED", true),
+			"timezone":        Env("QUEUE_SCHEDULER_TIMEZONE", "UTC"), // time zone of the cron specs
+			"leader_lock_ttl": 30 * time.Second,                       // leader lock ttl, renewed every (ttl / 3)
+		},
+
+		// Transactional outbox (tasks written with their data, enqueued by the relay running in the workers)
+		"outbox": map[string]any{
+			"relay_enabled": Env("QUEUE_OUTBOX_RELAY_ENABLED", true),
+			"poll_interval": Env("QUEUE_OUTBOX_POLL_INTERVAL", "2s"), // delay between the relay batches
+			"batch_size":    100,
+			"retry_delay":   "5s", // delay before enqueuing a failed message again (grows with the attempts up to 5m)
+			"max_attempts":  0,    // mark the message as failed after max attempts (0 = retry until enqueued)
+			"lease":         "1m", // a claimed message is claimed again after the lease if it's still not enqueued (e.g. the relay crashed)
+		},
+
+		// Dead letter (archived failed tasks are persisted in the failed_tasks table)
+		"dead_letter": map[string]any{
+			"enabled":      Env("QUEUE_DEAD_LETTER_ENABLED", true),
+			"attempts_ttl": 7 * 24 * time.Hour, // how long the failed attempts history is kept while the task is retrying
+		},
+
 		"consumer": map[string]any{
 			// Worker concurrency settings
 			"concurrency": Env("QUEUE_CONSUMER_CONCURRENCY", 10),
@@ -24,18 +56,65 @@ func queueConfig(cfg *Config) {
 				"inventory_check":        3,
 				"order_processing_chain": 6,
 				"notifications":          3,
+				"webhooks":               2,
 			},
 
 			// Retry configuration
+			// strategy: constant | exponential | jitter (random delay up to the exponential delay)
+			// the queues and task_types policies override the default policy (task type > queue > default)
 			"retry": map[string]any{
 				"max_attempts": Env("QUEUE_MAX_RETRY_ATTEMPTS", 3),
-				"delay":        Env("QUEUE_RETRY_DELAY", "15s"), // delay between retries
+				"delay":        Env("QUEUE_RETRY_DELAY", "15s"), // base delay between retries
+				"strategy":     Env("QUEUE_RETRY_STRATEGY", "exponential"),
+				"max_delay":    Env("QUEUE_RETRY_MAX_DELAY", "10m"),
+				"factor":       2,
+				"queues": map[string]any{
+					"payments": map[string]any{
+						"max_attempts": 5,
+						"delay":        "30s",
+						"strategy":     "jitter",
+						"max_delay":    "30m",
+					},
+				},
+				"task_types": map[string]any{
+					// "process:payment": map[string]any{"max_attempts": 5, "delay": "1m", "strategy": "constant"},
+
+					// partners endpoints may be down for a while (30s, 1m, 2m, ... up to 6h, ~1 day in total)
+					"webhook:deliver": map[string]any{
+						"max_attempts": Env("WEBHOOKS_MAX_ATTEMPTS", 12),
+						"delay":        "30s",
+						"strategy":     "exponential",
+						"max_delay":    "6h",
+					},
+				},
+			},
+
+			// Concurrency and rate limits by queue or task type (0 = unlimited)
+			// tasks over the limit are re-scheduled after retry_in (or the end of the rate window) without counting a failure
+			// backend: redis (shared by all the workers) | local (per worker process)
+			// the queue limits apply to the queue the task is pulled from (the chain steps run from the chain queue)
+			"limits": map[string]any{
+				"backend":  Env("QUEUE_LIMITS_BACKEND", "redis"),
+				"slot_ttl": "30m", // max time a concurrency slot is held if the task has no deadline
+				"queues":   map[string]any{
+					// "payments": map[string]any{"concurrency": 5},
+				},
+				"task_types": map[string]any{
+					// payment provider limits
+					"process:payment": map[string]any{
+						"concurrency": Env("QUEUE_PAYMENT_CONCURRENCY", 5),
+						"rate":        Env("QUEUE_PAYMENT_RATE", 10),
+						"period":      "1s",
+						"retry_in":    "2s",
+					},
+				},
 			},
 
 			// Health check configuration
 			"health_check": map[string]any{
 				"enabled":  Env("QUEUE_HEALTH_CHECK_ENABLED", true),
-				"interval": Env("QUEUE_HEALTH_CHECK_INTERVAL", "30s"),
+				"interval": Env("QUEUE_HEALTH_CHECK_INTERVAL", "30s"),  // readiness checks (redis, database) interval
+				"address":  Env("QUEUE_HEALTH_CHECK_ADDRESS", ":9090"), // worker health server (/livez, /readyz, /metrics)
 			},
 
 			// Logging configuration for tasks
@@ -0,0 +1,22 @@
+package config
+
+func init() {
+	Register(reportsConfig)
+}
+
+// Reports configuration
+func reportsConfig(cfg *Config) {
+	cfg.Set("reports", map[string]any{
+		"timezone":       Env("REPORTS_TIMEZONE", "UTC"), // default time zone of the reported days
+		"max_range_days": Env("REPORTS_MAX_RANGE_DAYS", 366),
+		"rollups": map[string]any{
+			"enabled": Env("REPORTS_ROLLUPS_ENABLED", false), // read the reports from the daily rollups, enable it once they are rebuilt (go run ./cmd/rollups rebuild)
+		},
+		// The previous day sales report queued for every active admin (enqueued by the workers scheduler)
+		"nightly": map[string]any{
+			"enabled":  Env("REPORTS_NIGHTLY_ENABLED", true),
+			"cronspec": Env("REPORTS_NIGHTLY_CRONSPEC", "0 2 * * *"), // in the queue.scheduler.timezone
+			"format":   Env("REPORTS_NIGHTLY_FORMAT", "xlsx"),
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package config
+
+func init() {
+	Register(smsConfig)
+}
+
+// SMS configuration (sms notifications)
+func smsConfig(cfg *Config) {
+	cfg.Set("sms", map[string]any{
+		// fake (records the messages, dev and tests)
+		"driver": Env("SMS_DRIVER", "fake"),
+
+		// sender id
+		"from": Env("SMS_FROM", "TaskGo"),
+
+		// the longer messages are truncated (0 = unlimited)
+		"max_segments": Env("SMS_MAX_SEGMENTS", 3),
+
+		// The provider delivery status callbacks must carry the token (X-Callback-Token header or token query),
+		// the callbacks are rejected if it's not set
+		"callback": map[string]any{
+			"token": Env("SMS_CALLBACK_TOKEN", ""),
+		},
+	})
+}
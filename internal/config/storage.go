@@ -0,0 +1,16 @@
+package config
+
+func init() {
+	Register(storageConfig)
+}
+
+// File storage configuration (reports exports, ...)
+func storageConfig(cfg *Config) {
+	cfg.Set("storage", map[string]any{
+		"driver": Env("STORAGE_DRIVER", "local"),
+
+		"local": map[string]any{
+			"root": Env("STORAGE_LOCAL_ROOT", "storage/app"), // shared by the server and the workers (e.g. a mounted volume)
+		},
+	})
+}
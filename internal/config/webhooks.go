@@ -0,0 +1,14 @@
+package config
+
+func init() {
+	Register(webhooksConfig)
+}
+
+// Outbound webhooks configuration (the delivery retries are set in queue.consumer.retry.task_types)
+func webhooksConfig(cfg *Config) {
+	cfg.Set("webhooks", map[string]any{
+		"timeout":      Env("WEBHOOKS_TIMEOUT", "10s"),   // receiver response timeout
+		"max_failures": Env("WEBHOOKS_MAX_FAILURES", 50), // disable the subscription after consecutive failed attempts (0 = never)
+		"user_agent":   Env("WEBHOOKS_USER_AGENT", "TaskGo-Webhooks/1.0"),
+	})
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+func init() {
+	Register(websocketConfig)
+}
+
+// Websocket configuration
+func websocketConfig(cfg *Config) {
+	cfg.Set("websocket", map[string]any{
+		// The backplane fans the messages out to the clients connected to every API server instance,
+		// the workers publish their messages (e.g. the notifications) to it
+		// redis (pub/sub channel) | memory (single API server instance, the workers can't publish)
+		"backplane": map[string]any{
+			"driver":  Env("WS_BACKPLANE_DRIVER", "redis"),
+			"channel": Env("WS_BACKPLANE_CHANNEL", "taskgo:ws"),
+		},
+
+		// Clients connections: a client is pinged every ping period and dropped if it doesn't pong within the pong wait,
+		// a write (message, ping, close frame) taking longer than the write wait drops it as well
+		"connection": map[string]any{
+			"write_wait":         Env("WS_WRITE_WAIT", 10*time.Second),
+			"pong_wait":          Env("WS_PONG_WAIT", 60*time.Second),
+			"ping_period":        Env("WS_PING_PERIOD", 54*time.Second), // must be less than the pong wait
+			"max_message_size":   Env("WS_MAX_MESSAGE_SIZE", 8*1024),    // bytes, larger client messages close the connection
+			"send_buffer":        Env("WS_SEND_BUFFER", 256),            // messages buffered per client, a slow client is dropped when it's full
+			"close_grace_period": 5 * time.Second,                       // how long the shutdown waits for the clients to get their close frame
+		},
+	})
+}
@@ -72,6 +72,22 @@ func TestConfig_GetTypedValues(t *testing.T) {
 	assert.Equal(t, []string{"a", "b"}, cfg.GetArrayOfStrings("strings", nil))
 }
 
+func TestConfig_GetTypedValuesFromStrings(t *testing.T) {
+	cfg := New()
+	cfg.Set("bool", "false")
+	cfg.Set("int", "42")
+	cfg.Set("duration", "30s")
+	cfg.Set("invalid", "not-a-value")
+
+	assert.False(t, cfg.GetBool("bool", true))
+	assert.Equal(t, 42, cfg.GetInt("int", 0))
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("duration", 0))
+
+	assert.True(t, cfg.GetBool("invalid", true))
+	assert.Equal(t, 7, cfg.GetInt("invalid", 7))
+	assert.Equal(t, time.Minute, cfg.GetDuration("invalid", time.Minute))
+}
+
 func TestConfig_Get_InvalidPath(t *testing.T) {
 	cfg := New()
 	cfg.Set("parent", "not a map")
@@ -20,7 +20,19 @@ func RunMigrations(db *gorm.DB) error {
 		&models.OrderItem{},
 		&models.Payment{},
 		&models.Notification{},
+		&models.NotificationDelivery{},
+		&models.SmsMessage{},
+		&models.NotificationPreference{},
 		&models.AuditLog{},
+		&models.FailedTask{},
+		&models.OutboxMessage{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.ReportJob{},
+		&models.SalesDailyTotal{},
+		&models.SalesDailyProduct{},
+		&models.SalesDailyCategory{},
+		&models.SalesRollupOrder{},
 	)
 
 	if err != nil {
@@ -37,7 +49,19 @@ func RollbackMigrations(db *gorm.DB) error {
 
 	// Drop all tables
 	err := db.Migrator().DropTable(
+		&models.SalesRollupOrder{},
+		&models.SalesDailyCategory{},
+		&models.SalesDailyProduct{},
+		&models.SalesDailyTotal{},
+		&models.ReportJob{},
+		&models.WebhookDelivery{},
+		&models.WebhookSubscription{},
+		&models.OutboxMessage{},
+		&models.FailedTask{},
 		&models.AuditLog{},
+		&models.NotificationPreference{},
+		&models.SmsMessage{},
+		&models.NotificationDelivery{},
 		&models.Notification{},
 		&models.Payment{},
 		&models.OrderItem{},
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"taskgo/internal/enums"
+	"time"
+)
+
+// FailedTask is a dead-lettered queue task (archived after exhausting its retries)
+type FailedTask struct {
+	Base
+	TaskID          string                          `gorm:"size:255;index;not null" json:"task_id"` // asynq task id of the archived task
+	TaskType        string                          `gorm:"size:100;index;not null" json:"task_type"`
+	Queue           string                          `gorm:"size:100;index;not null" json:"queue"`
+	Payload         string                          `gorm:"type:jsonb" json:"payload"`
+	PayloadEncoding enums.FailedTaskPayloadEncoding `gorm:"type:varchar(20);not null;default:'json'" json:"payload_encoding"`
+	LastError       string                          `gorm:"type:text" json:"last_error"`
+	ErrorChain      string                          `gorm:"type:jsonb" json:"error_chain"` // wrapped errors messages (outer -> inner)
+	Attempts        string                          `gorm:"type:jsonb" json:"attempts"`    // history of the failed attempts
+	RetryCount      int                             `gorm:"not null;default:0" json:"retry_count"`
+	MaxRetry        int                             `gorm:"not null;default:0" json:"max_retry"`
+	ChainID         string                          `gorm:"size:255;index" json:"chain_id,omitempty"` // set if the task is a chain step
+	ChainStep       *int                            `json:"chain_step,omitempty"`                     // the failed chain step (replay resumes from it)
+	Status          enums.FailedTaskStatus          `gorm:"type:varchar(20);index;not null;default:'archived'" json:"status"`
+	ReplayCount     int                             `gorm:"not null;default:0" json:"replay_count"`
+	ReplayedTaskID  string                          `gorm:"size:255" json:"replayed_task_id,omitempty"`
+	FailedAt        time.Time                       `json:"failed_at"`
+	ReplayedAt      *time.Time                      `json:"replayed_at,omitempty"`
+	DiscardedAt     *time.Time                      `json:"discarded_at,omitempty"`
+}
+
+// FailedTaskAttempt is a single failed attempt of a task
+type FailedTaskAttempt struct {
+	Attempt  int       `json:"attempt"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// RawPayload returns the original task payload bytes
+func (t *FailedTask) RawPayload() ([]byte, error) {
+	if t.PayloadEncoding != enums.FailedTaskPayloadBase64 {
+		return []byte(t.Payload), nil
+	}
+
+	var encoded string
+	if err := json.Unmarshal([]byte(t.Payload), &encoded); err != nil {
+		return nil, fmt.Errorf("invalid base64 payload: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
@@ -1,14 +1,17 @@
 package models
 
 import (
+	"taskgo/internal/enums"
 	"time"
 )
 
 type Notification struct {
 	Base
-	Type           string     `gorm:"type:varchar(255);not null" json:"type"`
-	Data           string     `gorm:"type:jsonb" json:"data"` // Additional notification data (nullable)
-	ReadAt         *time.Time `json:"read_at,omitempty"`
-	NotifiableID   uint       `gorm:"index" json:"notifiable_id"` // morph relation (user, product, order, etc) the notification is related to
-	NotifiableType string     `gorm:"size:50" json:"notifiable_type"`
+	NotificationID string                   `gorm:"size:36;index" json:"notification_id"` // shared by the notification channels deliveries
+	Type           string                   `gorm:"type:varchar(255);not null" json:"type"`
+	Data           string                   `gorm:"type:jsonb" json:"data"`                                 // Additional notification data (nullable)
+	Status         enums.NotificationStatus `gorm:"type:varchar(20);not null;default:'sent'" json:"status"` // sent | read
+	ReadAt         *time.Time               `json:"read_at,omitempty"`
+	NotifiableID   uint                     `gorm:"index" json:"notifiable_id"` // morph relation (user, product, order, etc) the notification is related to
+	NotifiableType string                   `gorm:"size:50" json:"notifiable_type"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"taskgo/internal/enums"
+	"time"
+)
+
+// NotificationDelivery is the delivery state of a notification on a channel
+type NotificationDelivery struct {
+	Base
+	NotificationID   string                   `gorm:"size:36;uniqueIndex:idx_notification_delivery;not null" json:"notification_id"`
+	Channel          string                   `gorm:"size:20;uniqueIndex:idx_notification_delivery;not null" json:"channel"`
+	NotificationType string                   `gorm:"type:varchar(255);not null" json:"notification_type"`
+	NotifiableID     uint                     `gorm:"index" json:"notifiable_id"`
+	NotifiableType   string                   `gorm:"size:50" json:"notifiable_type"`
+	Data             string                   `gorm:"type:jsonb" json:"data"`                                          // channel task data (resent as is)
+	Status           enums.NotificationStatus `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"` // pending | sent | failed (| delivered by the sms callbacks)
+	Attempts         int                      `gorm:"not null;default:0" json:"attempts"`
+	Redeliveries     int                      `gorm:"not null;default:0" json:"redeliveries"` // resent by the admins
+	LastError        string                   `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt           *time.Time               `json:"sent_at,omitempty"`
+	FailedAt         *time.Time               `json:"failed_at,omitempty"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"taskgo/internal/enums"
+	"time"
+)
+
+// NotificationPreference is the user channels and quiet hours of a notification type (all the channels without quiet hours if missing)
+type NotificationPreference struct {
+	Base
+	UserID          uint                   `gorm:"uniqueIndex:idx_notification_preference;not null" json:"user_id"`
+	Type            enums.NotificationType `gorm:"type:varchar(20);uniqueIndex:idx_notification_preference;not null" json:"type"`
+	Database        bool                   `gorm:"not null" json:"database"`
+	WebSocket       bool                   `gorm:"column:ws;not null" json:"ws"`
+	Email           bool                   `gorm:"not null" json:"email"`
+	SMS             bool                   `gorm:"column:sms;not null" json:"sms"`
+	QuietHoursStart string                 `gorm:"size:5" json:"quiet_hours_start"` // HH:MM (empty = no quiet hours)
+	QuietHoursEnd   string                 `gorm:"size:5" json:"quiet_hours_end"`   // HH:MM, before the start if overnight
+	Timezone        string                 `gorm:"size:64" json:"timezone"`         // quiet hours timezone (empty = default)
+}
+
+// DefaultNotificationPreference returns the preference of the users who didn't change it
+func DefaultNotificationPreference(userID uint, notificationType enums.NotificationType) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:    userID,
+		Type:      notificationType,
+		Database:  true,
+		WebSocket: true,
+		Email:     true,
+		SMS:       true,
+	}
+}
+
+// Enabled checks if the channel is enabled (the channels without a toggle are always enabled)
+func (p *NotificationPreference) Enabled(channel string) bool {
+	switch enums.NotificationChannel(channel) {
+	case enums.NotificationChannelDatabase:
+		return p.Database
+	case enums.NotificationChannelWebSocket:
+		return p.WebSocket
+	case enums.NotificationChannelEmail:
+		return p.Email
+	case enums.NotificationChannelSMS:
+		return p.SMS
+	default:
+		return true
+	}
+}
+
+// QuietUntil returns the end of the quiet hours if now is inside them (nil otherwise)
+func (p *NotificationPreference) QuietUntil(now time.Time, location *time.Location) *time.Time {
+	start, errStart := time.Parse("15:04", p.QuietHoursStart)
+	end, errEnd := time.Parse("15:04", p.QuietHoursEnd)
+	if errStart != nil || errEnd != nil || p.QuietHoursStart == p.QuietHoursEnd {
+		return nil
+	}
+
+	now = now.In(location)
+	at := func(day time.Time, clock time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+	}
+
+	todayStart, todayEnd := at(now, start), at(now, end)
+	var until time.Time
+	switch {
+	case todayStart.Before(todayEnd): // same day (e.g. 13:00 - 15:00)
+		if now.Before(todayStart) || !now.Before(todayEnd) {
+			return nil
+		}
+		until = todayEnd
+	case !now.Before(todayStart): // overnight (e.g. 22:00 - 07:00), before midnight
+		until = at(now.AddDate(0, 0, 1), end)
+	case now.Before(todayEnd): // overnight, after midnight
+		until = todayEnd
+	default:
+		return nil
+	}
+	return &until
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"taskgo/internal/enums"
+	"time"
+)
+
+// OutboxMessage is a task written in the same transaction as the data it belongs to,
+// the outbox relay enqueues it to the queue (so no task is lost if the queue is down on commit)
+type OutboxMessage struct {
+	Base
+	TaskType     string             `gorm:"size:100;index;not null" json:"task_type"`
+	TaskID       string             `gorm:"size:255;index" json:"task_id,omitempty"` // asynq task id (deduplicates the enqueues)
+	Queue        string             `gorm:"size:100;not null" json:"queue"`
+	Payload      []byte             `gorm:"type:bytea;not null" json:"payload"`
+	MaxRetry     int                `gorm:"not null;default:0" json:"max_retry"`
+	Timeout      time.Duration      `gorm:"not null;default:0" json:"timeout"`
+	Status       enums.OutboxStatus `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"`
+	Attempts     int                `gorm:"not null;default:0" json:"attempts"`
+	LastError    string             `gorm:"type:text" json:"last_error,omitempty"`
+	AvailableAt  time.Time          `gorm:"index;not null" json:"available_at"` // next enqueue attempt (the lease expiry while dispatching)
+	DispatchedAt *time.Time         `json:"dispatched_at,omitempty"`
+}
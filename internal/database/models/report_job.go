@@ -0,0 +1,23 @@
+package models
+
+import (
+	"taskgo/internal/enums"
+	"time"
+)
+
+// ReportJob is a report generated by the workers and exported to the file store
+type ReportJob struct {
+	Base
+	UserID     uint                  `gorm:"index;not null" json:"user_id"` // requested by
+	Type       enums.ReportType      `gorm:"type:varchar(30);not null" json:"type"`
+	Format     enums.ReportFormat    `gorm:"type:varchar(10);not null" json:"format"`
+	Params     string                `gorm:"type:jsonb;not null" json:"params"` // report filters
+	Status     enums.ReportJobStatus `gorm:"type:varchar(20);index;not null;default:'queued'" json:"status"`
+	Attempts   int                   `gorm:"not null;default:0" json:"attempts"`
+	FilePath   string                `gorm:"size:255" json:"-"` // file store name
+	FileName   string                `gorm:"size:255" json:"file_name,omitempty"`
+	FileSize   int64                 `gorm:"not null;default:0" json:"file_size"`
+	Error      string                `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  *time.Time            `json:"started_at,omitempty"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+}
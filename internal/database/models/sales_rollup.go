@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+/*
+	Sales rollups (one row per day in the reports time zone), maintained per order by the sales rollup service
+	and rebuilt from the orders with the rollups CLI
+*/
+
+// SalesDailyTotal is the orders totals of a day
+type SalesDailyTotal struct {
+	Day             time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Revenue         float64   `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"`
+	OrdersCount     int64     `gorm:"not null;default:0" json:"orders_count"`
+	UnitsSold       int64     `gorm:"not null;default:0" json:"units_sold"`
+	PaidAmount      float64   `gorm:"type:decimal(14,2);not null;default:0" json:"paid_amount"`
+	CancelledCount  int64     `gorm:"not null;default:0" json:"cancelled_count"`
+	CancelledAmount float64   `gorm:"type:decimal(14,2);not null;default:0" json:"cancelled_amount"`
+	RefundedCount   int64     `gorm:"not null;default:0" json:"refunded_count"`
+	RefundedAmount  float64   `gorm:"type:decimal(14,2);not null;default:0" json:"refunded_amount"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SalesDailyProduct is the units sold of a product in a day
+type SalesDailyProduct struct {
+	Day       time.Time `gorm:"type:date;primaryKey" json:"day"`
+	ProductID uint      `gorm:"primaryKey" json:"product_id"`
+	Category  string    `gorm:"size:255;not null" json:"category"` // product category when the order was rolled up
+	Units     int64     `gorm:"not null;default:0" json:"units"`
+	Revenue   float64   `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SalesDailyCategory is the units sold of a product category in a day
+type SalesDailyCategory struct {
+	Day       time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Category  string    `gorm:"size:255;primaryKey" json:"category"`
+	Units     int64     `gorm:"not null;default:0" json:"units"`
+	Revenue   float64   `gorm:"type:decimal(14,2);not null;default:0" json:"revenue"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SalesRollupOrder is what an order is counted for in the rollups (its bucket: sold, cancelled or refunded, and amounts),
+// counting the order again subtracts these values first
+type SalesRollupOrder struct {
+	OrderID    uint      `gorm:"primaryKey;autoIncrement:false" json:"order_id"`
+	Day        time.Time `gorm:"type:date;not null" json:"day"`
+	Bucket     string    `gorm:"type:varchar(20);not null" json:"bucket"`
+	Amount     float64   `gorm:"type:decimal(14,2);not null;default:0" json:"amount"` // revenue, cancelled or refunded amount
+	PaidAmount float64   `gorm:"type:decimal(14,2);not null;default:0" json:"paid_amount"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"taskgo/internal/enums"
+	"time"
+)
+
+// SmsMessage is an sms notification sent through the provider, its status is updated by the provider delivery callbacks
+type SmsMessage struct {
+	Base
+	NotificationID    string                   `gorm:"size:36;index" json:"notification_id"`
+	NotificationType  string                   `gorm:"type:varchar(255);not null" json:"notification_type"`
+	NotifiableID      uint                     `gorm:"index" json:"notifiable_id"`
+	NotifiableType    string                   `gorm:"size:50" json:"notifiable_type"`
+	To                string                   `gorm:"size:20;not null" json:"to"` // E.164
+	Body              string                   `gorm:"type:text;not null" json:"body"`
+	Encoding          string                   `gorm:"size:10;not null" json:"encoding"` // GSM-7 | UCS-2
+	Segments          int                      `gorm:"not null;default:1" json:"segments"`
+	Provider          string                   `gorm:"size:50;not null" json:"provider"`
+	ProviderMessageID string                   `gorm:"size:255;uniqueIndex:idx_sms_provider_message" json:"provider_message_id"`
+	Status            enums.NotificationStatus `gorm:"type:varchar(20);index;not null;default:'sent'" json:"status"` // sent | delivered | failed
+	Error             string                   `gorm:"type:text" json:"error,omitempty"`
+	SentAt            time.Time                `json:"sent_at"`
+	DeliveredAt       *time.Time               `json:"delivered_at,omitempty"`
+}
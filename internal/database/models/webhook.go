@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// WebhookSubscription is a partner endpoint receiving the subscribed events
+type WebhookSubscription struct {
+	Base
+	Name           string     `gorm:"size:100;not null" json:"name"`
+	URL            string     `gorm:"size:2048;not null" json:"url"`
+	Secret         string     `gorm:"size:255;not null" json:"-"`                // hmac signing secret
+	Events         string     `gorm:"type:jsonb;not null" json:"events"`         // subscribed event types (e.g. ["order.shipped", "order.*"])
+	Active         bool       `gorm:"not null;default:true;index" json:"active"` // disabled by an admin or after repeated failures
+	FailureCount   int        `gorm:"not null;default:0" json:"failure_count"`   // consecutive failed deliveries
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason string     `gorm:"type:text" json:"disabled_reason,omitempty"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+}
+
+// EventTypes returns the subscribed event types
+func (s *WebhookSubscription) EventTypes() []string {
+	var eventTypes []string
+	_ = json.Unmarshal([]byte(s.Events), &eventTypes)
+	return eventTypes
+}
+
+// Subscribes checks if the subscription receives the event type ("*" = all, "order.*" = all the order events)
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	for _, pattern := range s.EventTypes() {
+		if pattern == "*" || pattern == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is a delivery attempt of an event to a subscription
+type WebhookDelivery struct {
+	Base
+	SubscriptionID uint      `gorm:"index;not null" json:"subscription_id"`
+	EventID        string    `gorm:"size:255;index;not null" json:"event_id"`
+	EventType      string    `gorm:"size:100;not null" json:"event_type"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	StatusCode     int       `json:"status_code"` // 0 if the request couldn't be sent
+	ResponseBody   string    `gorm:"type:text" json:"response_body,omitempty"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	Success        bool      `gorm:"not null;default:false" json:"success"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+}
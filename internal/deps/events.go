@@ -0,0 +1,22 @@
+package deps
+
+import (
+	"taskgo/pkg/events"
+	"taskgo/pkg/ioc"
+)
+
+/*
+|--------------------------------------------------------
+|	Application Dependency Container Calls
+|--------------------------------------------------------
+*/
+
+// Events returns the domain events publisher (nil if the events module is not loaded)
+func Events() events.Publisher {
+	// Not logged, the events module is optional and it's resolved on every transition, payment and stock check
+	publisher, err := ioc.AppMake[events.Publisher]()
+	if err != nil {
+		return nil
+	}
+	return publisher
+}
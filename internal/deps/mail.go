@@ -0,0 +1,17 @@
+package deps
+
+import (
+	"fmt"
+	"taskgo/pkg/ioc"
+	"taskgo/pkg/mail"
+)
+
+// Mailer returns the mailer (nil if the mail module is not loaded)
+func Mailer() *mail.Mailer {
+	mailer, err := ioc.AppMake[*mail.Mailer]()
+	if err != nil {
+		Log().Log().Error(fmt.Sprintf("Mailer Dependency Container Error: %s", err.Error()))
+		return nil
+	}
+	return mailer
+}
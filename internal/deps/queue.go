@@ -14,7 +14,8 @@ import (
 */
 
 type QueueClient struct {
-	Client *asynq.Client
+	Client    *asynq.Client
+	Inspector *asynq.Inspector
 }
 
 /*
@@ -23,8 +24,8 @@ type QueueClient struct {
 |--------------------------------------------------------
 */
 
-func NewQueueClient(client *asynq.Client) *QueueClient {
-	return &QueueClient{Client: client}
+func NewQueueClient(client *asynq.Client, inspector *asynq.Inspector) *QueueClient {
+	return &QueueClient{Client: client, Inspector: inspector}
 }
 
 func Queue() *QueueClient {
@@ -38,6 +39,9 @@ func Queue() *QueueClient {
 
 // This implements the Shutdownable interface the ioc shutdown the service when the application is shutdown
 func (c *QueueClient) Shutdown() error {
+	if c.Inspector != nil {
+		_ = c.Inspector.Close()
+	}
 	if c.Client != nil {
 		return c.Client.Close()
 	}
@@ -0,0 +1,17 @@
+package deps
+
+import (
+	"fmt"
+	"taskgo/pkg/ioc"
+	"taskgo/pkg/sms"
+)
+
+// SMS returns the sms client (nil if the sms module is not loaded)
+func SMS() *sms.Client {
+	client, err := ioc.AppMake[*sms.Client]()
+	if err != nil {
+		Log().Log().Error(fmt.Sprintf("SMS Dependency Container Error: %s", err.Error()))
+		return nil
+	}
+	return client
+}
@@ -15,3 +15,14 @@ func WS() *ws.Server {
 
 	return ws
 }
+
+// WSRelay returns the relay publishing to the websocket clients (nil if the relay is not loaded)
+func WSRelay() ws.Relay {
+	relay, err := ioc.AppMake[ws.Relay]()
+	if err != nil {
+		Log().Log().Error(fmt.Sprintf("WS Relay Dependency Container Error: %s", err.Error()))
+		return nil
+	}
+
+	return relay
+}
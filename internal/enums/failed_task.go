@@ -0,0 +1,33 @@
+package enums
+
+type FailedTaskStatus string
+
+const (
+	// Task exhausted its retries (or was skipped from retrying) and archived by the queue
+	FailedTaskStatusArchived FailedTaskStatus = "archived"
+
+	// Task was enqueued again by an admin
+	FailedTaskStatusReplayed FailedTaskStatus = "replayed"
+
+	// Task was discarded by an admin and will never run again
+	FailedTaskStatusDiscarded FailedTaskStatus = "discarded"
+)
+
+func IsValidFailedTaskStatus(s string) bool {
+	switch FailedTaskStatus(s) {
+	case FailedTaskStatusArchived, FailedTaskStatusReplayed, FailedTaskStatusDiscarded:
+		return true
+	default:
+		return false
+	}
+}
+
+type FailedTaskPayloadEncoding string
+
+const (
+	// Payload is stored as is (valid json)
+	FailedTaskPayloadJson FailedTaskPayloadEncoding = "json"
+
+	// Payload isn't a valid json so it's stored as a base64 json string of its raw bytes
+	FailedTaskPayloadBase64 FailedTaskPayloadEncoding = "base64"
+)
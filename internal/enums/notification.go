@@ -7,15 +7,33 @@ const (
 	NotificationTypePayment   NotificationType = "payment"
 	NotificationTypeSystem    NotificationType = "system"
 	NotificationTypeInventory NotificationType = "inventory"
+
+	// Security alerts are mandatory, they can't be disabled by the user preferences
+	NotificationTypeSecurity NotificationType = "security"
 )
 
+// NotificationTypes are the notification types with user preferences
+var NotificationTypes = []NotificationType{
+	NotificationTypeOrder,
+	NotificationTypePayment,
+	NotificationTypeSystem,
+	NotificationTypeInventory,
+	NotificationTypeSecurity,
+}
+
+// Mandatory checks if the notification type is always sent on all its channels
+func (t NotificationType) Mandatory() bool {
+	return t == NotificationTypeSecurity
+}
+
 type NotificationStatus string
 
 const (
-	NotificationStatusPending NotificationStatus = "pending"
-	NotificationStatusSent    NotificationStatus = "sent"
-	NotificationStatusFailed  NotificationStatus = "failed"
-	NotificationStatusRead    NotificationStatus = "read"
+	NotificationStatusPending   NotificationStatus = "pending"
+	NotificationStatusSent      NotificationStatus = "sent"
+	NotificationStatusDelivered NotificationStatus = "delivered" // delivery confirmed by the provider (sms)
+	NotificationStatusFailed    NotificationStatus = "failed"
+	NotificationStatusRead      NotificationStatus = "read"
 )
 
 type NotificationChannel string
@@ -0,0 +1,17 @@
+package enums
+
+type OutboxStatus string
+
+const (
+	// Task is waiting to be enqueued by the relay
+	OutboxStatusPending OutboxStatus = "pending"
+
+	// Task is claimed by a relay that is enqueuing it (claimed again by another relay once its lease expires)
+	OutboxStatusDispatching OutboxStatus = "dispatching"
+
+	// Task was enqueued to the queue
+	OutboxStatusDispatched OutboxStatus = "dispatched"
+
+	// Task couldn't be enqueued after the max attempts (needs an admin action)
+	OutboxStatusFailed OutboxStatus = "failed"
+)
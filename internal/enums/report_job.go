@@ -0,0 +1,30 @@
+package enums
+
+type ReportJobStatus string
+
+const (
+	// Report is waiting for a worker
+	ReportJobStatusQueued ReportJobStatus = "queued"
+
+	// Report is being generated
+	ReportJobStatusRunning ReportJobStatus = "running"
+
+	// Report file is ready to download
+	ReportJobStatusDone ReportJobStatus = "done"
+
+	// Report generation failed after its retries
+	ReportJobStatusFailed ReportJobStatus = "failed"
+)
+
+type ReportType string
+
+const (
+	ReportTypeSales ReportType = "sales"
+)
+
+type ReportFormat string
+
+const (
+	ReportFormatCSV  ReportFormat = "csv"
+	ReportFormatXLSX ReportFormat = "xlsx"
+)
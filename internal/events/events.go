@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	pkgEvents "taskgo/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+/*
+|------------------------------------------
+|  Application domain events
+|------------------------------------------
+|	Published after the change is committed, consumed by the other services (e.g. from the redis stream)
+|	Events keyed by the order id keep their order per order on the brokers supporting partitions
+|------------------------------------------
+*/
+
+const (
+	TypeOrderPlaced        = "order.placed"
+	TypeOrderStatusChanged = "order.status_changed"
+	TypePaymentCaptured    = "payment.captured"
+	TypeStockLow           = "inventory.stock_low"
+)
+
+type OrderPlacedItem struct {
+	ProductID uint    `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+type OrderPlacedData struct {
+	OrderID     uint              `json:"order_id"`
+	UserID      uint              `json:"user_id"`
+	TotalAmount float64           `json:"total_amount"`
+	Items       []OrderPlacedItem `json:"items"`
+}
+
+type OrderStatusChangedData struct {
+	OrderID        uint              `json:"order_id"`
+	UserID         uint              `json:"user_id"`
+	From           enums.OrderStatus `json:"from"`
+	To             enums.OrderStatus `json:"to"`
+	Reason         string            `json:"reason,omitempty"`
+	TrackingNumber string            `json:"tracking_number,omitempty"`
+}
+
+type PaymentCapturedData struct {
+	OrderID        uint    `json:"order_id"`
+	UserID         uint    `json:"user_id"`
+	Amount         float64 `json:"amount"`
+	IdempotencyKey string  `json:"idempotency_key,omitempty"`
+}
+
+type StockLowData struct {
+	ProductID    uint `json:"product_id"`
+	Quantity     int  `json:"quantity"`
+	ReorderPoint int  `json:"reorder_point"`
+}
+
+// OrderPlaced is published when the order is created (one event per order)
+func OrderPlaced(order *models.Order) (pkgEvents.Event, error) {
+	items := make([]OrderPlacedItem, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		items[i] = OrderPlacedItem{ProductID: item.ProductID, Quantity: item.Quantity, UnitPrice: item.UnitPrice}
+	}
+
+	return pkgEvents.NewWithID(fmt.Sprintf("%s:%d", TypeOrderPlaced, order.ID), TypeOrderPlaced, orderKey(order.ID), OrderPlacedData{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		TotalAmount: order.TotalAmount,
+		Items:       items,
+	})
+}
+
+// OrderStatusChanged is published on every order status transition
+func OrderStatusChanged(order *models.Order, from enums.OrderStatus, reason string) (pkgEvents.Event, error) {
+	return pkgEvents.New(TypeOrderStatusChanged, orderKey(order.ID), OrderStatusChangedData{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		From:           from,
+		To:             order.Status,
+		Reason:         reason,
+		TrackingNumber: order.TrackingNumber,
+	})
+}
+
+// OrderStatusEntered is the "order.<status>" event (e.g. order.shipped) delivered to the partners webhooks,
+// the statuses are entered once per order so the event id is stable across retries
+func OrderStatusEntered(order *models.Order, from enums.OrderStatus, reason string) (pkgEvents.Event, error) {
+	eventType := OrderStatusEventType(order.Status)
+	return pkgEvents.NewWithID(fmt.Sprintf("%s:%d", eventType, order.ID), eventType, orderKey(order.ID), OrderStatusChangedData{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		From:           from,
+		To:             order.Status,
+		Reason:         reason,
+		TrackingNumber: order.TrackingNumber,
+	})
+}
+
+// OrderStatusEventType returns the event type of the order status (e.g. order.shipped)
+func OrderStatusEventType(status enums.OrderStatus) string {
+	return "order." + string(status)
+}
+
+// PaymentCaptured is published when the order payment is captured (one event per order)
+func PaymentCaptured(order *models.Order, idempotencyKey string) (pkgEvents.Event, error) {
+	return pkgEvents.NewWithID(fmt.Sprintf("%s:%d", TypePaymentCaptured, order.ID), TypePaymentCaptured, orderKey(order.ID), PaymentCapturedData{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Amount:         order.TotalAmount,
+		IdempotencyKey: idempotencyKey,
+	})
+}
+
+// StockLow is published when the product inventory reaches its reorder point
+func StockLow(inventory *models.Inventory) (pkgEvents.Event, error) {
+	return pkgEvents.New(TypeStockLow, fmt.Sprintf("product:%d", inventory.ProductID), StockLowData{
+		ProductID:    inventory.ProductID,
+		Quantity:     inventory.Quantity,
+		ReorderPoint: inventory.ReorderPoint,
+	})
+}
+
+// Publish publishes the built event with the app publisher (the build error is logged)
+//
+//	event, err := events.OrderPlaced(order)
+//	events.Publish(ctx, event, err)
+//
+// the events are published after the change is committed so a failure is logged and not returned
+func Publish(ctx context.Context, event pkgEvents.Event, err error) {
+	publisher := deps.Events()
+	if publisher == nil {
+		return
+	}
+
+	if err == nil {
+		err = publisher.Publish(ctx, event)
+	}
+	if err != nil {
+		deps.Log().Channel("default").Error("Failed to publish domain event", zap.String("event_type", event.Type), zap.Error(err))
+	}
+}
+
+func orderKey(orderID uint) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
@@ -0,0 +1,13 @@
+package filters
+
+// FailedTaskFilters struct for dead-lettered tasks filtering options
+type FailedTaskFilters struct {
+	Status   string `json:"status,omitempty" form:"status"`
+	TaskType string `json:"task_type,omitempty" form:"task_type"`
+	Queue    string `json:"queue,omitempty" form:"queue"`
+	ChainID  string `json:"chain_id,omitempty" form:"chain_id"`
+
+	// Pagination
+	Page    int `json:"page,omitempty" form:"page"`
+	PerPage int `json:"per_page,omitempty" form:"per_page"`
+}
@@ -0,0 +1,12 @@
+package filters
+
+// NotificationDeliveryFilters struct for notification deliveries filtering options
+type NotificationDeliveryFilters struct {
+	Status  string `json:"status,omitempty" form:"status"` // e.g. failed
+	Channel string `json:"channel,omitempty" form:"channel"`
+	Type    string `json:"type,omitempty" form:"type"` // notification type (e.g. ReportReadyNotification)
+
+	// Pagination
+	Page    int `json:"page,omitempty" form:"page"`
+	PerPage int `json:"per_page,omitempty" form:"per_page"`
+}
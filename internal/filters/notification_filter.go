@@ -0,0 +1,11 @@
+package filters
+
+// NotificationFilters struct for the user notifications filtering options
+type NotificationFilters struct {
+	Unread *bool  `json:"unread,omitempty" form:"unread"`
+	Type   string `json:"type,omitempty" form:"type"`
+
+	// Pagination
+	Page    int `json:"page,omitempty" form:"page"`
+	PerPage int `json:"per_page,omitempty" form:"per_page"`
+}
@@ -0,0 +1,9 @@
+package filters
+
+// SalesReportFilters struct for sales report options
+type SalesReportFilters struct {
+	From     string `json:"from,omitempty" form:"from"`         // YYYY-MM-DD (default: 29 days before to)
+	To       string `json:"to,omitempty" form:"to"`             // YYYY-MM-DD, included (default: today)
+	GroupBy  string `json:"group_by,omitempty" form:"group_by"` // day | week | month (default: day)
+	Timezone string `json:"timezone,omitempty" form:"timezone"` // IANA time zone (default: reports.timezone)
+}
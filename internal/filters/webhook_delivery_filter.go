@@ -0,0 +1,11 @@
+package filters
+
+// WebhookDeliveryFilters struct for webhook deliveries filtering options
+type WebhookDeliveryFilters struct {
+	Success *bool  `json:"success,omitempty" form:"success"`
+	Event   string `json:"event,omitempty" form:"event"`
+
+	// Pagination
+	Page    int `json:"page,omitempty" form:"page"`
+	PerPage int `json:"per_page,omitempty" form:"per_page"`
+}
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"fmt"
+	"taskgo/internal/deps"
+	"taskgo/pkg/notify"
+	"time"
+)
+
+// The digests keys (their windows are configured in notifications.digests.<key>.window)
+const (
+	DigestLowStock  = "low_stock"
+	DigestNewOrders = "new_orders"
+)
+
+// DigestSummarizers builds the summary of each digest batch
+func DigestSummarizers() map[string]notify.DigestSummarizer {
+	return map[string]notify.DigestSummarizer{
+		DigestLowStock:  summarizeLowStock,
+		DigestNewOrders: summarizeNewOrders,
+	}
+}
+
+// digestWindow returns the configured window of the digest (0 = the notifications are sent on their own)
+func digestWindow(key string) time.Duration {
+	return deps.Config().GetDuration(fmt.Sprintf("notifications.digests.%s.window", key), 0)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"taskgo/internal/database/models"
 	"taskgo/internal/deps"
+	"taskgo/internal/enums"
 	"taskgo/pkg/notify"
 )
 
@@ -19,8 +20,10 @@ func DatabaseChannelHandler(ctx context.Context, task *notify.NotificationTask)
 	}
 
 	notification := &models.Notification{
+		NotificationID: task.ID,
 		Type:           task.NotificationType,
 		Data:           string(dataBytes),
+		Status:         enums.NotificationStatusSent,
 		NotifiableID:   task.NotifiableID,
 		NotifiableType: task.NotifiableType,
 		ReadAt:         nil,
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	netMail "net/mail"
+	"strings"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/pkg/mail"
+	"taskgo/pkg/notify"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// EmailChannelHandler sends the notification email (data "mail" content or the "email" channel message) to the notifiable
+func EmailChannelHandler(ctx context.Context, task *notify.NotificationTask) error {
+	mailer := deps.Mailer()
+	if mailer == nil {
+		return errors.New("mailer is not loaded")
+	}
+
+	content, err := mailContent(task)
+	if err != nil {
+		return fmt.Errorf("invalid email notification %s: %w: %w", task.NotificationType, err, asynq.SkipRetry)
+	}
+
+	to, err := notifiableAddress(task)
+	if err != nil {
+		return err
+	}
+
+	if err := mailer.SendContent(ctx, []string{to}, content); err != nil {
+		return fmt.Errorf("failed to send email notification: %w", err)
+	}
+	return nil
+}
+
+// mailContent decodes the notification email (the data is decoded from json when the notification is queued)
+func mailContent(task *notify.NotificationTask) (*mail.Content, error) {
+	raw, ok := task.Data["mail"]
+	if !ok {
+		message := channelMessage(task.Data, "email")
+		if message == "" {
+			return nil, errors.New("no mail content nor email channel message")
+		}
+		return &mail.Content{Subject: message, Text: message}, nil
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var content mail.Content
+	if err := json.Unmarshal(encoded, &content); err != nil {
+		return nil, err
+	}
+	if content.Subject == "" || (content.Template == "" && content.Text == "") {
+		return nil, errors.New("mail content requires a subject and a template or a text")
+	}
+	return &content, nil
+}
+
+// notifiableAddress resolves the email address of the notifiable (users only)
+func notifiableAddress(task *notify.NotificationTask) (string, error) {
+	if task.NotifiableType != notify.NotifiableType(&models.User{}) {
+		return "", fmt.Errorf("email notifications are not supported for %s: %w", task.NotifiableType, asynq.SkipRetry)
+	}
+
+	var user models.User
+	err := deps.Gorm().DB.Select("id", "email", "first_name", "last_name").First(&user, task.NotifiableID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("notified user %d not found: %w", task.NotifiableID, asynq.SkipRetry)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the notified user %d: %w", task.NotifiableID, err)
+	}
+	if user.Email == "" {
+		return "", fmt.Errorf("user %d has no email address: %w", user.ID, asynq.SkipRetry)
+	}
+
+	address := netMail.Address{Name: strings.TrimSpace(user.FirstName + " " + user.LastName), Address: user.Email}
+	return address.String(), nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/pkg/notify"
+	"taskgo/pkg/sms"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// SMSChannelHandler sends the "sms" channel message to the notifiable phone number and records it (the provider callbacks update its status)
+func SMSChannelHandler(ctx context.Context, task *notify.NotificationTask) error {
+	client := deps.SMS()
+	if client == nil {
+		return errors.New("sms client is not loaded")
+	}
+
+	body := channelMessage(task.Data, "sms")
+	if body == "" {
+		return fmt.Errorf("no sms channel message for %s: %w", task.NotificationType, asynq.SkipRetry)
+	}
+
+	to, err := notifiablePhone(task)
+	if err != nil {
+		return err
+	}
+
+	sent, err := client.Send(ctx, to, body)
+	if errors.Is(err, sms.ErrInvalidNumber) {
+		return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send sms notification: %w", err)
+	}
+
+	message := &models.SmsMessage{
+		NotificationID:    task.ID,
+		NotificationType:  task.NotificationType,
+		NotifiableID:      task.NotifiableID,
+		NotifiableType:    task.NotifiableType,
+		To:                sent.To,
+		Body:              sent.Body,
+		Encoding:          string(sent.Encoding),
+		Segments:          sent.Segments,
+		Provider:          sent.Provider,
+		ProviderMessageID: sent.ID,
+		Status:            enums.NotificationStatusSent,
+		SentAt:            time.Now(),
+	}
+
+	// The sms is sent, the task is not retried (it would send it again)
+	if err := deps.Gorm().DB.Create(message).Error; err != nil {
+		return fmt.Errorf("failed to record the sent sms %s: %w: %w", sent.ID, err, asynq.SkipRetry)
+	}
+	return nil
+}
+
+// notifiablePhone resolves the E.164 phone number of the notifiable (users only, the numbers are stored in the Egyptian national format)
+func notifiablePhone(task *notify.NotificationTask) (string, error) {
+	if task.NotifiableType != notify.NotifiableType(&models.User{}) {
+		return "", fmt.Errorf("sms notifications are not supported for %s: %w", task.NotifiableType, asynq.SkipRetry)
+	}
+
+	var user models.User
+	err := deps.Gorm().DB.Select("id", "phone_number").First(&user, task.NotifiableID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("notified user %d not found: %w", task.NotifiableID, asynq.SkipRetry)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the notified user %d: %w", task.NotifiableID, err)
+	}
+
+	phone, err := sms.NormalizeEgyptian(user.PhoneNumber)
+	if err != nil {
+		return "", fmt.Errorf("user %d phone number: %w: %w", user.ID, err, asynq.SkipRetry)
+	}
+	return phone, nil
+}
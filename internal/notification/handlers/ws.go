@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/deps"
+	"taskgo/pkg/notify"
+	"taskgo/pkg/ws"
+)
+
+// UserChannel is the websocket channel of the user notifications
+func UserChannel(userID any) string {
+	return fmt.Sprintf("user.%v", userID)
+}
+
+// WebSocketChannelHandler pushes the notification to the user websocket channel (on every API server instance)
+func WebSocketChannelHandler(ctx context.Context, task *notify.NotificationTask) error {
+	relay := deps.WSRelay()
+	if relay == nil {
+		return errors.New("websocket relay is not loaded")
+	}
+
+	// The rendered message of the other channels (and the email content) is not sent to the client
+	data := make(map[string]any, len(task.Data))
+	for key, value := range task.Data {
+		data[key] = value
+	}
+	delete(data, "channel_messages")
+	delete(data, "mail")
+
+	msg := &ws.WSMessage{
+		Type:    "notification",
+		Channel: UserChannel(task.NotifiableID),
+		From:    "server",
+		Data: map[string]any{
+			"type":    task.NotificationType,
+			"message": channelMessage(task.Data, "ws"),
+			"data":    data,
+		},
+	}
+
+	if err := relay.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish websocket notification: %w", err)
+	}
+	return nil
+}
+
+// channelMessage returns the message rendered by the notification for the channel (channel_messages data)
+func channelMessage(data map[string]any, channel string) string {
+	switch messages := data["channel_messages"].(type) {
+	case map[string]string:
+		return messages[channel]
+	case map[string]any: // queued notifications data is decoded from json
+		message, _ := messages[channel].(string)
+		return message
+	default:
+		return ""
+	}
+}
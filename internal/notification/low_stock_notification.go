@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/pkg/mail"
+	"time"
+)
+
+// LowStockNotification tells the admins a product reached its reorder point (sent in the low_stock digest)
+type LowStockNotification struct {
+	Inventory *models.Inventory
+}
+
+func NewLowStockNotification(inventory *models.Inventory) *LowStockNotification {
+	return &LowStockNotification{Inventory: inventory}
+}
+
+func (n *LowStockNotification) Type() enums.NotificationType {
+	return enums.NotificationTypeInventory
+}
+
+func (n *LowStockNotification) Channels() []string {
+	return []string{
+		string(enums.NotificationChannelDatabase),
+		string(enums.NotificationChannelWebSocket),
+		string(enums.NotificationChannelEmail),
+	}
+}
+
+func (n *LowStockNotification) DigestKey() string {
+	return DigestLowStock
+}
+
+func (n *LowStockNotification) DigestWindow() time.Duration {
+	return digestWindow(DigestLowStock)
+}
+
+func (n *LowStockNotification) ToDatabase() string {
+	return fmt.Sprintf("📦 Product #%d is low on stock: %d left (reorder point %d)", n.Inventory.ProductID, n.Inventory.Quantity, n.Inventory.ReorderPoint)
+}
+
+func (n *LowStockNotification) ShouldQueue() bool {
+	return true
+}
+
+func (n *LowStockNotification) ScheduledAt() *time.Time {
+	return nil
+}
+
+func (n *LowStockNotification) Data() map[string]any {
+	return map[string]any{
+		"product_id":    n.Inventory.ProductID,
+		"quantity":      n.Inventory.Quantity,
+		"reorder_point": n.Inventory.ReorderPoint,
+		"channel_messages": map[string]string{
+			"database": n.ToDatabase(),
+			"ws":       n.ToDatabase(),
+			"email":    n.ToDatabase(),
+		},
+	}
+}
+
+// summarizeLowStock keeps the latest stock of each product of the digest
+func summarizeLowStock(entries []map[string]any) map[string]any {
+	latest := make(map[string]map[string]any)
+	var order []string
+	for _, entry := range entries {
+		id := fmt.Sprint(entry["product_id"])
+		if _, ok := latest[id]; !ok {
+			order = append(order, id)
+		}
+		latest[id] = entry
+	}
+
+	products := make([]map[string]any, 0, len(order))
+	lines := ""
+	for _, id := range order {
+		entry := latest[id]
+		products = append(products, map[string]any{
+			"product_id":    entry["product_id"],
+			"quantity":      entry["quantity"],
+			"reorder_point": entry["reorder_point"],
+		})
+		lines += fmt.Sprintf("\n- Product #%v: %v left (reorder point %v)", entry["product_id"], entry["quantity"], entry["reorder_point"])
+	}
+
+	message := fmt.Sprintf("📦 %d products are low on stock", len(products))
+	return map[string]any{
+		"products": products,
+		"channel_messages": map[string]string{
+			"database": message,
+			"ws":       message,
+		},
+		"mail": mail.Content{Subject: message, Text: message + ":" + lines},
+	}
+}
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"time"
+)
+
+// NewOrderNotification tells the admins an order was placed (sent in the new_orders digest)
+type NewOrderNotification struct {
+	Order *models.Order
+}
+
+func NewNewOrderNotification(order *models.Order) *NewOrderNotification {
+	return &NewOrderNotification{Order: order}
+}
+
+func (n *NewOrderNotification) Type() enums.NotificationType {
+	return enums.NotificationTypeOrder
+}
+
+func (n *NewOrderNotification) Channels() []string {
+	return []string{
+		string(enums.NotificationChannelDatabase),
+		string(enums.NotificationChannelWebSocket),
+	}
+}
+
+func (n *NewOrderNotification) DigestKey() string {
+	return DigestNewOrders
+}
+
+func (n *NewOrderNotification) DigestWindow() time.Duration {
+	return digestWindow(DigestNewOrders)
+}
+
+func (n *NewOrderNotification) ToDatabase() string {
+	return fmt.Sprintf("🛒 New order #%d placed: %.2f", n.Order.ID, n.Order.TotalAmount)
+}
+
+func (n *NewOrderNotification) ShouldQueue() bool {
+	return true
+}
+
+func (n *NewOrderNotification) ScheduledAt() *time.Time {
+	return nil
+}
+
+func (n *NewOrderNotification) Data() map[string]any {
+	return map[string]any{
+		"order_id":     n.Order.ID,
+		"total_amount": n.Order.TotalAmount,
+		"channel_messages": map[string]string{
+			"database": n.ToDatabase(),
+			"ws":       n.ToDatabase(),
+		},
+	}
+}
+
+// summarizeNewOrders counts the digest orders with their total amount
+func summarizeNewOrders(entries []map[string]any) map[string]any {
+	orderIDs := make([]any, 0, len(entries))
+	total := 0.0
+	for _, entry := range entries {
+		orderIDs = append(orderIDs, entry["order_id"])
+		if amount, ok := entry["total_amount"].(float64); ok {
+			total += amount
+		}
+	}
+
+	message := fmt.Sprintf("🛒 %d new orders placed: %.2f in total", len(orderIDs), total)
+	return map[string]any{
+		"order_ids":    orderIDs,
+		"total_amount": total,
+		"channel_messages": map[string]string{
+			"database": message,
+			"ws":       message,
+		},
+	}
+}
@@ -2,9 +2,11 @@ package notification
 
 import (
 	"fmt"
+	"taskgo/internal/enums"
 	"time"
 )
 
+// OrderCreatedNotification tells the customer the order was placed (confirmed once its payment is captured)
 type OrderCreatedNotification struct {
 	OrderID uint
 }
@@ -13,8 +15,15 @@ func NewOrderCreatedNotification(orderID uint) *OrderCreatedNotification {
 	return &OrderCreatedNotification{OrderID: orderID}
 }
 
+func (n *OrderCreatedNotification) Type() enums.NotificationType {
+	return enums.NotificationTypeOrder
+}
+
 func (n *OrderCreatedNotification) Channels() []string {
-	return []string{"database"}
+	return []string{
+		string(enums.NotificationChannelDatabase),
+		string(enums.NotificationChannelWebSocket),
+	}
 }
 
 func (n *OrderCreatedNotification) ToTelegram() string {
@@ -22,7 +31,7 @@ func (n *OrderCreatedNotification) ToTelegram() string {
 }
 
 func (n *OrderCreatedNotification) ToDatabase() string {
-	return "🛒 New order received! Order ID: " + fmt.Sprint(n.OrderID)
+	return fmt.Sprintf("🛒 Your order #%d was received, it will be confirmed once its payment is processed", n.OrderID)
 }
 
 func (n *OrderCreatedNotification) ShouldQueue() bool {
@@ -38,6 +47,7 @@ func (n *OrderCreatedNotification) Data() map[string]any {
 		"order_id": n.OrderID,
 		"channel_messages": map[string]string{
 			"database": n.ToDatabase(),
+			"ws":       n.ToDatabase(),
 			"telegram": n.ToTelegram(),
 		},
 	}
@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/pkg/mail"
+	"taskgo/pkg/notify"
+	"time"
+)
+
+// PaymentFailedReason is the cancellation reason of the orders whose payment failed (notified as a payment failure)
+const PaymentFailedReason = "payment failed"
+
+// NewOrderStatusNotification returns the customer notification of the order status change (nil if the status isn't notified)
+func NewOrderStatusNotification(order *models.Order, status enums.OrderStatus, reason string) notify.Notification {
+	base := orderNotification{Order: order, Reason: reason}
+
+	switch status {
+	case enums.OrderStatusConfirmed:
+		return &OrderConfirmedNotification{base}
+	case enums.OrderStatusShipped:
+		return &OrderShippedNotification{base}
+	case enums.OrderStatusDelivered:
+		return &OrderDeliveredNotification{base}
+	case enums.OrderStatusCancelled:
+		if reason == PaymentFailedReason {
+			return &OrderPaymentFailedNotification{base}
+		}
+		return &OrderCancelledNotification{base}
+	case enums.OrderStatusRefunded:
+		return &OrderRefundedNotification{base}
+	}
+	return nil
+}
+
+/*
+|------------------------------------------
+|  Order status notifications
+|------------------------------------------
+*/
+
+// orderNotification is shared by the order status notifications
+type orderNotification struct {
+	Order  *models.Order
+	Reason string
+}
+
+func (n *orderNotification) Type() enums.NotificationType {
+	return enums.NotificationTypeOrder
+}
+
+func (n *orderNotification) Channels() []string {
+	return []string{
+		string(enums.NotificationChannelDatabase),
+		string(enums.NotificationChannelWebSocket),
+		string(enums.NotificationChannelEmail),
+	}
+}
+
+func (n *orderNotification) ShouldQueue() bool {
+	return true
+}
+
+func (n *orderNotification) ScheduledAt() *time.Time {
+	return nil
+}
+
+// data returns the order data with the message of each channel (the sms message is sent only on the sms channel)
+func (n *orderNotification) data(subject, message, sms string) map[string]any {
+	messages := map[string]string{
+		"database": message,
+		"ws":       message,
+		"email":    message,
+	}
+	if sms != "" {
+		messages["sms"] = sms
+	}
+
+	data := map[string]any{
+		"order_id":         n.Order.ID,
+		"status":           n.Order.Status,
+		"total_amount":     n.Order.TotalAmount,
+		"channel_messages": messages,
+		"mail":             mail.Content{Subject: subject, Text: message},
+	}
+	if n.Reason != "" {
+		data["reason"] = n.Reason
+	}
+	return data
+}
+
+// OrderConfirmedNotification tells the customer the order payment was captured
+type OrderConfirmedNotification struct{ orderNotification }
+
+func (n *OrderConfirmedNotification) Data() map[string]any {
+	return n.data(
+		fmt.Sprintf("Your order #%d is confirmed", n.Order.ID),
+		fmt.Sprintf("✅ Your order #%d is confirmed, we're preparing it", n.Order.ID),
+		"",
+	)
+}
+
+// OrderPaymentFailedNotification tells the customer the order was cancelled because its payment failed
+type OrderPaymentFailedNotification struct{ orderNotification }
+
+func (n *OrderPaymentFailedNotification) Type() enums.NotificationType {
+	return enums.NotificationTypePayment
+}
+
+func (n *OrderPaymentFailedNotification) Channels() []string {
+	return append(n.orderNotification.Channels(), string(enums.NotificationChannelSMS))
+}
+
+func (n *OrderPaymentFailedNotification) Data() map[string]any {
+	return n.data(
+		fmt.Sprintf("Payment failed for your order #%d", n.Order.ID),
+		fmt.Sprintf("⚠️ The payment of your order #%d failed and the order was cancelled", n.Order.ID),
+		fmt.Sprintf("TaskGo: the payment of your order #%d failed and the order was cancelled.", n.Order.ID),
+	)
+}
+
+// OrderShippedNotification tells the customer the order was shipped with its tracking number
+type OrderShippedNotification struct{ orderNotification }
+
+func (n *OrderShippedNotification) Channels() []string {
+	return append(n.orderNotification.Channels(), string(enums.NotificationChannelSMS))
+}
+
+func (n *OrderShippedNotification) Data() map[string]any {
+	data := n.data(
+		fmt.Sprintf("Your order #%d is on its way", n.Order.ID),
+		fmt.Sprintf("🚚 Your order #%d was shipped, tracking number: %s", n.Order.ID, n.Order.TrackingNumber),
+		fmt.Sprintf("TaskGo: your order #%d was shipped, tracking number %s.", n.Order.ID, n.Order.TrackingNumber),
+	)
+	data["tracking_number"] = n.Order.TrackingNumber
+	return data
+}
+
+// OrderDeliveredNotification tells the customer the order was delivered
+type OrderDeliveredNotification struct{ orderNotification }
+
+func (n *OrderDeliveredNotification) Channels() []string {
+	return append(n.orderNotification.Channels(), string(enums.NotificationChannelSMS))
+}
+
+func (n *OrderDeliveredNotification) Data() map[string]any {
+	return n.data(
+		fmt.Sprintf("Your order #%d was delivered", n.Order.ID),
+		fmt.Sprintf("📦 Your order #%d was delivered, enjoy!", n.Order.ID),
+		fmt.Sprintf("TaskGo: your order #%d was delivered.", n.Order.ID),
+	)
+}
+
+// OrderCancelledNotification tells the customer the order was cancelled (with its reason)
+type OrderCancelledNotification struct{ orderNotification }
+
+func (n *OrderCancelledNotification) Data() map[string]any {
+	message := fmt.Sprintf("❌ Your order #%d was cancelled", n.Order.ID)
+	if n.Reason != "" {
+		message += ": " + n.Reason
+	}
+	return n.data(fmt.Sprintf("Your order #%d was cancelled", n.Order.ID), message, "")
+}
+
+// OrderRefundedNotification tells the customer the order amount was refunded
+type OrderRefundedNotification struct{ orderNotification }
+
+func (n *OrderRefundedNotification) Type() enums.NotificationType {
+	return enums.NotificationTypePayment
+}
+
+func (n *OrderRefundedNotification) Data() map[string]any {
+	return n.data(
+		fmt.Sprintf("Your order #%d was refunded", n.Order.ID),
+		fmt.Sprintf("💸 Your order #%d was refunded: %.2f", n.Order.ID, n.Order.TotalAmount),
+		"",
+	)
+}
@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/pkg/mail"
+	"time"
+)
+
+// ReportReadyNotification tells the requester the report job is done (or failed)
+type ReportReadyNotification struct {
+	Job *models.ReportJob
+}
+
+func NewReportReadyNotification(job *models.ReportJob) *ReportReadyNotification {
+	return &ReportReadyNotification{Job: job}
+}
+
+func (n *ReportReadyNotification) Type() enums.NotificationType {
+	return enums.NotificationTypeSystem
+}
+
+func (n *ReportReadyNotification) Channels() []string {
+	return []string{
+		string(enums.NotificationChannelDatabase),
+		string(enums.NotificationChannelWebSocket),
+		string(enums.NotificationChannelEmail),
+	}
+}
+
+func (n *ReportReadyNotification) ToDatabase() string {
+	if n.Job.Status == enums.ReportJobStatusFailed {
+		return fmt.Sprintf("Your %s report #%d failed: %s", n.Job.Type, n.Job.ID, n.Job.Error)
+	}
+	return fmt.Sprintf("📊 Your %s report #%d is ready to download", n.Job.Type, n.Job.ID)
+}
+
+func (n *ReportReadyNotification) ToMail() mail.Content {
+	subject := fmt.Sprintf("Your %s report #%d is ready", n.Job.Type, n.Job.ID)
+	if n.Job.Status == enums.ReportJobStatusFailed {
+		subject = fmt.Sprintf("Your %s report #%d failed", n.Job.Type, n.Job.ID)
+	}
+
+	return mail.Content{
+		Subject:  subject,
+		Template: "report_ready",
+		Data: map[string]any{
+			"report_id":    n.Job.ID,
+			"report_type":  n.Job.Type,
+			"format":       n.Job.Format,
+			"status":       n.Job.Status,
+			"file_name":    n.Job.FileName,
+			"error":        n.Job.Error,
+			"download_url": appURL(n.downloadPath()),
+		},
+	}
+}
+
+func (n *ReportReadyNotification) ShouldQueue() bool {
+	return true
+}
+
+func (n *ReportReadyNotification) ScheduledAt() *time.Time {
+	return nil
+}
+
+func (n *ReportReadyNotification) Data() map[string]any {
+	data := map[string]any{
+		"report_id":   n.Job.ID,
+		"report_type": n.Job.Type,
+		"format":      n.Job.Format,
+		"status":      n.Job.Status,
+		"channel_messages": map[string]string{
+			"database": n.ToDatabase(),
+			"ws":       n.ToDatabase(),
+		},
+		"mail": n.ToMail(),
+	}
+
+	if n.Job.Status == enums.ReportJobStatusDone {
+		data["file_name"] = n.Job.FileName
+		data["download_url"] = n.downloadPath()
+	}
+	return data
+}
+
+func (n *ReportReadyNotification) downloadPath() string {
+	return fmt.Sprintf("/api/v1/admin/reports/%d/download", n.Job.ID)
+}
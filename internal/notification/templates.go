@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// MailTemplates returns the email templates (name.html.tmpl, name.txt.tmpl and layouts/)
+func MailTemplates() fs.FS {
+	mailTemplates, err := fs.Sub(templates, "templates/mail")
+	if err != nil {
+		panic(err)
+	}
+	return mailTemplates
+}
@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"strings"
+	"taskgo/internal/deps"
+)
+
+// appURL returns the absolute url of the application path (e.g. the links of the emails)
+func appURL(path string) string {
+	url := deps.Config().GetString("app.url", "http://localhost")
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + url
+	}
+	if port := deps.Config().GetString("app.port", "8080"); port != "" && port != "80" && port != "443" {
+		url += ":" + port
+	}
+	return strings.TrimSuffix(url, "/") + path
+}
@@ -73,6 +73,131 @@ func RegisterHandlers(c *ioc.Container) {
 		), nil
 	})
 	logBindErr("NotificationHandler", err)
+
+	// Register User Notification handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.UserNotificationHandler, error) {
+		notificationService, err := ioc.Make[*services.NotificationService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewUserNotificationHandler(
+			notificationService,
+		), nil
+	})
+	logBindErr("UserNotificationHandler", err)
+
+	// Register Notification Preference handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.NotificationPreferenceHandler, error) {
+		notificationPreferenceService, err := ioc.Make[*services.NotificationPreferenceService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewNotificationPreferenceHandler(
+			notificationPreferenceService,
+		), nil
+	})
+	logBindErr("NotificationPreferenceHandler", err)
+
+	// Register Admin Notification Delivery handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminNotificationDeliveryHandler, error) {
+		notificationDeliveryService, err := ioc.Make[*services.NotificationDeliveryService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminNotificationDeliveryHandler(
+			notificationDeliveryService,
+		), nil
+	})
+	logBindErr("AdminNotificationDeliveryHandler", err)
+
+	// Register Sms Callback handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.SmsCallbackHandler, error) {
+		smsService, err := ioc.Make[*services.SmsService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewSmsCallbackHandler(
+			smsService,
+		), nil
+	})
+	logBindErr("SmsCallbackHandler", err)
+
+	// Register Admin Chain handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminChainHandler, error) {
+		chainService, err := ioc.Make[*services.ChainService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminChainHandler(
+			chainService,
+		), nil
+	})
+	logBindErr("AdminChainHandler", err)
+
+	// Register Admin Schedule handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminScheduleHandler, error) {
+		scheduleService, err := ioc.Make[*services.ScheduleService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminScheduleHandler(
+			scheduleService,
+		), nil
+	})
+	logBindErr("AdminScheduleHandler", err)
+
+	// Register Admin Failed Task handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminFailedTaskHandler, error) {
+		failedTaskService, err := ioc.Make[*services.FailedTaskService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminFailedTaskHandler(
+			failedTaskService,
+		), nil
+	})
+	logBindErr("AdminFailedTaskHandler", err)
+
+	// Register Admin Webhook Handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminWebhookHandler, error) {
+		webhookService, err := ioc.Make[*services.WebhookService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminWebhookHandler(
+			webhookService,
+		), nil
+	})
+	logBindErr("AdminWebhookHandler", err)
+
+	// Register Admin Report Handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminReportHandler, error) {
+		reportService, err := ioc.Make[*services.ReportService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminReportHandler(
+			reportService,
+		), nil
+	})
+	logBindErr("AdminReportHandler", err)
+
+	// Register Admin Order Handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*handlers.AdminOrderHandler, error) {
+		orderService, err := ioc.Make[*services.OrderService](c)
+		if err != nil {
+			return nil, err
+		}
+		reportService, err := ioc.Make[*services.ReportService](c)
+		if err != nil {
+			return nil, err
+		}
+		return handlers.NewAdminOrderHandler(
+			orderService,
+			reportService,
+		), nil
+	})
+	logBindErr("AdminOrderHandler", err)
 }
 
 func logBindErr(module string, err error) {
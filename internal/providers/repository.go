@@ -44,6 +44,114 @@ func RegisterRepository(c *ioc.Container) {
 	})
 	logBindErr("OrderRepository", err)
 
+	// Register Outbox Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.OutboxRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewOutboxRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("OutboxRepository", err)
+
+	// Register Webhook Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.WebhookRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewWebhookRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("WebhookRepository", err)
+
+	// Register Report Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.ReportRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewReportRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("ReportRepository", err)
+
+	// Register Report Job Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.ReportJobRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewReportJobRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("ReportJobRepository", err)
+
+	// Register Notification Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.NotificationRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewNotificationRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("NotificationRepository", err)
+
+	// Register Notification Preference Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.NotificationPreferenceRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewNotificationPreferenceRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("NotificationPreferenceRepository", err)
+
+	// Register Notification Delivery Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.NotificationDeliveryRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewNotificationDeliveryRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("NotificationDeliveryRepository", err)
+
+	// Register Sms Message Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.SmsMessageRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewSmsMessageRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("SmsMessageRepository", err)
+
+	// Register Sales Rollup Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.SalesRollupRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewSalesRollupRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("SalesRollupRepository", err)
+
 	// Register Inventory Repository
 	err = ioc.Bind(c, func(c *ioc.Container) (*repository.InventoryRepository, error) {
 		gormDB, err := ioc.Make[*deps.GormDB](c)
@@ -55,4 +163,16 @@ func RegisterRepository(c *ioc.Container) {
 		), nil
 	})
 	logBindErr("InventoryRepository", err)
+
+	// Register Failed Task Repository
+	err = ioc.Bind(c, func(c *ioc.Container) (*repository.FailedTaskRepository, error) {
+		gormDB, err := ioc.Make[*deps.GormDB](c)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewFailedTaskRepository(
+			gormDB,
+		), nil
+	})
+	logBindErr("FailedTaskRepository", err)
 }
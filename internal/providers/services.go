@@ -4,7 +4,17 @@ import (
 	"taskgo/internal/deps"
 	"taskgo/internal/repository"
 	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	"taskgo/pkg/filestore"
 	"taskgo/pkg/ioc"
+	"taskgo/pkg/notify"
+	"taskgo/pkg/sms"
+	"taskgo/pkg/webhook"
+	"taskgo/pkg/ws"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 )
 
 // Register Services in Container
@@ -58,16 +68,241 @@ func RegisterServices(c *ioc.Container) {
 			return nil, err
 		}
 
-		return services.NewInventoryService(invRepo, productRepo), nil
+		adminNotificationService, err := ioc.Make[*services.AdminNotificationService](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewInventoryService(invRepo, productRepo, adminNotificationService), nil
 	})
 	logBindErr("InventoryService", err)
 
+	// Register Admin Notification Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.AdminNotificationService, error) {
+		uRepo, err := ioc.Make[*repository.UserRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		// The admins are not notified if the notify module is not loaded
+		notifier, _ := ioc.Make[*notify.Notify](c)
+
+		return services.NewAdminNotificationService(uRepo, notifier), nil
+	})
+	logBindErr("AdminNotificationService", err)
+
+	// Register Order Notification Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.OrderNotificationService, error) {
+		// The customers are not notified if the notify module is not loaded
+		notifier, _ := ioc.Make[*notify.Notify](c)
+
+		return services.NewOrderNotificationService(notifier), nil
+	})
+	logBindErr("OrderNotificationService", err)
+
+	// Register Outbox Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.OutboxService, error) {
+		outboxRepo, err := ioc.Make[*repository.OutboxRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		var client *asynq.Client
+		if queue := deps.Queue(); queue != nil {
+			client = queue.Client
+		}
+
+		return services.NewOutboxService(
+			outboxRepo,
+			client,
+			deps.Config().GetInt("queue.outbox.max_attempts", 0),
+			deps.Config().GetDuration("queue.outbox.retry_delay", 5*time.Second),
+			deps.Config().GetDuration("queue.outbox.lease", time.Minute),
+		), nil
+	})
+	logBindErr("OutboxService", err)
+
+	// Register Webhook Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.WebhookService, error) {
+		webhookRepo, err := ioc.Make[*repository.WebhookRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewWebhookService(
+			webhookRepo,
+			webhook.NewSender(
+				deps.Config().GetDuration("webhooks.timeout", 10*time.Second),
+				deps.Config().GetString("webhooks.user_agent", ""),
+			),
+			deps.Config().GetInt("webhooks.max_failures", 50),
+		), nil
+	})
+	logBindErr("WebhookService", err)
+
+	// Register File Store
+	err = ioc.Singleton(c, func(c *ioc.Container) (filestore.Store, error) {
+		return filestore.NewLocalStore(deps.Config().GetString("storage.local.root", "storage/app")), nil
+	})
+	logBindErr("filestore.Store", err)
+
+	// Register Payment Gateway
+	err = ioc.Singleton(c, func(c *ioc.Container) (services.PaymentGateway, error) {
+		return services.NewManualPaymentGateway(), nil
+	})
+	logBindErr("services.PaymentGateway", err)
+
+	// Register Report Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.ReportService, error) {
+		reportRepo, err := ioc.Make[*repository.ReportRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		reportJobRepo, err := ioc.Make[*repository.ReportJobRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		salesRollupRepo, err := ioc.Make[*repository.SalesRollupRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		outboxService, err := ioc.Make[*services.OutboxService](c)
+		if err != nil {
+			return nil, err
+		}
+		store, err := ioc.Make[filestore.Store](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewReportService(
+			reportRepo,
+			reportJobRepo,
+			salesRollupRepo,
+			outboxService,
+			store,
+			deps.Config().GetString("reports.timezone", "UTC"),
+			deps.Config().GetInt("reports.max_range_days", 366),
+			deps.Config().GetBool("reports.rollups.enabled", false),
+		), nil
+	})
+	logBindErr("ReportService", err)
+
+	// Register Notification Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.NotificationService, error) {
+		notificationRepo, err := ioc.Make[*repository.NotificationRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		// The read events are not pushed if the websocket relay is not loaded
+		relay, _ := ioc.Make[ws.Relay](c)
+
+		return services.NewNotificationService(
+			notificationRepo,
+			relay,
+		), nil
+	})
+	logBindErr("NotificationService", err)
+
+	// Register Notification Preference Service (the notify router)
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.NotificationPreferenceService, error) {
+		notificationPreferenceRepo, err := ioc.Make[*repository.NotificationPreferenceRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewNotificationPreferenceService(
+			notificationPreferenceRepo,
+			deps.Config().GetString("notifications.preferences.timezone", "Africa/Cairo"),
+			deps.Config().GetArrayOfStrings("notifications.preferences.quiet_channels", []string{"sms", "email"}),
+		), nil
+	})
+	logBindErr("NotificationPreferenceService", err)
+
+	// Register Notification Delivery Service (the notify delivery recorder)
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.NotificationDeliveryService, error) {
+		notificationDeliveryRepo, err := ioc.Make[*repository.NotificationDeliveryRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		// The deliveries can't be resent if the notify module is not loaded
+		notifier, _ := ioc.Make[*notify.Notify](c)
+
+		return services.NewNotificationDeliveryService(
+			notificationDeliveryRepo,
+			notifier,
+		), nil
+	})
+	logBindErr("NotificationDeliveryService", err)
+
+	// Register Sms Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.SmsService, error) {
+		smsMessageRepo, err := ioc.Make[*repository.SmsMessageRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		notificationDeliveryRepo, err := ioc.Make[*repository.NotificationDeliveryRepository](c)
+		if err != nil {
+			return nil, err
+		}
+		client, err := ioc.Make[*sms.Client](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewSmsService(
+			smsMessageRepo,
+			notificationDeliveryRepo,
+			client,
+			deps.Config().GetString("sms.callback.token", ""),
+		), nil
+	})
+	logBindErr("SmsService", err)
+
+	// Register Sales Rollup Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.SalesRollupService, error) {
+		salesRollupRepo, err := ioc.Make[*repository.SalesRollupRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return services.NewSalesRollupService(
+			salesRollupRepo,
+			deps.Config().GetString("reports.timezone", "UTC"),
+		), nil
+	})
+	logBindErr("SalesRollupService", err)
+
+	// Register Order State Machine (singleton so the registered hooks are kept)
+	err = ioc.Singleton(c, func(c *ioc.Container) (*services.OrderStateMachine, error) {
+		return services.NewOrderStateMachine(), nil
+	})
+	logBindErr("OrderStateMachine", err)
+
 	// Register Order Service
 	err = ioc.Bind(c, func(c *ioc.Container) (*services.OrderService, error) {
 		invService, err := ioc.Make[*services.InventoryService](c)
 		if err != nil {
 			return nil, err
 		}
+		outboxService, err := ioc.Make[*services.OutboxService](c)
+		if err != nil {
+			return nil, err
+		}
+		adminNotificationService, err := ioc.Make[*services.AdminNotificationService](c)
+		if err != nil {
+			return nil, err
+		}
+		orderNotificationService, err := ioc.Make[*services.OrderNotificationService](c)
+		if err != nil {
+			return nil, err
+		}
+		stateMachine, err := ioc.Make[*services.OrderStateMachine](c)
+		if err != nil {
+			return nil, err
+		}
 		orderRepo, err := ioc.Make[*repository.OrderRepository](c)
 		if err != nil {
 			return nil, err
@@ -78,7 +313,7 @@ func RegisterServices(c *ioc.Container) {
 			return nil, err
 		}
 
-		return services.NewOrderService(invService, orderRepo, productRepo), nil
+		return services.NewOrderService(invService, outboxService, adminNotificationService, orderNotificationService, stateMachine, orderRepo, productRepo), nil
 	})
 	logBindErr("OrderService", err)
 
@@ -87,4 +322,48 @@ func RegisterServices(c *ioc.Container) {
 		return services.NewJwtService(deps.Config()), nil
 	})
 	logBindErr("JwtService", err)
+
+	// Register Chain Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.ChainService, error) {
+		return services.NewChainService(tasks.ChainControl()), nil
+	})
+	logBindErr("ChainService", err)
+
+	// Register Schedule Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.ScheduleService, error) {
+		queue := deps.Queue()
+		if queue == nil {
+			return services.NewScheduleService(nil), nil
+		}
+		return services.NewScheduleService(queue.Inspector), nil
+	})
+	logBindErr("ScheduleService", err)
+
+	// Register Failed Task Service
+	err = ioc.Bind(c, func(c *ioc.Container) (*services.FailedTaskService, error) {
+		failedTaskRepo, err := ioc.Make[*repository.FailedTaskRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		var client *asynq.Client
+		var inspector *asynq.Inspector
+		if queue := deps.Queue(); queue != nil {
+			client, inspector = queue.Client, queue.Inspector
+		}
+
+		var redisClient *redis.Client
+		if cache := deps.Cache(); cache != nil {
+			redisClient = cache.Redis
+		}
+
+		return services.NewFailedTaskService(
+			failedTaskRepo,
+			client,
+			inspector,
+			redisClient,
+			deps.Config().GetDuration("queue.dead_letter.attempts_ttl", 7*24*time.Hour),
+		), nil
+	})
+	logBindErr("FailedTaskService", err)
 }
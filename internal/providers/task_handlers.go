@@ -31,7 +31,26 @@ func RegisterTaskHandlers(c *ioc.Container) {
 
 	//  Register ProcessPayment task handler
 	err = ioc.Bind(c, func(c *ioc.Container) (*tasks.ProcessPaymentHandler, error) {
-		return tasks.NewProcessPaymentHandler(), nil
+		orderService, err := ioc.Make[*services.OrderService](c)
+		if err != nil {
+			return nil, err
+		}
+
+		orderRepo, err := ioc.Make[*repository.OrderRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		paymentGateway, err := ioc.Make[services.PaymentGateway](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return tasks.NewProcessPaymentHandler(
+			orderService,
+			orderRepo,
+			paymentGateway,
+		), nil
 	})
 	logBindErr("ProcessPaymentHandler", err)
 
@@ -43,4 +62,52 @@ func RegisterTaskHandlers(c *ioc.Container) {
 	})
 	logBindErr("notify.NotificationHandler", err)
 
+	// Register WebhookDelivery task handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*tasks.WebhookDeliveryHandler, error) {
+		webhookService, err := ioc.Make[*services.WebhookService](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return tasks.NewWebhookDeliveryHandler(webhookService), nil
+	})
+	logBindErr("WebhookDeliveryHandler", err)
+
+	// Register GenerateReport task handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*tasks.GenerateReportHandler, error) {
+		reportService, err := ioc.Make[*services.ReportService](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return tasks.NewGenerateReportHandler(reportService), nil
+	})
+	logBindErr("GenerateReportHandler", err)
+
+	// Register NightlyReport task handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*tasks.NightlyReportHandler, error) {
+		reportService, err := ioc.Make[*services.ReportService](c)
+		if err != nil {
+			return nil, err
+		}
+		userRepo, err := ioc.Make[*repository.UserRepository](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return tasks.NewNightlyReportHandler(reportService, userRepo), nil
+	})
+	logBindErr("NightlyReportHandler", err)
+
+	// Register ApplySalesRollup task handler
+	err = ioc.Bind(c, func(c *ioc.Container) (*tasks.ApplySalesRollupHandler, error) {
+		salesRollupService, err := ioc.Make[*services.SalesRollupService](c)
+		if err != nil {
+			return nil, err
+		}
+
+		return tasks.NewApplySalesRollupHandler(salesRollupService), nil
+	})
+	logBindErr("ApplySalesRollupHandler", err)
+
 }
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+
+	"gorm.io/gorm"
+)
+
+type FailedTaskRepository struct {
+	db *deps.GormDB
+}
+
+func NewFailedTaskRepository(db *deps.GormDB) *FailedTaskRepository {
+	return &FailedTaskRepository{
+		db: db,
+	}
+}
+
+// Create a new failed task
+func (r *FailedTaskRepository) Create(failedTask *models.FailedTask) error {
+	return r.db.DB.Create(failedTask).Error
+}
+
+// Get a failed task by id
+func (r *FailedTaskRepository) FindById(id uint) (*models.FailedTask, error) {
+	if id == 0 {
+		return nil, errors.New("id is required")
+	}
+
+	failedTask := models.FailedTask{}
+	if err := r.db.DB.Where("id = ?", id).First(&failedTask).Error; err != nil {
+		return nil, err
+	}
+
+	return &failedTask, nil
+}
+
+// Get a list of failed tasks by list of ids
+func (r *FailedTaskRepository) FindByIDs(ids []uint) ([]models.FailedTask, error) {
+	var failedTasks []models.FailedTask
+	err := r.db.DB.Where("id IN ?", ids).Order("id asc").Find(&failedTasks).Error
+	return failedTasks, err
+}
+
+// Update a failed task by id
+func (r *FailedTaskRepository) UpdateById(id uint, data map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("id is required")
+	}
+
+	return r.db.DB.Model(&models.FailedTask{}).Where("id = ?", id).Updates(data).Error
+}
+
+// Paginate failed tasks with filters (latest failures first)
+func (r *FailedTaskRepository) Paginate(f *filters.FailedTaskFilters) ([]*models.FailedTask, int64, error) {
+	var failedTasks []*models.FailedTask
+	var total int64
+
+	db := r.applyFilters(r.db.DB.Model(&models.FailedTask{}), f)
+
+	// Get total count before pagination
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Set default values
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+
+	if f.PerPage <= 0 {
+		f.PerPage = 10
+	}
+
+	// Apply pagination
+	offset := (f.Page - 1) * f.PerPage
+	db = db.Order("failed_at desc").Offset(offset).Limit(f.PerPage)
+
+	if err := db.Find(&failedTasks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return failedTasks, total, nil
+}
+
+// applyFilters applies all the filters to the query
+func (r *FailedTaskRepository) applyFilters(db *gorm.DB, f *filters.FailedTaskFilters) *gorm.DB {
+	if f.Status != "" && enums.IsValidFailedTaskStatus(f.Status) {
+		db = db.Where("status = ?", f.Status)
+	}
+
+	if f.TaskType != "" {
+		db = db.Where("task_type = ?", f.TaskType)
+	}
+
+	if f.Queue != "" {
+		db = db.Where("queue = ?", f.Queue)
+	}
+
+	if f.ChainID != "" {
+		db = db.Where("chain_id = ?", f.ChainID)
+	}
+
+	return db
+}
@@ -18,3 +18,10 @@ func NewInventoryRepository(db *deps.GormDB) *InventoryRepository {
 func (r *InventoryRepository) UpdateQuantity(inventoryId uint, quantity int) error {
 	return r.db.DB.Model(&models.Inventory{}).Where("id = ?", inventoryId).Update("quantity", quantity).Error
 }
+
+// Get the inventories of the products which reached their reorder point
+func (r *InventoryRepository) FindLowStockByProductIDs(productIDs []uint) ([]models.Inventory, error) {
+	var inventories []models.Inventory
+	err := r.db.DB.Where("product_id IN ? AND quantity <= reorder_point", productIDs).Find(&inventories).Error
+	return inventories, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationDeliveryRepository struct {
+	db *deps.GormDB
+}
+
+func NewNotificationDeliveryRepository(db *deps.GormDB) *NotificationDeliveryRepository {
+	return &NotificationDeliveryRepository{
+		db: db,
+	}
+}
+
+// Queued creates the pending delivery (a resent delivery goes back to pending with its redeliveries count)
+func (r *NotificationDeliveryRepository) Queued(delivery *models.NotificationDelivery) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "notification_id"}, {Name: "channel"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":       enums.NotificationStatusPending,
+			"redeliveries": delivery.Redeliveries,
+			"updated_at":   delivery.UpdatedAt,
+		}),
+	}).Create(delivery).Error
+}
+
+// Attempted counts the delivery attempt with its outcome (created if the dispatch wasn't recorded, e.g. sent now)
+func (r *NotificationDeliveryRepository) Attempted(delivery *models.NotificationDelivery) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "notification_id"}, {Name: "channel"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"attempts":   gorm.Expr("notification_deliveries.attempts + 1"),
+			"data":       delivery.Data, // the attempted data (e.g. the digest summary) is the resent one
+			"status":     delivery.Status,
+			"last_error": delivery.LastError,
+			"sent_at":    gorm.Expr("COALESCE(?, notification_deliveries.sent_at)", delivery.SentAt),
+			"failed_at":  delivery.FailedAt,
+			"updated_at": delivery.UpdatedAt,
+		}),
+	}).Create(delivery).Error
+}
+
+// Paginate the deliveries (latest updated first)
+func (r *NotificationDeliveryRepository) Paginate(f *filters.NotificationDeliveryFilters) ([]*models.NotificationDelivery, int64, error) {
+	var deliveries []*models.NotificationDelivery
+	var total int64
+
+	db := r.db.DB.Model(&models.NotificationDelivery{})
+	if f.Status != "" {
+		db = db.Where("status = ?", f.Status)
+	}
+	if f.Channel != "" {
+		db = db.Where("channel = ?", f.Channel)
+	}
+	if f.Type != "" {
+		db = db.Where("notification_type = ?", f.Type)
+	}
+
+	// Get total count before pagination
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Set default values
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+
+	if f.PerPage <= 0 {
+		f.PerPage = 10
+	}
+
+	// Apply pagination
+	offset := (f.Page - 1) * f.PerPage
+	if err := db.Order("updated_at desc, id desc").Offset(offset).Limit(f.PerPage).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// Get a delivery by id
+func (r *NotificationDeliveryRepository) FindById(id uint) (*models.NotificationDelivery, error) {
+	if id == 0 {
+		return nil, errors.New("id is required")
+	}
+
+	var delivery models.NotificationDelivery
+	if err := r.db.DB.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateStatus updates the delivery of the notification channel if its status is one of the from statuses
+func (r *NotificationDeliveryRepository) UpdateStatus(notificationID, channel string, from []enums.NotificationStatus, data map[string]any) error {
+	return r.db.DB.Model(&models.NotificationDelivery{}).
+		Where("notification_id = ? AND channel = ? AND status IN ?", notificationID, channel, from).
+		Updates(data).Error
+}
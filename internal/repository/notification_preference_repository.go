@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository struct {
+	db *deps.GormDB
+}
+
+func NewNotificationPreferenceRepository(db *deps.GormDB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		db: db,
+	}
+}
+
+// FindForUser gets the user preferences (only the changed ones are stored)
+func (r *NotificationPreferenceRepository) FindForUser(userID uint) ([]*models.NotificationPreference, error) {
+	var preferences []*models.NotificationPreference
+	err := r.db.DB.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// Find gets the user preference of the notification type
+func (r *NotificationPreferenceRepository) Find(userID uint, notificationType enums.NotificationType) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.DB.Where("user_id = ? AND type = ?", userID, notificationType).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// Upsert creates or replaces the user preference of the notification type
+func (r *NotificationPreferenceRepository) Upsert(preference *models.NotificationPreference) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"database", "ws", "email", "sms", "quiet_hours_start", "quiet_hours_end", "timezone", "updated_at",
+		}),
+	}).Create(preference).Error
+}
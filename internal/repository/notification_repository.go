@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	db *deps.GormDB
+}
+
+func NewNotificationRepository(db *deps.GormDB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// Paginate the notifications of the notifiable (latest first)
+func (r *NotificationRepository) PaginateForNotifiable(notifiableType string, notifiableID uint, f *filters.NotificationFilters) ([]*models.Notification, int64, error) {
+	var notifications []*models.Notification
+	var total int64
+
+	db := r.notifiable(notifiableType, notifiableID)
+	if f.Unread != nil {
+		if *f.Unread {
+			db = db.Where("read_at IS NULL")
+		} else {
+			db = db.Where("read_at IS NOT NULL")
+		}
+	}
+	if f.Type != "" {
+		db = db.Where("type = ?", f.Type)
+	}
+
+	// Get total count before pagination
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Set default values
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+
+	if f.PerPage <= 0 {
+		f.PerPage = 10
+	}
+
+	// Apply pagination
+	offset := (f.Page - 1) * f.PerPage
+	if err := db.Order("id desc").Offset(offset).Limit(f.PerPage).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread counts the unread notifications of the notifiable
+func (r *NotificationRepository) CountUnread(notifiableType string, notifiableID uint) (int64, error) {
+	var count int64
+	err := r.notifiable(notifiableType, notifiableID).Where("read_at IS NULL").Count(&count).Error
+	return count, err
+}
+
+// FindForNotifiable gets a notification of the notifiable by id
+func (r *NotificationRepository) FindForNotifiable(notifiableType string, notifiableID uint, id uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.notifiable(notifiableType, notifiableID).First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAsRead marks the unread notification of the notifiable read (read notifications keep their read date)
+func (r *NotificationRepository) MarkAsRead(notifiableType string, notifiableID uint, id uint, readAt time.Time) error {
+	return r.notifiable(notifiableType, notifiableID).
+		Where("id = ? AND read_at IS NULL", id).
+		Updates(map[string]any{"read_at": readAt, "status": enums.NotificationStatusRead}).Error
+}
+
+// MarkAllAsRead marks all the unread notifications of the notifiable read and returns their count
+func (r *NotificationRepository) MarkAllAsRead(notifiableType string, notifiableID uint, readAt time.Time) (int64, error) {
+	result := r.notifiable(notifiableType, notifiableID).
+		Where("read_at IS NULL").
+		Updates(map[string]any{"read_at": readAt, "status": enums.NotificationStatusRead})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteForNotifiable deletes a notification of the notifiable, returns gorm.ErrRecordNotFound if it doesn't exist
+func (r *NotificationRepository) DeleteForNotifiable(notifiableType string, notifiableID uint, id uint) error {
+	result := r.notifiable(notifiableType, notifiableID).Where("id = ?", id).Delete(&models.Notification{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) notifiable(notifiableType string, notifiableID uint) *gorm.DB {
+	return r.db.DB.Model(&models.Notification{}).
+		Where("notifiable_type = ? AND notifiable_id = ?", notifiableType, notifiableID)
+}
@@ -5,6 +5,7 @@ import (
 	"taskgo/internal/deps"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository struct {
@@ -17,8 +18,14 @@ func NewOrderRepository(db *deps.GormDB) *OrderRepository {
 	}
 }
 
+// WithTx returns a copy of the repository running its queries in the given transaction
+func (r *OrderRepository) WithTx(tx *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: &deps.GormDB{DB: tx}}
+}
+
 // Create a new order with order items
-func (r *OrderRepository) CreateWithOrderItems(order *models.Order, orderItems []*models.OrderItem) error {
+// afterCreate callbacks run inside the same transaction (e.g. writing the order tasks to the outbox)
+func (r *OrderRepository) CreateWithOrderItems(order *models.Order, orderItems []*models.OrderItem, afterCreate ...func(tx *gorm.DB) error) error {
 	return r.db.DB.Transaction(func(tx *gorm.DB) error {
 		// Create the order
 		if err := tx.Create(order).Error; err != nil {
@@ -34,6 +41,12 @@ func (r *OrderRepository) CreateWithOrderItems(order *models.Order, orderItems [
 			return err
 		}
 
+		for _, callback := range afterCreate {
+			if err := callback(tx); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
@@ -45,3 +58,34 @@ func (r *OrderRepository) GetOrderWithOrderItems(orderID uint) (*models.Order, e
 	}
 	return &order, nil
 }
+
+// Get an order by id
+func (r *OrderRepository) FindById(orderID uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.DB.First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Update an order by id
+func (r *OrderRepository) UpdateById(orderID uint, data map[string]interface{}) error {
+	return r.db.DB.Model(&models.Order{}).Where("id = ?", orderID).Updates(data).Error
+}
+
+// DB returns the connection of the repository (the transaction of a WithTx copy)
+func (r *OrderRepository) DB() *gorm.DB {
+	return r.db.DB
+}
+
+// LockById runs fn in a transaction holding the order row lock (e.g. to change the order status safely)
+func (r *OrderRepository) LockById(orderID uint, fn func(repo *OrderRepository, order *models.Order) error) error {
+	return r.db.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			return err
+		}
+
+		return fn(r.WithTx(tx), &order)
+	})
+}
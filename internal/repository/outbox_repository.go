@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *deps.GormDB
+}
+
+func NewOutboxRepository(db *deps.GormDB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// WithTx returns a copy of the repository running its queries in the given transaction
+func (r *OutboxRepository) WithTx(tx *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: &deps.GormDB{DB: tx}}
+}
+
+// Create a new outbox message
+func (r *OutboxRepository) Create(message *models.OutboxMessage) error {
+	return r.db.DB.Create(message).Error
+}
+
+// Update an outbox message by id
+func (r *OutboxRepository) UpdateById(id uint, data map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("id is required")
+	}
+
+	return r.db.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(data).Error
+}
+
+// ClaimPending claims the due pending messages (oldest first) for the lease duration and returns them,
+// the claimed messages are skipped by the other relays until their lease expires (e.g. the relay crashed while enqueuing)
+// so the rows aren't locked while the messages are enqueued
+func (r *OutboxRepository) ClaimPending(limit int, lease time.Duration) ([]*models.OutboxMessage, error) {
+	return r.claim(lease, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN ? AND available_at <= ?",
+			[]enums.OutboxStatus{enums.OutboxStatusPending, enums.OutboxStatusDispatching},
+			time.Now(),
+		).Order("id asc").Limit(limit)
+	})
+}
+
+// ClaimPendingById is ClaimPending for a single message (nothing is returned if it's claimed by a relay or not pending)
+func (r *OutboxRepository) ClaimPendingById(id uint, lease time.Duration) ([]*models.OutboxMessage, error) {
+	return r.claim(lease, func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ? AND status = ?", id, enums.OutboxStatusPending)
+	})
+}
+
+func (r *OutboxRepository) claim(lease time.Duration, scope func(db *gorm.DB) *gorm.DB) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+
+	err := r.db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(scope).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		return tx.Model(&models.OutboxMessage{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       enums.OutboxStatusDispatching,
+			"available_at": time.Now().Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+
+	"gorm.io/gorm"
+)
+
+type ReportJobRepository struct {
+	db *deps.GormDB
+}
+
+func NewReportJobRepository(db *deps.GormDB) *ReportJobRepository {
+	return &ReportJobRepository{
+		db: db,
+	}
+}
+
+// CreateWithTx creates the report job, fn runs in the same transaction (e.g. writes the job task to the outbox)
+func (r *ReportJobRepository) CreateWithTx(job *models.ReportJob, fn func(tx *gorm.DB) error) error {
+	return r.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		if fn == nil {
+			return nil
+		}
+		return fn(tx)
+	})
+}
+
+// Get a report job by id
+func (r *ReportJobRepository) FindById(id uint) (*models.ReportJob, error) {
+	if id == 0 {
+		return nil, errors.New("id is required")
+	}
+
+	job := models.ReportJob{}
+	if err := r.db.DB.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Update a report job by id
+func (r *ReportJobRepository) UpdateById(id uint, data map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("id is required")
+	}
+
+	return r.db.DB.Model(&models.ReportJob{}).Where("id = ?", id).Updates(data).Error
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"time"
+)
+
+// SoldOrderStatuses are the statuses of the orders counted in the revenue
+var SoldOrderStatuses = []enums.OrderStatus{
+	enums.OrderStatusConfirmed,
+	enums.OrderStatusProcessing,
+	enums.OrderStatusShipped,
+	enums.OrderStatusDelivered,
+}
+
+// SalesRange is the reported orders range [From, To) grouped by period in the time zone
+type SalesRange struct {
+	From     time.Time
+	To       time.Time
+	GroupBy  string // day | week | month
+	Timezone string
+}
+
+// SalesPeriod is the sales of a period (orders grouped by their creation date)
+type SalesPeriod struct {
+	PeriodStart     string  `json:"period_start"`
+	Revenue         float64 `json:"revenue"`
+	OrdersCount     int64   `json:"orders_count"`
+	UnitsSold       int64   `json:"units_sold"`
+	PaidAmount      float64 `json:"paid_amount"`
+	CancelledCount  int64   `json:"cancelled_count"`
+	CancelledAmount float64 `json:"cancelled_amount"`
+	RefundedCount   int64   `json:"refunded_count"`
+	RefundedAmount  float64 `json:"refunded_amount"`
+}
+
+// ProductSales is the units sold of a product
+type ProductSales struct {
+	ProductID uint    `json:"product_id"`
+	Name      string  `json:"name"`
+	SKU       string  `json:"sku"`
+	Category  string  `json:"category"`
+	Units     int64   `json:"units"`
+	Revenue   float64 `json:"revenue"`
+}
+
+// CategorySales is the units sold of a category
+type CategorySales struct {
+	Category string  `json:"category"`
+	Units    int64   `json:"units"`
+	Revenue  float64 `json:"revenue"`
+}
+
+type ReportRepository struct {
+	db *deps.GormDB
+}
+
+func NewReportRepository(db *deps.GormDB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+// SalesByPeriod aggregates the orders, their items and payments by period
+func (r *ReportRepository) SalesByPeriod(sr SalesRange) ([]SalesPeriod, error) {
+	var periods []SalesPeriod
+	err := r.db.DB.Raw(`
+		SELECT
+			to_char(date_trunc(@group_by, o.created_at AT TIME ZONE @timezone), 'YYYY-MM-DD') AS period_start,
+			COALESCE(SUM(o.total_amount) FILTER (WHERE o.status IN @sold), 0) AS revenue,
+			COUNT(*) FILTER (WHERE o.status IN @sold) AS orders_count,
+			COALESCE(SUM(oi.units) FILTER (WHERE o.status IN @sold), 0) AS units_sold,
+			COALESCE(SUM(p.amount) FILTER (WHERE p.status IN @paid), 0) AS paid_amount,
+			COUNT(*) FILTER (WHERE o.status = @cancelled) AS cancelled_count,
+			COALESCE(SUM(o.total_amount) FILTER (WHERE o.status = @cancelled), 0) AS cancelled_amount,
+			COUNT(*) FILTER (WHERE o.status = @refunded) AS refunded_count,
+			COALESCE(SUM(COALESCE(NULLIF(p.refund_amount, 0), o.total_amount)) FILTER (WHERE o.status = @refunded), 0) AS refunded_amount
+		FROM orders o
+		LEFT JOIN (
+			SELECT order_id, SUM(quantity) AS units FROM order_items WHERE deleted_at IS NULL GROUP BY order_id
+		) oi ON oi.order_id = o.id
+		LEFT JOIN payments p ON p.order_id = o.id AND p.deleted_at IS NULL
+		WHERE o.deleted_at IS NULL AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1
+		ORDER BY 1`,
+		r.salesArgs(sr),
+	).Scan(&periods).Error
+	return periods, err
+}
+
+// SalesByProduct aggregates the sold units and revenue by product (best sellers first)
+func (r *ReportRepository) SalesByProduct(sr SalesRange) ([]ProductSales, error) {
+	var products []ProductSales
+	err := r.EachSalesByProduct(sr, func(product ProductSales) error {
+		products = append(products, product)
+		return nil
+	})
+	return products, err
+}
+
+// EachSalesByProduct streams the SalesByProduct rows to fn (the rows are not loaded in memory)
+func (r *ReportRepository) EachSalesByProduct(sr SalesRange, fn func(product ProductSales) error) error {
+	rows, err := r.db.DB.Raw(`
+		SELECT
+			oi.product_id,
+			COALESCE(pr.name, '') AS name,
+			COALESCE(pr.sku, '') AS sku,
+			COALESCE(NULLIF(pr.category, ''), 'uncategorized') AS category,
+			SUM(oi.quantity) AS units,
+			SUM(oi.total_price) AS revenue
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL
+		LEFT JOIN products pr ON pr.id = oi.product_id
+		WHERE oi.deleted_at IS NULL AND o.status IN @sold AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY oi.product_id, pr.name, pr.sku, pr.category
+		ORDER BY units DESC, oi.product_id`,
+		r.salesArgs(sr),
+	).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product ProductSales
+		if err := r.db.DB.ScanRows(rows, &product); err != nil {
+			return err
+		}
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// SalesByCategory aggregates the sold units and revenue by product category (best sellers first)
+func (r *ReportRepository) SalesByCategory(sr SalesRange) ([]CategorySales, error) {
+	var categories []CategorySales
+	err := r.db.DB.Raw(`
+		SELECT
+			COALESCE(NULLIF(pr.category, ''), 'uncategorized') AS category,
+			SUM(oi.quantity) AS units,
+			SUM(oi.total_price) AS revenue
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL
+		LEFT JOIN products pr ON pr.id = oi.product_id
+		WHERE oi.deleted_at IS NULL AND o.status IN @sold AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1
+		ORDER BY units DESC, category`,
+		r.salesArgs(sr),
+	).Scan(&categories).Error
+	return categories, err
+}
+
+func (r *ReportRepository) salesArgs(sr SalesRange) map[string]any {
+	return map[string]any{
+		"group_by":  sr.GroupBy,
+		"timezone":  sr.Timezone,
+		"from":      sr.From,
+		"to":        sr.To,
+		"sold":      SoldOrderStatuses,
+		"paid":      []enums.PaymentStatus{enums.PaymentStatusPaid, enums.PaymentStatusRefunded},
+		"cancelled": enums.OrderStatusCancelled,
+		"refunded":  enums.OrderStatusRefunded,
+	}
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RollupOrderStatuses are the statuses of the orders counted in the sales rollups
+var RollupOrderStatuses = append([]enums.OrderStatus{enums.OrderStatusCancelled, enums.OrderStatusRefunded}, SoldOrderStatuses...)
+
+type SalesRollupRepository struct {
+	db *deps.GormDB
+}
+
+func NewSalesRollupRepository(db *deps.GormDB) *SalesRollupRepository {
+	return &SalesRollupRepository{
+		db: db,
+	}
+}
+
+// LockOrder runs fn in a transaction holding the order row lock with the order items, products and payment loaded,
+// tracked is the rollup bucket the order is counted in (nil if the order is not rolled up yet)
+func (r *SalesRollupRepository) LockOrder(orderID uint, fn func(repo *SalesRollupRepository, order *models.Order, tracked *models.SalesRollupOrder) error) error {
+	return r.db.DB.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("OrderItems.Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Preload("Payment").
+			First(&order, orderID).Error
+		if err != nil {
+			return err
+		}
+
+		var tracked *models.SalesRollupOrder
+		var rollupOrder models.SalesRollupOrder
+		err = tx.Where("order_id = ?", orderID).First(&rollupOrder).Error
+		if err == nil {
+			tracked = &rollupOrder
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return fn(&SalesRollupRepository{db: &deps.GormDB{DB: tx}}, &order, tracked)
+	})
+}
+
+// AddTotal adds the totals to the day row (negative values subtract)
+func (r *SalesRollupRepository) AddTotal(total *models.SalesDailyTotal) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"revenue":          gorm.Expr("sales_daily_totals.revenue + EXCLUDED.revenue"),
+			"orders_count":     gorm.Expr("sales_daily_totals.orders_count + EXCLUDED.orders_count"),
+			"units_sold":       gorm.Expr("sales_daily_totals.units_sold + EXCLUDED.units_sold"),
+			"paid_amount":      gorm.Expr("sales_daily_totals.paid_amount + EXCLUDED.paid_amount"),
+			"cancelled_count":  gorm.Expr("sales_daily_totals.cancelled_count + EXCLUDED.cancelled_count"),
+			"cancelled_amount": gorm.Expr("sales_daily_totals.cancelled_amount + EXCLUDED.cancelled_amount"),
+			"refunded_count":   gorm.Expr("sales_daily_totals.refunded_count + EXCLUDED.refunded_count"),
+			"refunded_amount":  gorm.Expr("sales_daily_totals.refunded_amount + EXCLUDED.refunded_amount"),
+			"updated_at":       gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(total).Error
+}
+
+// AddProduct adds the units and revenue to the day product row (negative values subtract)
+func (r *SalesRollupRepository) AddProduct(product *models.SalesDailyProduct) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"category":   gorm.Expr("EXCLUDED.category"),
+			"units":      gorm.Expr("sales_daily_products.units + EXCLUDED.units"),
+			"revenue":    gorm.Expr("sales_daily_products.revenue + EXCLUDED.revenue"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(product).Error
+}
+
+// AddCategory adds the units and revenue to the day category row (negative values subtract)
+func (r *SalesRollupRepository) AddCategory(category *models.SalesDailyCategory) error {
+	return r.db.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "category"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"units":      gorm.Expr("sales_daily_categories.units + EXCLUDED.units"),
+			"revenue":    gorm.Expr("sales_daily_categories.revenue + EXCLUDED.revenue"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(category).Error
+}
+
+// SaveRollupOrder creates or updates the order rollup bucket
+func (r *SalesRollupRepository) SaveRollupOrder(rollupOrder *models.SalesRollupOrder) error {
+	return r.db.DB.Save(rollupOrder).Error
+}
+
+// DeleteRollupOrder removes the order from the rollups tracking
+func (r *SalesRollupRepository) DeleteRollupOrder(orderID uint) error {
+	return r.db.DB.Where("order_id = ?", orderID).Delete(&models.SalesRollupOrder{}).Error
+}
+
+// Truncate empties all the rollup tables
+func (r *SalesRollupRepository) Truncate() error {
+	return r.db.DB.Exec("TRUNCATE TABLE sales_daily_totals, sales_daily_products, sales_daily_categories, sales_rollup_orders").Error
+}
+
+// EachRollupOrderIDs passes the ids of the rolled up orders statuses to fn in batches (oldest first)
+func (r *SalesRollupRepository) EachRollupOrderIDs(batchSize int, fn func(ids []uint) error) error {
+	var orders []models.Order
+	return r.db.DB.Select("id").
+		Where("status IN ?", RollupOrderStatuses).
+		FindInBatches(&orders, batchSize, func(tx *gorm.DB, batch int) error {
+			ids := make([]uint, len(orders))
+			for i, order := range orders {
+				ids[i] = order.ID
+			}
+			return fn(ids)
+		}).Error
+}
+
+/*
+|------------------------------------------
+|  Reads (same rows as the ReportRepository aggregates, the range days are in the rollups time zone)
+|------------------------------------------
+*/
+
+// SalesByPeriod sums the daily totals by period
+func (r *SalesRollupRepository) SalesByPeriod(sr SalesRange) ([]SalesPeriod, error) {
+	var periods []SalesPeriod
+	err := r.db.DB.Raw(`
+		SELECT
+			to_char(date_trunc(@group_by, day), 'YYYY-MM-DD') AS period_start,
+			SUM(revenue) AS revenue,
+			SUM(orders_count) AS orders_count,
+			SUM(units_sold) AS units_sold,
+			SUM(paid_amount) AS paid_amount,
+			SUM(cancelled_count) AS cancelled_count,
+			SUM(cancelled_amount) AS cancelled_amount,
+			SUM(refunded_count) AS refunded_count,
+			SUM(refunded_amount) AS refunded_amount
+		FROM sales_daily_totals
+		WHERE day >= @from AND day < @to
+		GROUP BY 1
+		ORDER BY 1`,
+		r.rollupArgs(sr),
+	).Scan(&periods).Error
+	return periods, err
+}
+
+// SalesByProduct sums the daily products units and revenue (best sellers first)
+func (r *SalesRollupRepository) SalesByProduct(sr SalesRange) ([]ProductSales, error) {
+	var products []ProductSales
+	err := r.EachSalesByProduct(sr, func(product ProductSales) error {
+		products = append(products, product)
+		return nil
+	})
+	return products, err
+}
+
+// EachSalesByProduct streams the SalesByProduct rows to fn (the rows are not loaded in memory)
+func (r *SalesRollupRepository) EachSalesByProduct(sr SalesRange, fn func(product ProductSales) error) error {
+	rows, err := r.db.DB.Raw(`
+		SELECT
+			s.product_id,
+			COALESCE(pr.name, '') AS name,
+			COALESCE(pr.sku, '') AS sku,
+			MAX(s.category) AS category,
+			SUM(s.units) AS units,
+			SUM(s.revenue) AS revenue
+		FROM sales_daily_products s
+		LEFT JOIN products pr ON pr.id = s.product_id
+		WHERE s.day >= @from AND s.day < @to
+		GROUP BY s.product_id, pr.name, pr.sku
+		HAVING SUM(s.units) <> 0
+		ORDER BY units DESC, s.product_id`,
+		r.rollupArgs(sr),
+	).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product ProductSales
+		if err := r.db.DB.ScanRows(rows, &product); err != nil {
+			return err
+		}
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// SalesByCategory sums the daily categories units and revenue (best sellers first)
+func (r *SalesRollupRepository) SalesByCategory(sr SalesRange) ([]CategorySales, error) {
+	var categories []CategorySales
+	err := r.db.DB.Raw(`
+		SELECT
+			category,
+			SUM(units) AS units,
+			SUM(revenue) AS revenue
+		FROM sales_daily_categories
+		WHERE day >= @from AND day < @to
+		GROUP BY category
+		HAVING SUM(units) <> 0
+		ORDER BY units DESC, category`,
+		r.rollupArgs(sr),
+	).Scan(&categories).Error
+	return categories, err
+}
+
+// rollupArgs converts the range bounds to days (the rollup days have no time zone)
+func (r *SalesRollupRepository) rollupArgs(sr SalesRange) map[string]any {
+	return map[string]any{
+		"group_by": sr.GroupBy,
+		"from":     sr.From.Format(time.DateOnly),
+		"to":       sr.To.Format(time.DateOnly),
+	}
+}
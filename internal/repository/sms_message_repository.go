@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+)
+
+type SmsMessageRepository struct {
+	db *deps.GormDB
+}
+
+func NewSmsMessageRepository(db *deps.GormDB) *SmsMessageRepository {
+	return &SmsMessageRepository{
+		db: db,
+	}
+}
+
+// FindByProviderMessageID gets the sms message by the provider message id
+func (r *SmsMessageRepository) FindByProviderMessageID(provider, providerMessageID string) (*models.SmsMessage, error) {
+	var message models.SmsMessage
+	err := r.db.DB.Where("provider = ? AND provider_message_id = ?", provider, providerMessageID).First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// UpdateStatus updates the sms message if its status is one of the from statuses, returns false if it wasn't updated
+func (r *SmsMessageRepository) UpdateStatus(id uint, from []enums.NotificationStatus, data map[string]any) (bool, error) {
+	result := r.db.DB.Model(&models.SmsMessage{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(data)
+	return result.RowsAffected > 0, result.Error
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"taskgo/internal/database/models"
 	"taskgo/internal/deps"
+	"taskgo/internal/enums"
 	"time"
 )
 
@@ -49,6 +50,13 @@ func (r *UserRepository) FindById(id string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindActiveAdmins returns the active admins (e.g. notified of the store alerts)
+func (r *UserRepository) FindActiveAdmins() ([]models.User, error) {
+	var users []models.User
+	err := r.db.DB.Where("role = ? AND is_active = ?", enums.RoleAdmin, true).Find(&users).Error
+	return users, err
+}
+
 // Update user
 func (r *UserRepository) UpdateById(id string, data map[string]interface{}) error {
 	if id == "" {
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/filters"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *deps.GormDB
+}
+
+func NewWebhookRepository(db *deps.GormDB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// Create a new webhook subscription
+func (r *WebhookRepository) Create(subscription *models.WebhookSubscription) error {
+	return r.db.DB.Create(subscription).Error
+}
+
+// Get a webhook subscription by id
+func (r *WebhookRepository) FindById(id uint) (*models.WebhookSubscription, error) {
+	if id == 0 {
+		return nil, errors.New("id is required")
+	}
+
+	subscription := models.WebhookSubscription{}
+	if err := r.db.DB.Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// Get all the webhook subscriptions (latest first)
+func (r *WebhookRepository) FindAll() ([]*models.WebhookSubscription, error) {
+	var subscriptions []*models.WebhookSubscription
+	err := r.db.DB.Order("id desc").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Get the active webhook subscriptions
+func (r *WebhookRepository) FindActive() ([]*models.WebhookSubscription, error) {
+	var subscriptions []*models.WebhookSubscription
+	err := r.db.DB.Where("active = ?", true).Order("id asc").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Update a webhook subscription by id
+func (r *WebhookRepository) UpdateById(id uint, data map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("id is required")
+	}
+
+	return r.db.DB.Model(&models.WebhookSubscription{}).Where("id = ?", id).Updates(data).Error
+}
+
+// Delete a webhook subscription by id (its deliveries are kept)
+func (r *WebhookRepository) DeleteById(id uint) error {
+	if id == 0 {
+		return errors.New("id is required")
+	}
+
+	result := r.db.DB.Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IncrementFailures increments the consecutive failures of the subscription and returns the new count
+func (r *WebhookRepository) IncrementFailures(id uint) (int, error) {
+	var failureCount int
+	err := r.db.DB.Raw(
+		"UPDATE webhook_subscriptions SET failure_count = failure_count + 1, updated_at = NOW() WHERE id = ? RETURNING failure_count", id,
+	).Scan(&failureCount).Error
+	return failureCount, err
+}
+
+// Create a new webhook delivery
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.DB.Create(delivery).Error
+}
+
+// Paginate the deliveries of the subscription with filters (latest first)
+func (r *WebhookRepository) PaginateDeliveries(subscriptionID uint, f *filters.WebhookDeliveryFilters) ([]*models.WebhookDelivery, int64, error) {
+	var deliveries []*models.WebhookDelivery
+	var total int64
+
+	db := r.db.DB.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if f.Success != nil {
+		db = db.Where("success = ?", *f.Success)
+	}
+	if f.Event != "" {
+		db = db.Where("event_type = ?", f.Event)
+	}
+
+	// Get total count before pagination
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Set default values
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+
+	if f.PerPage <= 0 {
+		f.PerPage = 10
+	}
+
+	// Apply pagination
+	offset := (f.Page - 1) * f.PerPage
+	if err := db.Order("id desc").Offset(offset).Limit(f.PerPage).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
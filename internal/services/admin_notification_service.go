@@ -0,0 +1,37 @@
+package services
+
+import (
+	"taskgo/internal/repository"
+	"taskgo/pkg/notify"
+)
+
+// AdminNotificationService sends the store alerts (e.g. low stock, new orders) to the active admins
+type AdminNotificationService struct {
+	userRepository *repository.UserRepository
+	notifier       *notify.Notify // optional
+}
+
+func NewAdminNotificationService(userRepository *repository.UserRepository, notifier *notify.Notify) *AdminNotificationService {
+	return &AdminNotificationService{
+		userRepository: userRepository,
+		notifier:       notifier,
+	}
+}
+
+// Notify sends the notification to the active admins (nothing is sent if the notify module is not loaded)
+func (s *AdminNotificationService) Notify(notification notify.Notification) error {
+	if s.notifier == nil {
+		return nil
+	}
+
+	admins, err := s.userRepository.FindActiveAdmins()
+	if err != nil || len(admins) == 0 {
+		return err
+	}
+
+	notifiables := make([]notify.Notifiable, len(admins))
+	for i := range admins {
+		notifiables[i] = &admins[i]
+	}
+	return s.notifier.Send(notification, notifiables...)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	chainq "taskgo/pkg/asynq_chain"
+	pkgErrors "taskgo/pkg/errors"
+)
+
+type ChainService struct {
+	control chainq.Control
+}
+
+// Create a new chain service
+func NewChainService(control chainq.Control) *ChainService {
+	return &ChainService{control: control}
+}
+
+// CancelChain cancels the chain so the orchestrator skips its remaining steps
+func (s *ChainService) CancelChain(ctx context.Context, chainID string) error {
+	if err := s.ensureControl(); err != nil {
+		return err
+	}
+
+	if err := s.control.Cancel(ctx, chainID); err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to cancel the chain", "Internal Server Error: Failed to cancel chain "+chainID, err)
+	}
+	return nil
+}
+
+// PauseChain pauses the chain before running its next step
+func (s *ChainService) PauseChain(ctx context.Context, chainID string) error {
+	if err := s.ensureControl(); err != nil {
+		return err
+	}
+
+	if err := s.control.Pause(ctx, chainID); err != nil {
+		return pkgErrors.NewBadRequestError("Chain can't be paused", "Failed to pause chain "+chainID, err)
+	}
+	return nil
+}
+
+// ResumeChain resumes a paused chain
+func (s *ChainService) ResumeChain(ctx context.Context, chainID string) error {
+	if err := s.ensureControl(); err != nil {
+		return err
+	}
+
+	if err := s.control.Resume(ctx, chainID); err != nil {
+		return pkgErrors.NewBadRequestError("Chain can't be resumed", "Failed to resume chain "+chainID, err)
+	}
+	return nil
+}
+
+// PauseAllChains pauses all the chains (e.g. during payment provider outage)
+func (s *ChainService) PauseAllChains(ctx context.Context) error {
+	if err := s.ensureControl(); err != nil {
+		return err
+	}
+
+	if err := s.control.PauseAll(ctx); err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to pause chains", "Internal Server Error: Failed to pause all chains", err)
+	}
+	return nil
+}
+
+// ResumeAllChains resumes all the chains paused by PauseAllChains
+func (s *ChainService) ResumeAllChains(ctx context.Context) error {
+	if err := s.ensureControl(); err != nil {
+		return err
+	}
+
+	if err := s.control.ResumeAll(ctx); err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to resume chains", "Internal Server Error: Failed to resume all chains", err)
+	}
+	return nil
+}
+
+// GetChainState returns the chain state and if all chains are paused
+func (s *ChainService) GetChainState(ctx context.Context, chainID string) (chainq.ChainState, bool, error) {
+	if err := s.ensureControl(); err != nil {
+		return "", false, err
+	}
+
+	state, err := s.control.State(ctx, chainID)
+	if err != nil {
+		return "", false, pkgErrors.NewServerError("Internal Server Error: Failed to get chain state", "Internal Server Error: Failed to get chain state", err)
+	}
+
+	allPaused, err := s.control.IsAllPaused(ctx)
+	if err != nil {
+		return "", false, pkgErrors.NewServerError("Internal Server Error: Failed to get chain state", "Internal Server Error: Failed to check chains pause flag", err)
+	}
+
+	return state, allPaused, nil
+}
+
+func (s *ChainService) ensureControl() error {
+	if s.control == nil {
+		return pkgErrors.NewServerError("Internal Server Error: Chain control is not available", "Internal Server Error: chain control is nil (cache redis is not loaded)", nil)
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	chainq "taskgo/pkg/asynq_chain"
+	pkgErrors "taskgo/pkg/errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Dead Letter (failed tasks)
+|------------------------------------------
+|	1- The worker ErrorHandler calls RecordFailure on every failed attempt
+|	2- Every attempt is pushed to a redis list (attempts history) until the task is archived by asynq
+|	   (retries exhausted or asynq.SkipRetry), then the task is persisted as a FailedTask
+|	3- Admins can replay (optionally with an edited payload) or discard the failed tasks,
+|	   a failed chain step is replayed from the failed step since its orchestrator payload holds the current step
+|------------------------------------------
+*/
+
+type FailedTaskService struct {
+	failedTaskRepository *repository.FailedTaskRepository
+	client               *asynq.Client
+	inspector            *asynq.Inspector
+	redis                *redis.Client
+	attemptsTTL          time.Duration
+}
+
+// ReplayResult is the result of replaying a single failed task
+type ReplayResult struct {
+	ID     uint   `json:"id"`
+	TaskID string `json:"task_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Create a new failed task service
+func NewFailedTaskService(
+	failedTaskRepository *repository.FailedTaskRepository,
+	client *asynq.Client,
+	inspector *asynq.Inspector,
+	redis *redis.Client,
+	attemptsTTL time.Duration,
+) *FailedTaskService {
+	if attemptsTTL <= 0 {
+		attemptsTTL = 7 * 24 * time.Hour
+	}
+
+	return &FailedTaskService{
+		failedTaskRepository: failedTaskRepository,
+		client:               client,
+		inspector:            inspector,
+		redis:                redis,
+		attemptsTTL:          attemptsTTL,
+	}
+}
+
+// RecordFailure records a failed attempt of the task, the task is persisted once it's archived by the queue
+func (s *FailedTaskService) RecordFailure(ctx context.Context, task *asynq.Task, taskErr error) error {
+	taskID, _ := asynq.GetTaskID(ctx)
+	queue, _ := asynq.GetQueueName(ctx)
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	attempt := models.FailedTaskAttempt{
+		Attempt:  retryCount + 1,
+		Error:    taskErr.Error(),
+		FailedAt: time.Now(),
+	}
+
+	attempts := s.pushAttempt(taskID, attempt)
+
+	// asynq archives the task when it exhausted its retries or asked to skip retrying
+	if retryCount < maxRetry && !errors.Is(taskErr, asynq.SkipRetry) {
+		return nil
+	}
+
+	attemptsJson, err := json.Marshal(attempts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task attempts: %w", err)
+	}
+
+	errorChainJson, err := json.Marshal(errorChain(taskErr))
+	if err != nil {
+		return fmt.Errorf("failed to marshal task error chain: %w", err)
+	}
+
+	failedTask := &models.FailedTask{
+		TaskID:     taskID,
+		TaskType:   task.Type(),
+		Queue:      queue,
+		Payload:    string(task.Payload()),
+		LastError:  taskErr.Error(),
+		ErrorChain: string(errorChainJson),
+		Attempts:   string(attemptsJson),
+		RetryCount: retryCount,
+		MaxRetry:   maxRetry,
+		Status:     enums.FailedTaskStatusArchived,
+		FailedAt:   attempt.FailedAt,
+	}
+
+	// Keep the failed chain step so it's visible which step failed
+	if task.Type() == chainq.TypeChainOrchestrator {
+		var chainPayload chainq.ChainPayload
+		if err := json.Unmarshal(task.Payload(), &chainPayload); err == nil {
+			step := chainPayload.CurrentStep
+			failedTask.ChainID = chainPayload.ChainID
+			failedTask.ChainStep = &step
+		}
+	}
+
+	// Keep the raw bytes of the non json payloads so they can be replayed as they were
+	failedTask.PayloadEncoding = enums.FailedTaskPayloadJson
+	if !json.Valid(task.Payload()) {
+		encoded, err := json.Marshal(base64.StdEncoding.EncodeToString(task.Payload()))
+		if err != nil {
+			return fmt.Errorf("failed to encode task payload: %w", err)
+		}
+		failedTask.Payload = string(encoded)
+		failedTask.PayloadEncoding = enums.FailedTaskPayloadBase64
+	}
+
+	if err := s.failedTaskRepository.Create(failedTask); err != nil {
+		return fmt.Errorf("failed to persist failed task %s: %w", taskID, err)
+	}
+
+	s.clearAttempts(taskID)
+	return nil
+}
+
+// Get paginated failed tasks
+func (s *FailedTaskService) GetPaginatedFailedTasks(ctx context.Context, f *filters.FailedTaskFilters) ([]*models.FailedTask, int64, error) {
+	failedTasks, total, err := s.failedTaskRepository.Paginate(f)
+	if err != nil {
+		return nil, 0, pkgErrors.NewServerError("Internal Server Error: Failed to get the failed tasks", "Failed to paginate failed tasks", err)
+	}
+	return failedTasks, total, nil
+}
+
+// Get failed task by id
+func (s *FailedTaskService) GetFailedTaskById(ctx context.Context, id uint) (*models.FailedTask, error) {
+	failedTask, err := s.failedTaskRepository.FindById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgErrors.NewNotFoundError("NotFoundError: failed task not found", "NotFoundError: failed task not found", err)
+		}
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the failed task", "Failed to find failed task", err)
+	}
+	return failedTask, nil
+}
+
+// ReplayFailedTasks enqueues the archived failed tasks again (payload replaces the task payload if provided)
+func (s *FailedTaskService) ReplayFailedTasks(ctx context.Context, ids []uint, payload json.RawMessage) ([]ReplayResult, error) {
+	if s.client == nil || s.inspector == nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Queue is not available", "Queue client is not loaded", nil)
+	}
+
+	if len(payload) > 0 {
+		if len(ids) != 1 {
+			return nil, pkgErrors.NewValidationError(map[string]any{"payload": "Payload can only be edited when replaying a single task"})
+		}
+		if !json.Valid(payload) {
+			return nil, pkgErrors.NewValidationError(map[string]any{"payload": "Payload must be a valid JSON"})
+		}
+	}
+
+	failedTasks, err := s.findFailedTasks(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReplayResult, 0, len(failedTasks))
+	for _, failedTask := range failedTasks {
+		taskID, err := s.replay(ctx, &failedTask, payload)
+		result := ReplayResult{ID: failedTask.ID, TaskID: taskID}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// DiscardFailedTasks removes the archived tasks from the queue and marks them as discarded
+func (s *FailedTaskService) DiscardFailedTasks(ctx context.Context, ids []uint) ([]ReplayResult, error) {
+	failedTasks, err := s.findFailedTasks(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReplayResult, 0, len(failedTasks))
+	for _, failedTask := range failedTasks {
+		result := ReplayResult{ID: failedTask.ID, TaskID: failedTask.TaskID}
+		if err := s.discard(&failedTask); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (s *FailedTaskService) replay(ctx context.Context, failedTask *models.FailedTask, payload json.RawMessage) (string, error) {
+	if failedTask.Status != enums.FailedTaskStatusArchived {
+		return "", fmt.Errorf("failed task is %s and can't be replayed", failedTask.Status)
+	}
+
+	if len(payload) == 0 {
+		raw, err := failedTask.RawPayload()
+		if err != nil {
+			return "", err
+		}
+		payload = raw
+	}
+
+	// Remove the archived task first so the same task id can be used again (keeps chain steps deduplication)
+	if err := s.deleteArchivedTask(failedTask); err != nil {
+		return "", err
+	}
+
+	opts := []asynq.Option{
+		asynq.Queue(failedTask.Queue),
+		asynq.MaxRetry(failedTask.MaxRetry),
+		asynq.TaskID(failedTask.TaskID),
+	}
+
+	// Chain steps keep their timeout
+	if failedTask.TaskType == chainq.TypeChainOrchestrator {
+		var chainPayload chainq.ChainPayload
+		if err := json.Unmarshal(payload, &chainPayload); err != nil {
+			return "", fmt.Errorf("invalid chain payload: %w", err)
+		}
+		if chainPayload.Timeout > 0 {
+			opts = append(opts, asynq.Timeout(chainPayload.Timeout))
+		}
+	}
+
+	info, err := s.client.EnqueueContext(ctx, asynq.NewTask(failedTask.TaskType, payload), opts...)
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return "", fmt.Errorf("task %s is already enqueued", failedTask.TaskID)
+		}
+		return "", fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	now := time.Now()
+	err = s.failedTaskRepository.UpdateById(failedTask.ID, map[string]any{
+		"status":           enums.FailedTaskStatusReplayed,
+		"replay_count":     failedTask.ReplayCount + 1,
+		"replayed_task_id": info.ID,
+		"replayed_at":      &now,
+	})
+	if err != nil {
+		return info.ID, fmt.Errorf("task replayed but failed to update its status: %w", err)
+	}
+
+	return info.ID, nil
+}
+
+func (s *FailedTaskService) discard(failedTask *models.FailedTask) error {
+	if failedTask.Status != enums.FailedTaskStatusArchived {
+		return fmt.Errorf("failed task is %s and can't be discarded", failedTask.Status)
+	}
+
+	if err := s.deleteArchivedTask(failedTask); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.failedTaskRepository.UpdateById(failedTask.ID, map[string]any{
+		"status":       enums.FailedTaskStatusDiscarded,
+		"discarded_at": &now,
+	})
+}
+
+// deleteArchivedTask deletes the archived task from the queue (it may be already deleted by the queue retention)
+func (s *FailedTaskService) deleteArchivedTask(failedTask *models.FailedTask) error {
+	if s.inspector == nil {
+		return errors.New("queue inspector is not loaded")
+	}
+
+	err := s.inspector.DeleteTask(failedTask.Queue, failedTask.TaskID)
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+		return fmt.Errorf("failed to delete the archived task: %w", err)
+	}
+	return nil
+}
+
+func (s *FailedTaskService) findFailedTasks(ids []uint) ([]models.FailedTask, error) {
+	if len(ids) == 0 {
+		return nil, pkgErrors.NewValidationError(map[string]any{"ids": "At least one failed task id is required"})
+	}
+
+	failedTasks, err := s.failedTaskRepository.FindByIDs(ids)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the failed tasks", "Failed to find failed tasks by ids", err)
+	}
+	if len(failedTasks) == 0 {
+		return nil, pkgErrors.NewNotFoundError("NotFoundError: failed tasks not found", "NotFoundError: failed tasks not found", nil)
+	}
+
+	return failedTasks, nil
+}
+
+// pushAttempt adds the attempt to the task attempts history and returns the full history
+func (s *FailedTaskService) pushAttempt(taskID string, attempt models.FailedTaskAttempt) []models.FailedTaskAttempt {
+	if s.redis == nil || taskID == "" {
+		return []models.FailedTaskAttempt{attempt}
+	}
+
+	ctx := context.Background()
+	key := s.attemptsKey(taskID)
+
+	raw, err := json.Marshal(attempt)
+	if err != nil {
+		return []models.FailedTaskAttempt{attempt}
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.RPush(ctx, key, raw)
+	pipe.Expire(ctx, key, s.attemptsTTL)
+	rangeCmd := pipe.LRange(ctx, key, 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return []models.FailedTaskAttempt{attempt}
+	}
+
+	attempts := make([]models.FailedTaskAttempt, 0, len(rangeCmd.Val()))
+	for _, item := range rangeCmd.Val() {
+		var a models.FailedTaskAttempt
+		if err := json.Unmarshal([]byte(item), &a); err == nil {
+			attempts = append(attempts, a)
+		}
+	}
+
+	return attempts
+}
+
+func (s *FailedTaskService) clearAttempts(taskID string) {
+	if s.redis == nil || taskID == "" {
+		return
+	}
+	_ = s.redis.Del(context.Background(), s.attemptsKey(taskID)).Err()
+}
+
+func (s *FailedTaskService) attemptsKey(taskID string) string {
+	return "deadletter:attempts:" + taskID
+}
+
+// errorChain returns the messages of the wrapped errors (outer -> inner)
+func errorChain(err error) []string {
+	var chain []string
+	for err != nil {
+		// skip the wrappers that only add context without changing the message
+		if len(chain) == 0 || chain[len(chain)-1] != err.Error() {
+			chain = append(chain, err.Error())
+		}
+
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }: // errors.Join
+			for _, inner := range e.Unwrap() {
+				chain = append(chain, errorChain(inner)...)
+			}
+			return chain
+		default:
+			err = errors.Unwrap(err)
+		}
+	}
+	return chain
+}
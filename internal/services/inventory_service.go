@@ -3,21 +3,50 @@ package services
 import (
 	"context"
 	"taskgo/internal/database/models"
+	"taskgo/internal/events"
+	"taskgo/internal/notification"
 	"taskgo/internal/repository"
 )
 
 type InventoryService struct {
-	inventoryRepository *repository.InventoryRepository
-	productRepository   *repository.ProductRepository
+	inventoryRepository      *repository.InventoryRepository
+	productRepository        *repository.ProductRepository
+	adminNotificationService *AdminNotificationService
 }
 
-func NewInventoryService(inventoryRepository *repository.InventoryRepository, productRepository *repository.ProductRepository) *InventoryService {
+func NewInventoryService(inventoryRepository *repository.InventoryRepository, productRepository *repository.ProductRepository, adminNotificationService *AdminNotificationService) *InventoryService {
 	return &InventoryService{
-		inventoryRepository: inventoryRepository,
-		productRepository:   productRepository,
+		inventoryRepository:      inventoryRepository,
+		productRepository:        productRepository,
+		adminNotificationService: adminNotificationService,
 	}
 }
 
+// PublishLowStock publishes a stock low event for each product of the items which reached its reorder point
+// and alerts the admins (accumulated in the low stock digest)
+func (s *InventoryService) PublishLowStock(ctx context.Context, orderItems []models.OrderItem) error {
+	productIDs := make([]uint, len(orderItems))
+	for i, item := range orderItems {
+		productIDs[i] = item.ProductID
+	}
+
+	inventories, err := s.inventoryRepository.FindLowStockByProductIDs(productIDs)
+	if err != nil {
+		return err
+	}
+
+	var notifyErr error
+	for i := range inventories {
+		event, err := events.StockLow(&inventories[i])
+		events.Publish(ctx, event, err)
+
+		if err := s.adminNotificationService.Notify(notification.NewLowStockNotification(&inventories[i])); err != nil {
+			notifyErr = err
+		}
+	}
+	return notifyErr
+}
+
 // Reserve inventory for one product
 // func (s *InventoryService) ReserveInventory(ctx *gin.Context, product models.Product, item requests.OrderItemRequest) error {
 // 	cache, err := pkgRedis.Default()
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/notify"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Notifications deliveries
+|------------------------------------------
+|	Every (notification, channel) has a delivery record, the service is the notify delivery recorder:
+|	- pending when dispatched to the queue
+|	- sent or failed after every attempt (with the attempts count and the last error),
+|	  failed once the retries are exhausted (or skipped)
+|	The failed deliveries are resent by the admins with their recorded data
+|------------------------------------------
+*/
+
+type NotificationDeliveryService struct {
+	notificationDeliveryRepository *repository.NotificationDeliveryRepository
+	notify                         *notify.Notify // optional (resending)
+}
+
+func NewNotificationDeliveryService(notificationDeliveryRepo *repository.NotificationDeliveryRepository, notify *notify.Notify) *NotificationDeliveryService {
+	return &NotificationDeliveryService{
+		notificationDeliveryRepository: notificationDeliveryRepo,
+		notify:                         notify,
+	}
+}
+
+// Queued implements notify.DeliveryRecorder, the delivery is pending
+func (s *NotificationDeliveryService) Queued(ctx context.Context, task *notify.NotificationTask) error {
+	delivery, err := deliveryOf(task)
+	if err != nil {
+		return err
+	}
+	delivery.Status = enums.NotificationStatusPending
+	delivery.UpdatedAt = time.Now()
+	return s.notificationDeliveryRepository.Queued(delivery)
+}
+
+// Attempted implements notify.DeliveryRecorder, the delivery is sent or failed (pending while retried)
+func (s *NotificationDeliveryService) Attempted(ctx context.Context, task *notify.NotificationTask, attempt notify.Attempt) error {
+	delivery, err := deliveryOf(task)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch {
+	case attempt.Err == nil:
+		delivery.Status = enums.NotificationStatusSent
+		delivery.SentAt = &now
+	case attempt.Final:
+		delivery.Status = enums.NotificationStatusFailed
+		delivery.LastError = attempt.Err.Error()
+		delivery.FailedAt = &now
+	default:
+		delivery.Status = enums.NotificationStatusPending
+		delivery.LastError = attempt.Err.Error()
+	}
+	delivery.Attempts = 1
+	delivery.UpdatedAt = now
+
+	return s.notificationDeliveryRepository.Attempted(delivery)
+}
+
+// GetPaginatedDeliveries returns the deliveries (e.g. the failed ones)
+func (s *NotificationDeliveryService) GetPaginatedDeliveries(ctx context.Context, f *filters.NotificationDeliveryFilters) ([]*models.NotificationDelivery, int64, error) {
+	deliveries, total, err := s.notificationDeliveryRepository.Paginate(f)
+	if err != nil {
+		return nil, 0, pkgErrors.NewServerError("Internal Server Error: Failed to get the notification deliveries", "Failed to paginate the notification deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+// Resend dispatches the failed delivery again with its recorded data
+func (s *NotificationDeliveryService) Resend(ctx context.Context, id uint) (*models.NotificationDelivery, error) {
+	delivery, err := s.notificationDeliveryRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, pkgErrors.NewNotFoundError("notification delivery not found", "notification delivery not found", err)
+	}
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to resend the notification", "Failed to find the notification delivery", err)
+	}
+
+	if delivery.Status != enums.NotificationStatusFailed {
+		return nil, pkgErrors.NewValidationError(map[string]any{"status": "Only the failed deliveries can be resent"})
+	}
+	if s.notify == nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to resend the notification", "notify module is not loaded", nil)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(delivery.Data), &data); err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to resend the notification", "Failed to decode the notification delivery data", err)
+	}
+
+	task := &notify.NotificationTask{
+		ID:               delivery.NotificationID,
+		NotificationType: delivery.NotificationType,
+		NotifiableType:   delivery.NotifiableType,
+		NotifiableID:     delivery.NotifiableID,
+		Data:             data,
+		Channel:          delivery.Channel,
+		Redelivery:       delivery.Redeliveries,
+	}
+	if err := s.notify.Redeliver(task); err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to resend the notification", "Failed to dispatch the notification delivery", err)
+	}
+
+	delivery.Status = enums.NotificationStatusPending
+	delivery.Redeliveries = task.Redelivery
+	return delivery, nil
+}
+
+// deliveryOf returns the delivery record of the channel task
+func deliveryOf(task *notify.NotificationTask) (*models.NotificationDelivery, error) {
+	data, err := json.Marshal(task.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NotificationDelivery{
+		NotificationID:   task.ID,
+		Channel:          task.Channel,
+		NotificationType: task.NotificationType,
+		NotifiableID:     task.NotifiableID,
+		NotifiableType:   task.NotifiableType,
+		Data:             string(data),
+		Redeliveries:     task.Redelivery,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/notify"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Notifications preferences
+|------------------------------------------
+|	The users toggle the channels of every notification type and set its quiet hours,
+|	the service is the notify router:
+|	- the disabled channels are not sent
+|	- the quiet channels (sms, email) are delayed to the end of the quiet hours
+|	- the mandatory types (security) and the untyped notifications are sent on all their channels
+|------------------------------------------
+*/
+
+// typedNotification is a notification routed by the preferences of its type
+type typedNotification interface {
+	Type() enums.NotificationType
+}
+
+type NotificationPreferenceService struct {
+	notificationPreferenceRepository *repository.NotificationPreferenceRepository
+	timezone                         string   // quiet hours default timezone
+	quietChannels                    []string // channels delayed by the quiet hours
+}
+
+func NewNotificationPreferenceService(notificationPreferenceRepo *repository.NotificationPreferenceRepository, timezone string, quietChannels []string) *NotificationPreferenceService {
+	return &NotificationPreferenceService{
+		notificationPreferenceRepository: notificationPreferenceRepo,
+		timezone:                         timezone,
+		quietChannels:                    quietChannels,
+	}
+}
+
+// Preferences returns the user preferences of all the notification types (the defaults if not changed)
+func (s *NotificationPreferenceService) Preferences(ctx context.Context, user *models.User) ([]*models.NotificationPreference, error) {
+	stored, err := s.notificationPreferenceRepository.FindForUser(user.ID)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the notifications preferences", "Failed to find the user notifications preferences", err)
+	}
+
+	preferences := make([]*models.NotificationPreference, len(enums.NotificationTypes))
+	for i, notificationType := range enums.NotificationTypes {
+		preferences[i] = models.DefaultNotificationPreference(user.ID, notificationType)
+		for _, preference := range stored {
+			if preference.Type == notificationType {
+				preferences[i] = preference
+			}
+		}
+	}
+	return preferences, nil
+}
+
+// UpdatePreference changes the user preference of the notification type (the mandatory types can't be changed)
+func (s *NotificationPreferenceService) UpdatePreference(ctx context.Context, user *models.User, notificationType enums.NotificationType, req *requests.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+	if !slices.Contains(enums.NotificationTypes, notificationType) {
+		return nil, pkgErrors.NewNotFoundError("notification type not found", "notification type not found", nil)
+	}
+	if notificationType.Mandatory() {
+		return nil, pkgErrors.NewValidationError(map[string]any{"type": "The " + string(notificationType) + " notifications are mandatory, they can't be changed"})
+	}
+
+	preference, err := s.notificationPreferenceRepository.Find(user.ID, notificationType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preference, err = models.DefaultNotificationPreference(user.ID, notificationType), nil
+	}
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to update the notification preference", "Failed to find the user notification preference", err)
+	}
+
+	setIfSent(&preference.Database, req.Database)
+	setIfSent(&preference.WebSocket, req.WebSocket)
+	setIfSent(&preference.Email, req.Email)
+	setIfSent(&preference.SMS, req.SMS)
+	setIfSent(&preference.QuietHoursStart, req.QuietHoursStart)
+	setIfSent(&preference.QuietHoursEnd, req.QuietHoursEnd)
+	setIfSent(&preference.Timezone, req.Timezone)
+
+	if (preference.QuietHoursStart == "") != (preference.QuietHoursEnd == "") {
+		return nil, pkgErrors.NewValidationError(map[string]any{"quiet_hours": "Quiet hours require both the start and the end"})
+	}
+	if preference.Timezone != "" {
+		if _, err := time.LoadLocation(preference.Timezone); err != nil || preference.Timezone == "Local" {
+			return nil, pkgErrors.NewValidationError(map[string]any{"timezone": "Timezone must be a valid IANA time zone (e.g. Africa/Cairo)"})
+		}
+	}
+
+	if err := s.notificationPreferenceRepository.Upsert(preference); err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to update the notification preference", "Failed to save the user notification preference", err)
+	}
+	return preference, nil
+}
+
+// Route implements notify.Router, it filters the notification channels by the notifiable preferences
+func (s *NotificationPreferenceService) Route(ctx context.Context, notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error) {
+	routes := make([]notify.Route, 0, len(channels))
+
+	typed, ok := notification.(typedNotification)
+	user, isUser := notifiable.(*models.User)
+	if !ok || !isUser || typed.Type().Mandatory() {
+		for _, ch := range channels {
+			routes = append(routes, notify.Route{Channel: ch})
+		}
+		return routes, nil
+	}
+
+	preference, err := s.notificationPreferenceRepository.Find(user.ID, typed.Type())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preference, err = models.DefaultNotificationPreference(user.ID, typed.Type()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quietUntil := preference.QuietUntil(time.Now(), s.location(preference))
+	for _, ch := range channels {
+		if !preference.Enabled(ch) {
+			continue
+		}
+
+		route := notify.Route{Channel: ch}
+		if quietUntil != nil && slices.Contains(s.quietChannels, ch) {
+			route.At = quietUntil
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// location returns the quiet hours timezone of the preference
+func (s *NotificationPreferenceService) location(preference *models.NotificationPreference) *time.Location {
+	for _, timezone := range []string{preference.Timezone, s.timezone} {
+		if timezone == "" {
+			continue
+		}
+		if location, err := time.LoadLocation(timezone); err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+func setIfSent[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	notificationHandlers "taskgo/internal/notification/handlers"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/notify"
+	"taskgo/pkg/ws"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// The read notifications are pushed to the user websocket channel so the other tabs stay in sync
+const (
+	WsEventNotificationRead    = "notification.read"
+	WsEventNotificationAllRead = "notification.all_read"
+)
+
+type NotificationService struct {
+	notificationRepository *repository.NotificationRepository
+	relay                  ws.Relay // optional
+}
+
+func NewNotificationService(notificationRepo *repository.NotificationRepository, relay ws.Relay) *NotificationService {
+	return &NotificationService{
+		notificationRepository: notificationRepo,
+		relay:                  relay,
+	}
+}
+
+// GetPaginatedNotifications returns the user notifications (latest first)
+func (s *NotificationService) GetPaginatedNotifications(ctx context.Context, user *models.User, f *filters.NotificationFilters) ([]*models.Notification, int64, error) {
+	notifications, total, err := s.notificationRepository.PaginateForNotifiable(notify.NotifiableType(user), user.ID, f)
+	if err != nil {
+		return nil, 0, pkgErrors.NewServerError("Internal Server Error: Failed to get notifications", "Failed to paginate the user notifications", err)
+	}
+	return notifications, total, nil
+}
+
+// UnreadCount counts the user unread notifications
+func (s *NotificationService) UnreadCount(ctx context.Context, user *models.User) (int64, error) {
+	count, err := s.notificationRepository.CountUnread(notify.NotifiableType(user), user.ID)
+	if err != nil {
+		return 0, pkgErrors.NewServerError("Internal Server Error: Failed to count unread notifications", "Failed to count the user unread notifications", err)
+	}
+	return count, nil
+}
+
+// MarkAsRead marks the user notification read
+func (s *NotificationService) MarkAsRead(ctx context.Context, user *models.User, id uint) (*models.Notification, error) {
+	notifiableType := notify.NotifiableType(user)
+
+	notification, err := s.notificationRepository.FindForNotifiable(notifiableType, user.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, pkgErrors.NewNotFoundError("notification not found", "notification not found", err)
+	}
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to mark the notification read", "Failed to find the user notification", err)
+	}
+
+	// Already read
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	readAt := time.Now()
+	if err := s.notificationRepository.MarkAsRead(notifiableType, user.ID, id, readAt); err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to mark the notification read", "Failed to mark the user notification read", err)
+	}
+	notification.ReadAt = &readAt
+	notification.Status = enums.NotificationStatusRead
+
+	s.push(ctx, user, WsEventNotificationRead, map[string]any{"id": notification.ID, "read_at": readAt})
+	return notification, nil
+}
+
+// MarkAllAsRead marks all the user unread notifications read and returns their count
+func (s *NotificationService) MarkAllAsRead(ctx context.Context, user *models.User) (int64, error) {
+	readAt := time.Now()
+	count, err := s.notificationRepository.MarkAllAsRead(notify.NotifiableType(user), user.ID, readAt)
+	if err != nil {
+		return 0, pkgErrors.NewServerError("Internal Server Error: Failed to mark the notifications read", "Failed to mark all the user notifications read", err)
+	}
+
+	if count > 0 {
+		s.push(ctx, user, WsEventNotificationAllRead, map[string]any{"count": count, "read_at": readAt})
+	}
+	return count, nil
+}
+
+// DeleteNotification deletes the user notification
+func (s *NotificationService) DeleteNotification(ctx context.Context, user *models.User, id uint) error {
+	err := s.notificationRepository.DeleteForNotifiable(notify.NotifiableType(user), user.ID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkgErrors.NewNotFoundError("notification not found", "notification not found", err)
+	}
+	if err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to delete the notification", "Failed to delete the user notification", err)
+	}
+	return nil
+}
+
+// push sends the event with the user unread count to the user websocket channel (best effort)
+func (s *NotificationService) push(ctx context.Context, user *models.User, event string, data map[string]any) {
+	if s.relay == nil {
+		return
+	}
+
+	unread, err := s.notificationRepository.CountUnread(notify.NotifiableType(user), user.ID)
+	if err == nil {
+		data["unread_count"] = unread
+	}
+
+	err = s.relay.Publish(ctx, &ws.WSMessage{
+		Type:    event,
+		Channel: notificationHandlers.UserChannel(user.ID),
+		From:    "server",
+		Data:    data,
+	})
+	if err != nil {
+		deps.Log().Log().Warn("Failed to push the notifications read event", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"taskgo/internal/database/models"
+	"taskgo/internal/notification"
+	"taskgo/pkg/notify"
+)
+
+// OrderNotificationService notifies the customers of their orders lifecycle
+type OrderNotificationService struct {
+	notifier *notify.Notify // optional
+}
+
+func NewOrderNotificationService(notifier *notify.Notify) *OrderNotificationService {
+	return &OrderNotificationService{notifier: notifier}
+}
+
+// NotifyPlaced tells the customer the order was received
+func (s *OrderNotificationService) NotifyPlaced(order *models.Order) error {
+	return s.send(notification.NewOrderCreatedNotification(order.ID), order)
+}
+
+// NotifyStatusChange tells the customer the order status changed (order state machine hook)
+func (s *OrderNotificationService) NotifyStatusChange(ctx context.Context, change OrderStatusChange) error {
+	n := notification.NewOrderStatusNotification(change.Order, change.To, change.Reason)
+	if n == nil {
+		return nil
+	}
+	return s.send(n, change.Order)
+}
+
+// send sends the notification to the order customer (nothing is sent if the notify module is not loaded)
+func (s *OrderNotificationService) send(n notify.Notification, order *models.Order) error {
+	if s.notifier == nil {
+		return nil
+	}
+
+	customer := &models.User{Base: models.Base{ID: order.UserID}}
+	return s.notifier.Send(n, customer)
+}
@@ -2,24 +2,45 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"taskgo/internal/api/requests"
 	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/events"
+	"taskgo/internal/notification"
 	"taskgo/internal/repository"
+	chainq "taskgo/pkg/asynq_chain"
 	pkgErrors "taskgo/pkg/errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type OrderService struct {
-	inventoryService  *InventoryService
-	orderRepository   *repository.OrderRepository
-	productRepository *repository.ProductRepository
+	inventoryService         *InventoryService
+	outboxService            *OutboxService
+	adminNotificationService *AdminNotificationService
+	orderNotificationService *OrderNotificationService
+	stateMachine             *OrderStateMachine
+	orderRepository          *repository.OrderRepository
+	productRepository        *repository.ProductRepository
 }
 
-func NewOrderService(inventoryService *InventoryService, orderRepo *repository.OrderRepository, productRepo *repository.ProductRepository) *OrderService {
+// OrderTasksBuilder builds the task dispatched with the created order (e.g. the order processing chain)
+type OrderTasksBuilder func(order *models.Order) (*chainq.TaskMessage, error)
+
+func NewOrderService(inventoryService *InventoryService, outboxService *OutboxService, adminNotificationService *AdminNotificationService, orderNotificationService *OrderNotificationService, stateMachine *OrderStateMachine, orderRepo *repository.OrderRepository, productRepo *repository.ProductRepository) *OrderService {
 	return &OrderService{
-		inventoryService:  inventoryService,
-		orderRepository:   orderRepo,
-		productRepository: productRepo,
+		inventoryService:         inventoryService,
+		outboxService:            outboxService,
+		adminNotificationService: adminNotificationService,
+		orderNotificationService: orderNotificationService,
+		stateMachine:             stateMachine,
+		orderRepository:          orderRepo,
+		productRepository:        productRepo,
 	}
 }
 
@@ -40,7 +61,9 @@ func NewOrderService(inventoryService *InventoryService, orderRepo *repository.O
 // --------------------------------------------------------------------------------------------------------------------
 // Ok that's a problem reserve inventory should be for all the products in one transaction not for each product
 // err := s.inventoryService.ReserveInventory(product, item)
-func (s *OrderService) CreateOrder(ctx context.Context, req *requests.CreateOrderRequest) (*models.Order, error) {
+//
+// The task built by buildTask is written to the outbox in the order transaction so the order is never left without it
+func (s *OrderService) CreateOrder(ctx context.Context, req *requests.CreateOrderRequest, buildTask OrderTasksBuilder) (*models.Order, error) {
 	// Create base order
 	order := &models.Order{
 		UserID:          req.UserId,
@@ -89,21 +112,122 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *requests.CreateOrde
 		orderItems[i] = mapOrderItemData(&item, &product)
 	}
 
-	// Create order with order items after reserving inventory
-	err = s.orderRepository.CreateWithOrderItems(order, orderItems)
+	// Create order with order items after reserving inventory (and its task in the outbox)
+	var outboxMessage *models.OutboxMessage
+	err = s.orderRepository.CreateWithOrderItems(order, orderItems, func(tx *gorm.DB) error {
+		if buildTask == nil {
+			return nil
+		}
+
+		message, err := buildTask(order)
+		if err != nil {
+			return err
+		}
+
+		outboxMessage, err = s.outboxService.Add(tx, message)
+		return err
+	})
 	if err != nil {
 		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create order", "Internal Server Error: Failed to create order", err)
 	}
 
+	// Enqueue the order task right away, the outbox relay retries it if the queue is unavailable
+	if outboxMessage != nil {
+		if err := s.outboxService.DispatchById(ctx, outboxMessage.ID); err != nil {
+			deps.Log().Channel("queue_log").Warn("Order task left in the outbox for the relay",
+				zap.Uint("order_id", order.ID),
+				zap.Uint("outbox_id", outboxMessage.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Load order with order items so it can be returned in response
 	order.OrderItems = make([]models.OrderItem, len(orderItems))
 	for i, item := range orderItems {
 		order.OrderItems[i] = *item
 	}
 
+	event, err := events.OrderPlaced(order)
+	events.Publish(ctx, event, err)
+
+	// Tell the customer and alert the admins (accumulated in the new orders digest), the order is created even if it fails
+	if err := s.orderNotificationService.NotifyPlaced(order); err != nil {
+		deps.Log().Channel("default").Error("Failed to notify the customer of the new order", zap.Uint("order_id", order.ID), zap.Error(err))
+	}
+	if err := s.adminNotificationService.Notify(notification.NewNewOrderNotification(order)); err != nil {
+		deps.Log().Channel("default").Error("Failed to notify the admins of the new order", zap.Uint("order_id", order.ID), zap.Error(err))
+	}
+
 	return order, nil
 }
 
+// CancelOrder cancels the order of the customer (the state machine hooks drop its processing chain)
+func (s *OrderService) CancelOrder(ctx context.Context, orderID uint, userID uint) (*models.Order, error) {
+	order, err := s.orderRepository.FindById(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && order.UserID != userID) {
+		return nil, pkgErrors.NewNotFoundError("order not found", "order not found", err)
+	}
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the order", "Internal Server Error: Failed to find order", err)
+	}
+
+	return s.UpdateStatus(ctx, order.ID, enums.OrderStatusCancelled, "cancelled by the customer")
+}
+
+// UpdateStatus moves the order to the status if the transition is allowed then runs the state machine hooks,
+// updating the order to its current status does nothing (e.g. a retried task)
+func (s *OrderService) UpdateStatus(ctx context.Context, orderID uint, status enums.OrderStatus, reason string) (*models.Order, error) {
+	var change *OrderStatusChange
+
+	err := s.orderRepository.LockById(orderID, func(repo *repository.OrderRepository, order *models.Order) error {
+		if order.Status == status {
+			change = &OrderStatusChange{Order: order}
+			return nil
+		}
+
+		if !s.stateMachine.CanTransition(order.Status, status) {
+			return pkgErrors.NewValidationError(map[string]any{
+				"status": fmt.Sprintf("Order status can't be changed from %s to %s", order.Status, status),
+			})
+		}
+
+		data := map[string]interface{}{"status": status}
+		if status == enums.OrderStatusDelivered {
+			data["actual_delivery"] = time.Now()
+		}
+		if err := repo.UpdateById(order.ID, data); err != nil {
+			return err
+		}
+
+		change = &OrderStatusChange{Order: order, From: order.Status, To: status, Reason: reason}
+		order.Status = status
+
+		// Committed with the status (e.g. the tasks written to the outbox)
+		return s.stateMachine.FireTx(ctx, repo.DB(), *change)
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, pkgErrors.NewNotFoundError("order not found", "order not found", err)
+	}
+
+	var validationErr *pkgErrors.ValidationError
+	if errors.As(err, &validationErr) {
+		return nil, validationErr
+	}
+
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to update order status", "Internal Server Error: Failed to update order status", err)
+	}
+
+	// Run the hooks only if the status changed
+	if change.From != "" {
+		s.stateMachine.Fire(ctx, *change)
+	}
+
+	return change.Order, nil
+}
+
 func mapOrderItemData(item *requests.OrderItemRequest, product *models.Product) *models.OrderItem {
 	orderItem := &models.OrderItem{
 		ProductID: item.ProductId,
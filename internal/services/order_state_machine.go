@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Order state machine
+|------------------------------------------
+|	pending    -> confirmed | cancelled
+|	confirmed  -> processing | shipped | cancelled
+|	processing -> shipped | cancelled
+|	shipped    -> delivered | refunded
+|	delivered  -> refunded
+|	cancelled, refunded are final
+|
+|	Hooks run after the transition is committed (events, notifications, ...),
+|	a hook error is logged and doesn't revert the transition.
+|	Tx hooks run in the transaction of the transition (e.g. to write a task to the outbox: webhooks, rollups),
+|	a tx hook error reverts the transition
+|------------------------------------------
+*/
+
+// OrderStatusChange is a committed order status transition
+type OrderStatusChange struct {
+	Order  *models.Order
+	From   enums.OrderStatus
+	To     enums.OrderStatus
+	Reason string
+}
+
+// OrderStatusHook runs after an order status transition is committed
+type OrderStatusHook func(ctx context.Context, change OrderStatusChange) error
+
+// OrderStatusTxHook runs in the transaction of an order status transition
+type OrderStatusTxHook func(ctx context.Context, tx *gorm.DB, change OrderStatusChange) error
+
+type OrderStateMachine struct {
+	mu          sync.RWMutex
+	transitions map[enums.OrderStatus][]enums.OrderStatus
+	hooks       map[enums.OrderStatus][]OrderStatusHook   // hooks by target status ("" = all the transitions)
+	txHooks     map[enums.OrderStatus][]OrderStatusTxHook // tx hooks by target status ("" = all the transitions)
+}
+
+// Create a new order state machine with the order transitions
+func NewOrderStateMachine() *OrderStateMachine {
+	return &OrderStateMachine{
+		transitions: map[enums.OrderStatus][]enums.OrderStatus{
+			enums.OrderStatusPending:    {enums.OrderStatusConfirmed, enums.OrderStatusCancelled},
+			enums.OrderStatusConfirmed:  {enums.OrderStatusProcessing, enums.OrderStatusShipped, enums.OrderStatusCancelled},
+			enums.OrderStatusProcessing: {enums.OrderStatusShipped, enums.OrderStatusCancelled},
+			enums.OrderStatusShipped:    {enums.OrderStatusDelivered, enums.OrderStatusRefunded},
+			enums.OrderStatusDelivered:  {enums.OrderStatusRefunded},
+		},
+		hooks:   make(map[enums.OrderStatus][]OrderStatusHook),
+		txHooks: make(map[enums.OrderStatus][]OrderStatusTxHook),
+	}
+}
+
+// CanTransition checks if the order can move from a status to another
+func (m *OrderStateMachine) CanTransition(from, to enums.OrderStatus) bool {
+	for _, status := range m.transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OnEnter registers a hook running when an order enters the status
+func (m *OrderStateMachine) OnEnter(status enums.OrderStatus, hook OrderStatusHook) *OrderStateMachine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks[status] = append(m.hooks[status], hook)
+	return m
+}
+
+// OnTransition registers a hook running on every transition
+func (m *OrderStateMachine) OnTransition(hook OrderStatusHook) *OrderStateMachine {
+	return m.OnEnter("", hook)
+}
+
+// OnEnterTx registers a hook running in the transaction of the transition when an order enters the status
+func (m *OrderStateMachine) OnEnterTx(status enums.OrderStatus, hook OrderStatusTxHook) *OrderStateMachine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.txHooks[status] = append(m.txHooks[status], hook)
+	return m
+}
+
+// OnTransitionTx registers a hook running in the transaction of every transition
+func (m *OrderStateMachine) OnTransitionTx(hook OrderStatusTxHook) *OrderStateMachine {
+	return m.OnEnterTx("", hook)
+}
+
+// FireTx runs the tx hooks of the transition, the first error is returned so the transition is rolled back
+func (m *OrderStateMachine) FireTx(ctx context.Context, tx *gorm.DB, change OrderStatusChange) error {
+	m.mu.RLock()
+	hooks := append(append([]OrderStatusTxHook{}, m.txHooks[""]...), m.txHooks[change.To]...)
+	m.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, tx, change); err != nil {
+			return fmt.Errorf("order status %s hook failed: %w", change.To, err)
+		}
+	}
+	return nil
+}
+
+// Fire runs the hooks of the committed transition
+func (m *OrderStateMachine) Fire(ctx context.Context, change OrderStatusChange) {
+	m.mu.RLock()
+	hooks := append(append([]OrderStatusHook{}, m.hooks[""]...), m.hooks[change.To]...)
+	m.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, change); err != nil {
+			deps.Log().Channel("default").Error("Order status hook failed",
+				zap.Uint("order_id", change.Order.ID),
+				zap.String("from", string(change.From)),
+				zap.String("to", string(change.To)),
+				zap.Error(err),
+			)
+		}
+	}
+}
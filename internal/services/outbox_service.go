@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	chainq "taskgo/pkg/asynq_chain"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Transactional outbox
+|------------------------------------------
+|	1- The task is written to the outbox table in the same transaction as its data (e.g. the order)
+|	2- After the commit the task is enqueued right away (best effort)
+|	3- The relay (running in the workers) enqueues the pending messages left behind (e.g. redis was down),
+|	   the failed enqueues are retried with a growing delay
+|	The messages are claimed for a lease (committed) before they are enqueued so no transaction is held while enqueuing,
+|	a message left dispatching after its lease (e.g. the relay crashed) is claimed again by the next relay.
+|	The messages keep their asynq task id so an enqueue retried after a crash is deduplicated by the queue
+|------------------------------------------
+*/
+
+type OutboxService struct {
+	outboxRepository *repository.OutboxRepository
+	client           *asynq.Client
+	maxAttempts      int           // 0 = retry until the task is enqueued
+	retryDelay       time.Duration // delay between failed enqueues (grows with the attempts up to maxRetryDelay)
+	lease            time.Duration // how long a claimed message is skipped by the other relays
+}
+
+const maxOutboxRetryDelay = 5 * time.Minute
+
+// Create a new outbox service
+func NewOutboxService(outboxRepository *repository.OutboxRepository, client *asynq.Client, maxAttempts int, retryDelay, lease time.Duration) *OutboxService {
+	if retryDelay <= 0 {
+		retryDelay = 5 * time.Second
+	}
+	if lease <= 0 {
+		lease = time.Minute
+	}
+
+	return &OutboxService{
+		outboxRepository: outboxRepository,
+		client:           client,
+		maxAttempts:      maxAttempts,
+		retryDelay:       retryDelay,
+		lease:            lease,
+	}
+}
+
+// Add writes the task to the outbox inside the given transaction
+func (s *OutboxService) Add(tx *gorm.DB, message *chainq.TaskMessage) (*models.OutboxMessage, error) {
+	outboxMessage := &models.OutboxMessage{
+		TaskType:    message.Type,
+		TaskID:      message.TaskID,
+		Queue:       message.Queue,
+		Payload:     message.Payload,
+		MaxRetry:    message.MaxRetry,
+		Timeout:     message.Timeout,
+		Status:      enums.OutboxStatusPending,
+		AvailableAt: time.Now(),
+	}
+
+	if err := s.outboxRepository.WithTx(tx).Create(outboxMessage); err != nil {
+		return nil, err
+	}
+
+	return outboxMessage, nil
+}
+
+// DispatchById enqueues the outbox message right away (skipped if the relay is already enqueuing it)
+func (s *OutboxService) DispatchById(ctx context.Context, id uint) error {
+	messages, err := s.outboxRepository.ClaimPendingById(id, s.lease)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if err := s.dispatch(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Relay enqueues a batch of the due pending messages, returns the number of enqueued messages
+func (s *OutboxService) Relay(ctx context.Context, batchSize int) (int, error) {
+	messages, err := s.outboxRepository.ClaimPending(batchSize, s.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, message := range messages {
+		if err := s.dispatch(ctx, message); err == nil {
+			dispatched++
+		}
+	}
+
+	return dispatched, nil
+}
+
+// dispatch enqueues the message and updates its status (the failure is recorded for the next attempt)
+func (s *OutboxService) dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	if s.client == nil {
+		return errors.New("asynq client not initialized")
+	}
+
+	task := (&chainq.TaskMessage{
+		Type:     message.TaskType,
+		Payload:  message.Payload,
+		Queue:    message.Queue,
+		TaskID:   message.TaskID,
+		MaxRetry: message.MaxRetry,
+		Timeout:  message.Timeout,
+	}).Task()
+
+	_, enqueueErr := s.client.EnqueueContext(ctx, task)
+
+	// Already enqueued (e.g. the relay crashed before marking the message as dispatched)
+	if errors.Is(enqueueErr, asynq.ErrTaskIDConflict) {
+		enqueueErr = nil
+	}
+
+	now := time.Now()
+	if enqueueErr == nil {
+		return s.outboxRepository.UpdateById(message.ID, map[string]interface{}{
+			"status":        enums.OutboxStatusDispatched,
+			"attempts":      message.Attempts + 1,
+			"last_error":    "",
+			"dispatched_at": now,
+		})
+	}
+
+	attempts := message.Attempts + 1
+	status := enums.OutboxStatusPending
+	if s.maxAttempts > 0 && attempts >= s.maxAttempts {
+		status = enums.OutboxStatusFailed
+	}
+
+	deps.Log().Channel("queue_log").Error("Failed to enqueue outbox message",
+		zap.Uint("outbox_id", message.ID),
+		zap.String("task_type", message.TaskType),
+		zap.Int("attempts", attempts),
+		zap.String("status", string(status)),
+		zap.Error(enqueueErr),
+	)
+
+	if err := s.outboxRepository.UpdateById(message.ID, map[string]interface{}{
+		"status":       status,
+		"attempts":     attempts,
+		"last_error":   enqueueErr.Error(),
+		"available_at": now.Add(min(s.retryDelay*time.Duration(attempts), maxOutboxRetryDelay)),
+	}); err != nil {
+		return err
+	}
+
+	return enqueueErr
+}
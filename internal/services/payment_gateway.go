@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"taskgo/internal/database/models"
+)
+
+// PaymentGateway charges the orders through the payment provider
+type PaymentGateway interface {
+	// Charge captures the order payment, the idempotency key is sent to the provider so a re-run can't double charge
+	Charge(ctx context.Context, order *models.Order, idempotencyKey string) error
+}
+
+// ManualPaymentGateway accepts every payment (collected outside of the app until a payment provider is integrated)
+type ManualPaymentGateway struct{}
+
+func NewManualPaymentGateway() *ManualPaymentGateway {
+	return &ManualPaymentGateway{}
+}
+
+func (g *ManualPaymentGateway) Charge(ctx context.Context, order *models.Order, idempotencyKey string) error {
+	return nil
+}
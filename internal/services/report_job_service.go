@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	chainq "taskgo/pkg/asynq_chain"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/xlsx"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Report jobs (asynchronous exports)
+|------------------------------------------
+|	1- The job is created (queued) with its task written to the outbox in the same transaction
+|	2- The worker generates the report (running) and streams it to the file store as CSV or XLSX
+|	3- The job is done with its file (or failed after the task retries), the requester is notified
+|------------------------------------------
+*/
+
+// ReportTaskBuilder builds the task generating the report job
+type ReportTaskBuilder func(job *models.ReportJob) (*chainq.TaskMessage, error)
+
+// CreateReportJob validates the report params then queues the report job
+func (s *ReportService) CreateReportJob(ctx context.Context, userID uint, req *requests.CreateReportJobRequest, buildTask ReportTaskBuilder) (*models.ReportJob, error) {
+	reportFilters := filters.SalesReportFilters{From: req.From, To: req.To, GroupBy: req.GroupBy, Timezone: req.Timezone}
+	if _, err := s.salesRange(&reportFilters); err != nil {
+		return nil, err
+	}
+
+	params, err := json.Marshal(reportFilters)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the report", "Failed to marshal report params", err)
+	}
+
+	job := &models.ReportJob{
+		UserID: userID,
+		Type:   enums.ReportType(req.Type),
+		Format: enums.ReportFormat(req.Format),
+		Params: string(params),
+		Status: enums.ReportJobStatusQueued,
+	}
+
+	var outboxMessage *models.OutboxMessage
+	err = s.reportJobRepository.CreateWithTx(job, func(tx *gorm.DB) error {
+		message, err := buildTask(job)
+		if err != nil {
+			return err
+		}
+
+		outboxMessage, err = s.outboxService.Add(tx, message)
+		return err
+	})
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the report", "Failed to create report job", err)
+	}
+
+	// Enqueue the report task right away, the outbox relay retries it if the queue is unavailable
+	if err := s.outboxService.DispatchById(ctx, outboxMessage.ID); err != nil {
+		deps.Log().Channel("queue_log").Warn("Report task left in the outbox for the relay",
+			zap.Uint("report_job_id", job.ID),
+			zap.Uint("outbox_id", outboxMessage.ID),
+			zap.Error(err),
+		)
+	}
+
+	return job, nil
+}
+
+// CreateDailyReportJob queues the sales report of the day before now (in the reports time zone)
+func (s *ReportService) CreateDailyReportJob(ctx context.Context, userID uint, format string, now time.Time, buildTask ReportTaskBuilder) (*models.ReportJob, error) {
+	loc, err := time.LoadLocation(s.timezone)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the report", "Invalid reports time zone", err)
+	}
+
+	day := now.In(loc).AddDate(0, 0, -1).Format(reportDateLayout)
+	return s.CreateReportJob(ctx, userID, &requests.CreateReportJobRequest{
+		Type:     string(enums.ReportTypeSales),
+		Format:   format,
+		From:     day,
+		To:       day,
+		GroupBy:  ReportGroupByDay,
+		Timezone: s.timezone,
+	}, buildTask)
+}
+
+// GetReportJobById returns the report job
+func (s *ReportService) GetReportJobById(ctx context.Context, id uint) (*models.ReportJob, error) {
+	job, err := s.reportJobRepository.FindById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgErrors.NewNotFoundError("NotFoundError: report not found", "NotFoundError: report not found", err)
+		}
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the report", "Failed to find report job", err)
+	}
+	return job, nil
+}
+
+// OpenReportFile opens the file of the done report job
+func (s *ReportService) OpenReportFile(ctx context.Context, id uint) (*models.ReportJob, io.ReadCloser, error) {
+	job, err := s.GetReportJobById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != enums.ReportJobStatusDone {
+		return nil, nil, pkgErrors.NewValidationError(map[string]any{"status": fmt.Sprintf("Report is %s and has no file yet", job.Status)})
+	}
+
+	file, err := s.store.Open(ctx, job.FilePath)
+	if err != nil {
+		return nil, nil, pkgErrors.NewServerError("Internal Server Error: Failed to open the report file", "Failed to open report file", err)
+	}
+	return job, file, nil
+}
+
+// RunReportJob generates the report job file, the job is marked as failed on the last attempt
+// (queued again otherwise so the task retry runs it)
+func (s *ReportService) RunReportJob(ctx context.Context, id uint, lastAttempt bool) (*models.ReportJob, error) {
+	job, err := s.reportJobRepository.FindById(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find report job %d: %w", id, err)
+	}
+
+	// Already generated (e.g. the task was delivered twice)
+	if job.Status == enums.ReportJobStatusDone {
+		return job, nil
+	}
+
+	startedAt := time.Now()
+	err = s.reportJobRepository.UpdateById(job.ID, map[string]any{
+		"status":     enums.ReportJobStatusRunning,
+		"attempts":   job.Attempts + 1,
+		"started_at": &startedAt,
+		"error":      "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start report job %d: %w", id, err)
+	}
+
+	fileName, filePath, fileSize, runErr := s.exportReport(ctx, job)
+
+	finishedAt := time.Now()
+	if runErr != nil {
+		status := enums.ReportJobStatusQueued
+		if lastAttempt || errors.As(runErr, new(*pkgErrors.ValidationError)) {
+			status = enums.ReportJobStatusFailed
+		}
+
+		if err := s.reportJobRepository.UpdateById(job.ID, map[string]any{"status": status, "error": runErr.Error(), "finished_at": &finishedAt}); err != nil {
+			deps.Log().Channel("queue_log").Error("Failed to update failed report job", zap.Uint("report_job_id", job.ID), zap.Error(err))
+		}
+		return nil, runErr
+	}
+
+	err = s.reportJobRepository.UpdateById(job.ID, map[string]any{
+		"status":      enums.ReportJobStatusDone,
+		"file_name":   fileName,
+		"file_path":   filePath,
+		"file_size":   fileSize,
+		"finished_at": &finishedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete report job %d: %w", id, err)
+	}
+
+	return s.reportJobRepository.FindById(job.ID)
+}
+
+// exportReport streams the report rows to the file store
+func (s *ReportService) exportReport(ctx context.Context, job *models.ReportJob) (string, string, int64, error) {
+	var reportFilters filters.SalesReportFilters
+	if err := json.Unmarshal([]byte(job.Params), &reportFilters); err != nil {
+		return "", "", 0, fmt.Errorf("invalid report params: %w", err)
+	}
+
+	salesRange, err := s.salesRange(&reportFilters)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	report, err := s.salesReport(salesRange, false)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	fileName := fmt.Sprintf("%s-report-%s-%s.%s", job.Type, report.From, report.To, job.Format)
+	filePath := fmt.Sprintf("reports/%d/%s", job.ID, fileName)
+
+	file, err := s.store.Create(ctx, filePath)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	rows, err := newReportRowWriter(job.Format, file)
+	if err == nil {
+		err = s.writeSalesReport(rows, report, salesRange)
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		file.Abort()
+		return "", "", 0, fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return "", "", 0, err
+	}
+	return fileName, filePath, file.Size(), nil
+}
+
+// writeSalesReport writes the report sections (the products are streamed from the database)
+func (s *ReportService) writeSalesReport(rows reportRowWriter, report *SalesReport, salesRange repository.SalesRange) error {
+	write := func(values ...any) error { return rows.WriteRow(values) }
+
+	meta := [][]any{
+		{"Sales report"},
+		{"From", report.From},
+		{"To", report.To},
+		{"Group by", report.GroupBy},
+		{"Timezone", report.Timezone},
+		{},
+		{"Summary"},
+		{"Revenue", report.Summary.Revenue},
+		{"Orders", report.Summary.OrdersCount},
+		{"Average order value", report.Summary.AverageOrderValue},
+		{"Units sold", report.Summary.UnitsSold},
+		{"Paid amount", report.Summary.PaidAmount},
+		{"Cancelled orders", report.Summary.CancelledCount},
+		{"Cancelled amount", report.Summary.CancelledAmount},
+		{"Refunded orders", report.Summary.RefundedCount},
+		{"Refunded amount", report.Summary.RefundedAmount},
+		{},
+		{"Periods"},
+		{"period_start", "revenue", "orders_count", "average_order_value", "units_sold", "paid_amount", "cancelled_count", "cancelled_amount", "refunded_count", "refunded_amount"},
+	}
+	for _, row := range meta {
+		if err := rows.WriteRow(row); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range report.Periods {
+		if err := write(p.PeriodStart, p.Revenue, p.OrdersCount, p.AverageOrderValue, p.UnitsSold, p.PaidAmount, p.CancelledCount, p.CancelledAmount, p.RefundedCount, p.RefundedAmount); err != nil {
+			return err
+		}
+	}
+
+	if err := write(); err != nil {
+		return err
+	}
+	if err := write("Categories"); err != nil {
+		return err
+	}
+	if err := write("category", "units", "revenue"); err != nil {
+		return err
+	}
+	for _, c := range report.Categories {
+		if err := write(c.Category, c.Units, c.Revenue); err != nil {
+			return err
+		}
+	}
+
+	if err := write(); err != nil {
+		return err
+	}
+	if err := write("Products"); err != nil {
+		return err
+	}
+	if err := write("product_id", "name", "sku", "category", "units", "revenue"); err != nil {
+		return err
+	}
+	return s.salesAggregates(salesRange).EachSalesByProduct(salesRange, func(p repository.ProductSales) error {
+		return write(p.ProductID, p.Name, p.SKU, p.Category, p.Units, p.Revenue)
+	})
+}
+
+/*
+|------------------------------------------
+|  Report rows writers (csv, xlsx)
+|------------------------------------------
+*/
+
+type reportRowWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+func newReportRowWriter(format enums.ReportFormat, w io.Writer) (reportRowWriter, error) {
+	switch format {
+	case enums.ReportFormatCSV:
+		return &csvRowWriter{csv: csv.NewWriter(w)}, nil
+	case enums.ReportFormatXLSX:
+		return xlsx.NewWriter(w, "Report")
+	default:
+		return nil, pkgErrors.NewValidationError(map[string]any{"format": fmt.Sprintf("Unsupported report format %s", format)})
+	}
+}
+
+type csvRowWriter struct {
+	csv *csv.Writer
+}
+
+func (w *csvRowWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			record[i] = v
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return w.csv.Write(record)
+}
+
+func (w *csvRowWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/filestore"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Sales reports
+|------------------------------------------
+|	Orders are reported by their creation date in the report time zone:
+|	- revenue, orders count, average order value and units: confirmed, processing, shipped and delivered orders
+|	- cancellations and refunds are reported apart (refunded amount = payment refund amount or the order total)
+|	The reports read the daily sales rollups when enabled and the report time zone is the rollups one,
+|	otherwise the aggregates run over the raw orders
+|------------------------------------------
+*/
+
+const (
+	ReportGroupByDay   = "day"
+	ReportGroupByWeek  = "week"
+	ReportGroupByMonth = "month"
+
+	reportDateLayout = "2006-01-02"
+)
+
+type ReportService struct {
+	reportRepository      *repository.ReportRepository
+	reportJobRepository   *repository.ReportJobRepository
+	salesRollupRepository *repository.SalesRollupRepository
+	outboxService         *OutboxService
+	store                 filestore.Store
+	timezone              string
+	maxRangeDays          int
+	useRollups            bool
+}
+
+// salesAggregates are the sales report rows (from the raw orders or the daily rollups)
+type salesAggregates interface {
+	SalesByPeriod(sr repository.SalesRange) ([]repository.SalesPeriod, error)
+	SalesByProduct(sr repository.SalesRange) ([]repository.ProductSales, error)
+	EachSalesByProduct(sr repository.SalesRange, fn func(product repository.ProductSales) error) error
+	SalesByCategory(sr repository.SalesRange) ([]repository.CategorySales, error)
+}
+
+// SalesSummary is the sales totals of the report range
+type SalesSummary struct {
+	Revenue           float64 `json:"revenue"`
+	OrdersCount       int64   `json:"orders_count"`
+	AverageOrderValue float64 `json:"average_order_value"`
+	UnitsSold         int64   `json:"units_sold"`
+	PaidAmount        float64 `json:"paid_amount"`
+	CancelledCount    int64   `json:"cancelled_count"`
+	CancelledAmount   float64 `json:"cancelled_amount"`
+	RefundedCount     int64   `json:"refunded_count"`
+	RefundedAmount    float64 `json:"refunded_amount"`
+}
+
+// SalesReportPeriod is the sales of a period with its average order value
+type SalesReportPeriod struct {
+	repository.SalesPeriod
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// SalesReport is the sales report of a date range
+type SalesReport struct {
+	From       string                     `json:"from"`
+	To         string                     `json:"to"`
+	GroupBy    string                     `json:"group_by"`
+	Timezone   string                     `json:"timezone"`
+	Summary    SalesSummary               `json:"summary"`
+	Periods    []SalesReportPeriod        `json:"periods"`
+	Products   []repository.ProductSales  `json:"products"`
+	Categories []repository.CategorySales `json:"categories"`
+}
+
+// Create a new report service
+func NewReportService(
+	reportRepository *repository.ReportRepository,
+	reportJobRepository *repository.ReportJobRepository,
+	salesRollupRepository *repository.SalesRollupRepository,
+	outboxService *OutboxService,
+	store filestore.Store,
+	timezone string,
+	maxRangeDays int,
+	useRollups bool,
+) *ReportService {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	return &ReportService{
+		reportRepository:      reportRepository,
+		reportJobRepository:   reportJobRepository,
+		salesRollupRepository: salesRollupRepository,
+		outboxService:         outboxService,
+		store:                 store,
+		timezone:              timezone,
+		maxRangeDays:          maxRangeDays,
+		useRollups:            useRollups && salesRollupRepository != nil,
+	}
+}
+
+// SalesReport computes the sales report of the filters range
+func (s *ReportService) SalesReport(ctx context.Context, f *filters.SalesReportFilters) (*SalesReport, error) {
+	salesRange, err := s.salesRange(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.salesReport(salesRange, true)
+}
+
+// salesReport computes the sales report of the range (the products are streamed apart by the exports)
+func (s *ReportService) salesReport(salesRange repository.SalesRange, withProducts bool) (*SalesReport, error) {
+	sales := s.salesAggregates(salesRange)
+
+	periods, err := sales.SalesByPeriod(salesRange)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to generate the sales report", "Failed to aggregate sales by period", err)
+	}
+
+	var products []repository.ProductSales
+	if withProducts {
+		products, err = sales.SalesByProduct(salesRange)
+		if err != nil {
+			return nil, pkgErrors.NewServerError("Internal Server Error: Failed to generate the sales report", "Failed to aggregate sales by product", err)
+		}
+	}
+
+	categories, err := sales.SalesByCategory(salesRange)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to generate the sales report", "Failed to aggregate sales by category", err)
+	}
+
+	report := &SalesReport{
+		From:       salesRange.From.Format(reportDateLayout),
+		To:         salesRange.To.AddDate(0, 0, -1).Format(reportDateLayout),
+		GroupBy:    salesRange.GroupBy,
+		Timezone:   salesRange.Timezone,
+		Periods:    fillSalesPeriods(salesRange, periods),
+		Products:   products,
+		Categories: categories,
+	}
+
+	for _, period := range report.Periods {
+		report.Summary.Revenue += period.Revenue
+		report.Summary.OrdersCount += period.OrdersCount
+		report.Summary.UnitsSold += period.UnitsSold
+		report.Summary.PaidAmount += period.PaidAmount
+		report.Summary.CancelledCount += period.CancelledCount
+		report.Summary.CancelledAmount += period.CancelledAmount
+		report.Summary.RefundedCount += period.RefundedCount
+		report.Summary.RefundedAmount += period.RefundedAmount
+	}
+	report.Summary.Revenue = roundAmount(report.Summary.Revenue)
+	report.Summary.PaidAmount = roundAmount(report.Summary.PaidAmount)
+	report.Summary.CancelledAmount = roundAmount(report.Summary.CancelledAmount)
+	report.Summary.RefundedAmount = roundAmount(report.Summary.RefundedAmount)
+	report.Summary.AverageOrderValue = averageOrderValue(report.Summary.Revenue, report.Summary.OrdersCount)
+
+	return report, nil
+}
+
+// salesAggregates reads the daily rollups if the range days are the rollups days (same time zone)
+func (s *ReportService) salesAggregates(salesRange repository.SalesRange) salesAggregates {
+	if s.useRollups && salesRange.Timezone == s.timezone {
+		return s.salesRollupRepository
+	}
+	return s.reportRepository
+}
+
+// salesRange validates the filters and returns the range [from 00:00, to + 1 day 00:00) in the time zone
+func (s *ReportService) salesRange(f *filters.SalesReportFilters) (repository.SalesRange, error) {
+	groupBy := f.GroupBy
+	if groupBy == "" {
+		groupBy = ReportGroupByDay
+	}
+	if groupBy != ReportGroupByDay && groupBy != ReportGroupByWeek && groupBy != ReportGroupByMonth {
+		return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"group_by": "Group by must be one of day, week, month"})
+	}
+
+	timezone := f.Timezone
+	if timezone == "" {
+		timezone = s.timezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"timezone": "Timezone must be a valid IANA time zone (e.g. Africa/Cairo)"})
+	}
+
+	now := time.Now().In(location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	if f.To != "" {
+		if to, err = time.ParseInLocation(reportDateLayout, f.To, location); err != nil {
+			return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"to": "To must be a date (YYYY-MM-DD)"})
+		}
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if f.From != "" {
+		if from, err = time.ParseInLocation(reportDateLayout, f.From, location); err != nil {
+			return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"from": "From must be a date (YYYY-MM-DD)"})
+		}
+	}
+
+	if from.After(to) {
+		return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"from": "From must be before or equal to to"})
+	}
+	if s.maxRangeDays > 0 && to.Sub(from) >= time.Duration(s.maxRangeDays)*24*time.Hour {
+		return repository.SalesRange{}, pkgErrors.NewValidationError(map[string]any{"from": fmt.Sprintf("The report range must be at most %d days", s.maxRangeDays)})
+	}
+
+	return repository.SalesRange{
+		From:     from,
+		To:       to.AddDate(0, 0, 1),
+		GroupBy:  groupBy,
+		Timezone: location.String(),
+	}, nil
+}
+
+// fillSalesPeriods returns a period for every day, week or month of the range (periods without orders are zeroed)
+func fillSalesPeriods(salesRange repository.SalesRange, periods []repository.SalesPeriod) []SalesReportPeriod {
+	byStart := make(map[string]repository.SalesPeriod, len(periods))
+	for _, period := range periods {
+		byStart[period.PeriodStart] = period
+	}
+
+	var filled []SalesReportPeriod
+	for start := periodStart(salesRange.From, salesRange.GroupBy); start.Before(salesRange.To); start = nextPeriod(start, salesRange.GroupBy) {
+		key := start.Format(reportDateLayout)
+		period, ok := byStart[key]
+		if !ok {
+			period = repository.SalesPeriod{PeriodStart: key}
+		}
+
+		filled = append(filled, SalesReportPeriod{
+			SalesPeriod:       period,
+			AverageOrderValue: averageOrderValue(period.Revenue, period.OrdersCount),
+		})
+	}
+	return filled
+}
+
+// periodStart truncates the date to its period (weeks start on monday like postgres date_trunc)
+func periodStart(date time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case ReportGroupByWeek:
+		daysSinceMonday := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -daysSinceMonday)
+	case ReportGroupByMonth:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	default:
+		return date
+	}
+}
+
+func nextPeriod(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case ReportGroupByWeek:
+		return start.AddDate(0, 0, 7)
+	case ReportGroupByMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func averageOrderValue(revenue float64, ordersCount int64) float64 {
+	if ordersCount == 0 {
+		return 0
+	}
+	return roundAmount(revenue / float64(ordersCount))
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"taskgo/internal/database/models"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Sales rollups
+|------------------------------------------
+|	Each order is counted once in the day of its creation (in the rollups time zone) under a bucket:
+|	- sold: confirmed, processing, shipped and delivered orders (revenue, orders count, units, products and categories)
+|	- cancelled / refunded: cancellations and refunds
+|	Applying an order is idempotent, counting it again (e.g. confirmed then refunded) subtracts its previous values first
+|	The rollups are rebuilt from the orders with: go run ./cmd/rollups rebuild
+|	the reports read them only once reports.rollups.enabled is set (the orders placed before are missing otherwise)
+|------------------------------------------
+*/
+
+const (
+	SalesRollupBucketSold      = "sold"
+	SalesRollupBucketCancelled = "cancelled"
+	SalesRollupBucketRefunded  = "refunded"
+
+	salesRollupRebuildBatch = 500
+)
+
+type SalesRollupService struct {
+	salesRollupRepository *repository.SalesRollupRepository
+	location              *time.Location
+}
+
+func NewSalesRollupService(salesRollupRepo *repository.SalesRollupRepository, timezone string) *SalesRollupService {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &SalesRollupService{
+		salesRollupRepository: salesRollupRepo,
+		location:              location,
+	}
+}
+
+// Timezone is the time zone of the rollup days
+func (s *SalesRollupService) Timezone() string {
+	return s.location.String()
+}
+
+// ApplyOrder counts the order in the rollup bucket of its current status,
+// applying an order already counted with the same bucket and amounts does nothing
+func (s *SalesRollupService) ApplyOrder(ctx context.Context, orderID uint) error {
+	err := s.salesRollupRepository.LockOrder(orderID, func(repo *repository.SalesRollupRepository, order *models.Order, tracked *models.SalesRollupOrder) error {
+		rollup := s.orderRollup(order)
+		if tracked == nil && rollup == nil {
+			return nil
+		}
+		if tracked != nil && rollup != nil && tracked.Bucket == rollup.Bucket && tracked.Day.Equal(rollup.Day) &&
+			tracked.Amount == rollup.Amount && tracked.PaidAmount == rollup.PaidAmount {
+			return nil
+		}
+
+		// Subtract what the order was counted for (e.g. confirmed then refunded)
+		if tracked != nil {
+			if err := s.addRollup(repo, order, tracked, -1); err != nil {
+				return err
+			}
+		}
+
+		if rollup == nil {
+			return repo.DeleteRollupOrder(order.ID)
+		}
+
+		if err := s.addRollup(repo, order, rollup, 1); err != nil {
+			return err
+		}
+		return repo.SaveRollupOrder(rollup)
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkgErrors.NewNotFoundError("order not found", "order not found", err)
+	}
+	if err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to update the sales rollups", "Failed to apply the order to the sales rollups", err)
+	}
+	return nil
+}
+
+// Rebuild empties the rollups then applies all the confirmed, cancelled and refunded orders again,
+// progress is called after each batch with the count of applied orders
+func (s *SalesRollupService) Rebuild(ctx context.Context, progress func(applied int)) error {
+	if err := s.salesRollupRepository.Truncate(); err != nil {
+		return pkgErrors.NewServerError("Internal Server Error: Failed to rebuild the sales rollups", "Failed to truncate the sales rollups", err)
+	}
+
+	applied := 0
+	return s.salesRollupRepository.EachRollupOrderIDs(salesRollupRebuildBatch, func(ids []uint) error {
+		for _, id := range ids {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.ApplyOrder(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		applied += len(ids)
+		if progress != nil {
+			progress(applied)
+		}
+		return nil
+	})
+}
+
+// orderRollup is what the order is counted for in its current status (nil for the orders not counted yet)
+func (s *SalesRollupService) orderRollup(order *models.Order) *models.SalesRollupOrder {
+	rollup := &models.SalesRollupOrder{OrderID: order.ID, Day: s.orderDay(order), Amount: order.TotalAmount}
+	if order.Payment.Status == enums.PaymentStatusPaid || order.Payment.Status == enums.PaymentStatusRefunded {
+		rollup.PaidAmount = order.Payment.Amount
+	}
+
+	switch {
+	case order.Status == enums.OrderStatusCancelled:
+		rollup.Bucket = SalesRollupBucketCancelled
+	case order.Status == enums.OrderStatusRefunded:
+		rollup.Bucket = SalesRollupBucketRefunded
+		if order.Payment.RefundAmount != 0 {
+			rollup.Amount = order.Payment.RefundAmount
+		}
+	case slices.Contains(repository.SoldOrderStatuses, order.Status):
+		rollup.Bucket = SalesRollupBucketSold
+	default:
+		return nil
+	}
+	return rollup
+}
+
+// addRollup adds (sign 1) or subtracts (sign -1) the order rollup values
+func (s *SalesRollupService) addRollup(repo *repository.SalesRollupRepository, order *models.Order, rollup *models.SalesRollupOrder, sign float64) error {
+	total := &models.SalesDailyTotal{Day: rollup.Day, PaidAmount: sign * rollup.PaidAmount}
+
+	switch rollup.Bucket {
+	case SalesRollupBucketCancelled:
+		total.CancelledCount = int64(sign)
+		total.CancelledAmount = sign * rollup.Amount
+		return repo.AddTotal(total)
+	case SalesRollupBucketRefunded:
+		total.RefundedCount = int64(sign)
+		total.RefundedAmount = sign * rollup.Amount
+		return repo.AddTotal(total)
+	}
+
+	total.Revenue = sign * rollup.Amount
+	total.OrdersCount = int64(sign)
+
+	// The rows are upserted in the same order (products, categories then the day) by every order to avoid deadlocks
+	items := slices.Clone(order.OrderItems)
+	slices.SortFunc(items, func(a, b models.OrderItem) int { return int(a.ProductID) - int(b.ProductID) })
+
+	categories := make(map[string]*models.SalesDailyCategory)
+	for _, item := range items {
+		units := int64(sign) * int64(item.Quantity)
+		total.UnitsSold += units
+
+		category := item.Product.Category
+		if category == "" {
+			category = "uncategorized"
+		}
+
+		if err := repo.AddProduct(&models.SalesDailyProduct{
+			Day:       rollup.Day,
+			ProductID: item.ProductID,
+			Category:  category,
+			Units:     units,
+			Revenue:   sign * item.TotalPrice,
+		}); err != nil {
+			return err
+		}
+
+		if _, ok := categories[category]; !ok {
+			categories[category] = &models.SalesDailyCategory{Day: rollup.Day, Category: category}
+		}
+		categories[category].Units += units
+		categories[category].Revenue += sign * item.TotalPrice
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(categories)) {
+		if err := repo.AddCategory(categories[name]); err != nil {
+			return err
+		}
+	}
+	return repo.AddTotal(total)
+}
+
+// orderDay is the order creation date in the rollups time zone
+func (s *SalesRollupService) orderDay(order *models.Order) time.Time {
+	created := order.CreatedAt.In(s.location)
+	return time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+}
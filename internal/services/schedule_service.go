@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sort"
+	pkgErrors "taskgo/pkg/errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+type ScheduleService struct {
+	inspector *asynq.Inspector
+}
+
+// ScheduledRun is a recurring task registered by the workers scheduler leader
+type ScheduledRun struct {
+	ID       string     `json:"id"`
+	Cronspec string     `json:"cronspec"`
+	TaskType string     `json:"task_type"`
+	Options  []string   `json:"options"`
+	NextRun  time.Time  `json:"next_run"`
+	PrevRun  *time.Time `json:"prev_run,omitempty"`
+}
+
+// Create a new schedule service
+func NewScheduleService(inspector *asynq.Inspector) *ScheduleService {
+	return &ScheduleService{inspector: inspector}
+}
+
+// ListUpcomingRuns returns the recurring tasks registered by the running scheduler sorted by the next run
+func (s *ScheduleService) ListUpcomingRuns() ([]ScheduledRun, error) {
+	if s.inspector == nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Queue is not available", "Queue inspector is not loaded", nil)
+	}
+
+	entries, err := s.inspector.SchedulerEntries()
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the schedules", "Failed to get scheduler entries", err)
+	}
+
+	runs := make([]ScheduledRun, 0, len(entries))
+	for _, entry := range entries {
+		run := ScheduledRun{
+			ID:       entry.ID,
+			Cronspec: entry.Spec,
+			TaskType: entry.Task.Type(),
+			NextRun:  entry.Next,
+		}
+
+		for _, opt := range entry.Opts {
+			run.Options = append(run.Options, opt.String())
+		}
+
+		if !entry.Prev.IsZero() {
+			prev := entry.Prev
+			run.PrevRun = &prev
+		}
+
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].NextRun.Before(runs[j].NextRun)
+	})
+
+	return runs, nil
+}
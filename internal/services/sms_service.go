@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/sms"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  SMS delivery status
+|------------------------------------------
+|	The sms channel records every sent message with the provider message id,
+|	the provider callbacks move it: sent -> delivered | failed
+|	(delivered is final, the late or retried callbacks don't move it back)
+|	The notification sms delivery follows the message status
+|------------------------------------------
+*/
+
+type SmsService struct {
+	smsMessageRepository           *repository.SmsMessageRepository
+	notificationDeliveryRepository *repository.NotificationDeliveryRepository
+	client                         *sms.Client
+	callbackToken                  string // the callbacks are rejected if empty
+}
+
+func NewSmsService(smsMessageRepo *repository.SmsMessageRepository, notificationDeliveryRepo *repository.NotificationDeliveryRepository, client *sms.Client, callbackToken string) *SmsService {
+	return &SmsService{
+		smsMessageRepository:           smsMessageRepo,
+		notificationDeliveryRepository: notificationDeliveryRepo,
+		client:                         client,
+		callbackToken:                  callbackToken,
+	}
+}
+
+// HandleStatusCallback verifies the provider callback token and applies the delivery status reports, returns the applied count
+func (s *SmsService) HandleStatusCallback(ctx context.Context, token string, r *http.Request) (int, error) {
+	if s.callbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.callbackToken)) != 1 {
+		return 0, pkgErrors.NewUnAuthorizedError("Invalid callback token", "sms status callback with an invalid token", nil)
+	}
+
+	provider := s.client.Provider()
+	reports, err := provider.ParseStatus(r)
+	if err != nil {
+		return 0, pkgErrors.NewBadRequestError("Invalid status callback", "Failed to parse the sms status callback", err)
+	}
+
+	applied := 0
+	for _, report := range reports {
+		ok, err := s.applyStatus(provider.Name(), report)
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+func (s *SmsService) applyStatus(provider string, report sms.StatusReport) (bool, error) {
+	message, err := s.smsMessageRepository.FindByProviderMessageID(provider, report.MessageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Not sent by us (or already pruned), the provider shouldn't retry it
+		deps.Log().Log().Warn("SMS status report of an unknown message", zap.String("provider", provider), zap.String("message_id", report.MessageID))
+		return false, nil
+	}
+	if err != nil {
+		return false, pkgErrors.NewServerError("Internal Server Error: Failed to apply the status callback", "Failed to find the sms message", err)
+	}
+
+	now := time.Now()
+	var from []enums.NotificationStatus
+	data := map[string]any{}
+	deliveryData := map[string]any{"updated_at": now}
+	switch report.Status {
+	case sms.StatusDelivered:
+		from = []enums.NotificationStatus{enums.NotificationStatusSent, enums.NotificationStatusFailed}
+		data["status"] = enums.NotificationStatusDelivered
+		data["delivered_at"] = now
+		data["error"] = ""
+		deliveryData["status"] = enums.NotificationStatusDelivered
+		deliveryData["last_error"] = ""
+		deliveryData["failed_at"] = nil
+	case sms.StatusFailed:
+		from = []enums.NotificationStatus{enums.NotificationStatusSent}
+		data["status"] = enums.NotificationStatusFailed
+		data["error"] = report.Error
+		deliveryData["status"] = enums.NotificationStatusFailed
+		deliveryData["last_error"] = "sms not delivered: " + report.Error
+		deliveryData["failed_at"] = now
+	default: // sent is the recorded status
+		return false, nil
+	}
+
+	updated, err := s.smsMessageRepository.UpdateStatus(message.ID, from, data)
+	if err != nil {
+		return false, pkgErrors.NewServerError("Internal Server Error: Failed to apply the status callback", "Failed to update the sms message status", err)
+	}
+
+	if updated && message.NotificationID != "" {
+		err := s.notificationDeliveryRepository.UpdateStatus(message.NotificationID, string(enums.NotificationChannelSMS), from, deliveryData)
+		if err != nil {
+			return false, pkgErrors.NewServerError("Internal Server Error: Failed to apply the status callback", "Failed to update the sms notification delivery status", err)
+		}
+	}
+	return updated, nil
+}
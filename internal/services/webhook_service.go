@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/webhook"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Outbound webhooks
+|------------------------------------------
+|	1- Partners subscriptions (url, secret and event types) are managed by the admins
+|	2- Each event is delivered to every matching subscription by its own task (webhooks queue),
+|	   the failed deliveries are retried by the queue with an exponential backoff
+|	3- Every attempt is kept in the delivery log with the receiver response,
+|	   the subscription is disabled after maxFailures consecutive failed attempts
+|------------------------------------------
+*/
+
+type WebhookService struct {
+	webhookRepository *repository.WebhookRepository
+	sender            *webhook.Sender
+	maxFailures       int // 0 = never disable the subscription
+}
+
+// Create a new webhook service
+func NewWebhookService(webhookRepository *repository.WebhookRepository, sender *webhook.Sender, maxFailures int) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+		sender:            sender,
+		maxFailures:       maxFailures,
+	}
+}
+
+// CreateSubscription creates a new subscription (a secret is generated if not provided)
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *requests.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhook.NewSecret(); err != nil {
+			return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the webhook", "Failed to generate webhook secret", err)
+		}
+	}
+
+	eventsJson, err := json.Marshal(req.Events)
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the webhook", "Failed to marshal webhook events", err)
+	}
+
+	subscription := &models.WebhookSubscription{
+		Name:   req.Name,
+		URL:    req.URL,
+		Secret: secret,
+		Events: string(eventsJson),
+		Active: true,
+	}
+
+	if err := s.webhookRepository.Create(subscription); err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to create the webhook", "Failed to create webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription updates the sent fields of the subscription
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id uint, req *requests.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	if _, err := s.GetSubscriptionById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+	if req.Name != nil {
+		data["name"] = *req.Name
+	}
+	if req.URL != nil {
+		data["url"] = *req.URL
+	}
+	if req.Secret != nil {
+		data["secret"] = *req.Secret
+	}
+	if req.Events != nil {
+		eventsJson, err := json.Marshal(req.Events)
+		if err != nil {
+			return nil, pkgErrors.NewServerError("Internal Server Error: Failed to update the webhook", "Failed to marshal webhook events", err)
+		}
+		data["events"] = string(eventsJson)
+	}
+	if req.Active != nil {
+		data["active"] = *req.Active
+		if *req.Active {
+			data["failure_count"] = 0
+			data["disabled_at"] = nil
+			data["disabled_reason"] = ""
+		} else {
+			data["disabled_at"] = time.Now()
+			data["disabled_reason"] = "disabled by an admin"
+		}
+	}
+
+	if len(data) > 0 {
+		if err := s.webhookRepository.UpdateById(id, data); err != nil {
+			return nil, pkgErrors.NewServerError("Internal Server Error: Failed to update the webhook", "Failed to update webhook subscription", err)
+		}
+	}
+
+	return s.GetSubscriptionById(ctx, id)
+}
+
+// DeleteSubscription deletes the subscription, its pending deliveries are skipped
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uint) error {
+	if err := s.webhookRepository.DeleteById(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgErrors.NewNotFoundError("NotFoundError: webhook not found", "NotFoundError: webhook not found", err)
+		}
+		return pkgErrors.NewServerError("Internal Server Error: Failed to delete the webhook", "Failed to delete webhook subscription", err)
+	}
+	return nil
+}
+
+// Get all the subscriptions
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepository.FindAll()
+	if err != nil {
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the webhooks", "Failed to find webhook subscriptions", err)
+	}
+	return subscriptions, nil
+}
+
+// Get a subscription by id
+func (s *WebhookService) GetSubscriptionById(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepository.FindById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgErrors.NewNotFoundError("NotFoundError: webhook not found", "NotFoundError: webhook not found", err)
+		}
+		return nil, pkgErrors.NewServerError("Internal Server Error: Failed to get the webhook", "Failed to find webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+// Get the paginated deliveries of the subscription
+func (s *WebhookService) GetPaginatedDeliveries(ctx context.Context, id uint, f *filters.WebhookDeliveryFilters) ([]*models.WebhookDelivery, int64, error) {
+	if _, err := s.GetSubscriptionById(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := s.webhookRepository.PaginateDeliveries(id, f)
+	if err != nil {
+		return nil, 0, pkgErrors.NewServerError("Internal Server Error: Failed to get the webhook deliveries", "Failed to paginate webhook deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+// MatchingSubscriptions returns the active subscriptions receiving the event type
+func (s *WebhookService) MatchingSubscriptions(ctx context.Context, eventType string) ([]*models.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepository.FindActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active webhook subscriptions: %w", err)
+	}
+
+	matching := make([]*models.WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(eventType) {
+			matching = append(matching, subscription)
+		}
+	}
+	return matching, nil
+}
+
+// Deliver sends the message to the subscription and records the delivery,
+// returns an error if the receiver didn't accept it (the delivery task is retried)
+func (s *WebhookService) Deliver(ctx context.Context, subscriptionID uint, message webhook.Message) error {
+	subscription, err := s.webhookRepository.FindById(subscriptionID)
+	if err != nil {
+		// The subscription was deleted after the event
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find webhook subscription %d: %w", subscriptionID, err)
+	}
+
+	if !subscription.Active {
+		return nil
+	}
+
+	res, sendErr := s.sender.Send(ctx, subscription.URL, subscription.Secret, message)
+	s.recordDelivery(subscription, message, res, sendErr)
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if !res.Success() {
+		return fmt.Errorf("webhook %d responded with status %d", subscription.ID, res.StatusCode)
+	}
+	return nil
+}
+
+// recordDelivery writes the delivery log and tracks the consecutive failures of the subscription
+func (s *WebhookService) recordDelivery(subscription *models.WebhookSubscription, message webhook.Message, res *webhook.Response, sendErr error) {
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        message.ID,
+		EventType:      message.Event,
+		Attempt:        message.Attempt,
+		DeliveredAt:    now,
+	}
+	if res != nil {
+		delivery.StatusCode = res.StatusCode
+		delivery.ResponseBody = res.Body
+		delivery.DurationMs = res.Duration.Milliseconds()
+		delivery.Success = res.Success()
+	}
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+	}
+
+	logger := deps.Log().Channel("queue_log")
+	if err := s.webhookRepository.CreateDelivery(delivery); err != nil {
+		logger.Error("Failed to record webhook delivery", zap.Uint("subscription_id", subscription.ID), zap.Error(err))
+	}
+
+	if delivery.Success {
+		err := s.webhookRepository.UpdateById(subscription.ID, map[string]any{
+			"failure_count":    0,
+			"last_delivery_at": &now,
+		})
+		if err != nil {
+			logger.Error("Failed to reset webhook failures", zap.Uint("subscription_id", subscription.ID), zap.Error(err))
+		}
+		return
+	}
+
+	failureCount, err := s.webhookRepository.IncrementFailures(subscription.ID)
+	if err != nil {
+		logger.Error("Failed to count webhook failure", zap.Uint("subscription_id", subscription.ID), zap.Error(err))
+		return
+	}
+
+	if s.maxFailures > 0 && failureCount >= s.maxFailures {
+		err := s.webhookRepository.UpdateById(subscription.ID, map[string]any{
+			"active":          false,
+			"disabled_at":     &now,
+			"disabled_reason": fmt.Sprintf("disabled after %d consecutive failed deliveries", failureCount),
+		})
+		if err != nil {
+			logger.Error("Failed to disable webhook", zap.Uint("subscription_id", subscription.ID), zap.Error(err))
+			return
+		}
+		logger.Warn("Webhook disabled after repeated failures", zap.Uint("subscription_id", subscription.ID), zap.Int("failures", failureCount))
+	}
+}
@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	"taskgo/pkg/envelope"
+	pkgErrors "taskgo/pkg/errors"
+
+	"github.com/hibiken/asynq"
+)
+
+// ApplySalesRollupTask implement Task interface also it's used as payload for task
+// (written to the outbox with the order status change so the rollups are never left behind)
+type ApplySalesRollupTask struct {
+	OrderID uint `json:"order_id"`
+}
+
+func NewApplySalesRollupTask(orderID uint) *ApplySalesRollupTask {
+	return &ApplySalesRollupTask{OrderID: orderID}
+}
+
+func (t *ApplySalesRollupTask) GetTaskType() string {
+	return TypeApplySalesRollup
+}
+
+func (t *ApplySalesRollupTask) GetPayload() interface{} {
+	return *t // Return itself as payload
+}
+
+func (t *ApplySalesRollupTask) CreateTask() (*asynq.Task, error) {
+	message, err := t.Message()
+	if err != nil {
+		return nil, err
+	}
+	return message.Task(), nil
+}
+
+// Message builds the task message (stored in the outbox with the order status change),
+// it has no task id since the order is applied again on each of its status changes
+func (t *ApplySalesRollupTask) Message() (*chainq.TaskMessage, error) {
+	payload, err := envelope.Wrap(t.GetTaskType(), t.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chainq.TaskMessage{
+		Type:     TypeApplySalesRollup,
+		Payload:  payload,
+		Queue:    QueueLow,
+		MaxRetry: RetryPolicies().For(TypeApplySalesRollup, QueueLow).MaxRetry,
+	}, nil
+}
+
+/*
+|------------------------------------------
+|  Task handler: ApplySalesRollupHandler
+|------------------------------------------
+*/
+type ApplySalesRollupHandler struct {
+	salesRollupService *services.SalesRollupService
+}
+
+// Return a new apply sales rollup task Handler
+func NewApplySalesRollupHandler(salesRollupService *services.SalesRollupService) *ApplySalesRollupHandler {
+	return &ApplySalesRollupHandler{
+		salesRollupService: salesRollupService,
+	}
+}
+
+// Handler method for the apply sales rollup task implement Handler interface
+func (h *ApplySalesRollupHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	return processTaskPayload(ctx, t, h.handle)
+}
+
+/*
+|-------------------------------------------------
+|  Actual task handling code goes here:
+|-------------------------------------------------
+*/
+// handle counts the order in the rollups of its current status (applying it again does nothing)
+func (h *ApplySalesRollupHandler) handle(ctx context.Context, payload *ApplySalesRollupTask) error {
+	err := h.salesRollupService.ApplyOrder(ctx, payload.OrderID)
+
+	// The order was deleted, there is nothing to count
+	if errors.As(err, new(*pkgErrors.NotFoundError)) {
+		return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"fmt"
+	"taskgo/internal/deps"
+	chainq "taskgo/pkg/asynq_chain"
+	"time"
+)
+
+// ChainControl returns the chain control used to cancel, pause and resume chains (nil if cache redis is not loaded)
+func ChainControl() chainq.Control {
+	cache := deps.Cache()
+	if cache == nil || cache.Redis == nil {
+		return nil
+	}
+
+	return chainq.NewRedisControl(
+		cache.Redis,
+		deps.Config().GetDuration("queue.chain.state_ttl", 7*24*time.Hour),
+	)
+}
+
+// ChainStepTracker returns the tracker of the chains completed steps (nil if cache redis is not loaded)
+func ChainStepTracker() chainq.StepTracker {
+	cache := deps.Cache()
+	if cache == nil || cache.Redis == nil {
+		return nil
+	}
+
+	return chainq.NewRedisStepTracker(
+		cache.Redis,
+		deps.Config().GetDuration("queue.chain.state_ttl", 7*24*time.Hour),
+	)
+}
+
+// OrderProcessingChainID returns the processing chain id of the order so it can be controlled by order id
+func OrderProcessingChainID(orderID uint) string {
+	return fmt.Sprintf("CHAIN_ORDER_%d", orderID)
+}
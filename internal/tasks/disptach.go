@@ -8,6 +8,7 @@ import (
 	"taskgo/internal/adapters"
 	"taskgo/internal/deps"
 	chainq "taskgo/pkg/asynq_chain"
+	"taskgo/pkg/envelope"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -56,34 +57,60 @@ func Dispatch(task chainq.Task, opts ...asynq.Option) error {
 	Helpers to create new asynq Task or TaskHandler used inside the tasks
 */
 
-// Create new asynq task to from task to use it inside package
+// Create new asynq task to from task to use it inside package (payload is wrapped in a versioned envelope)
 func CreateAsynqTask(t chainq.Task, opts ...asynq.Option) (*asynq.Task, error) {
-	payload, err := json.Marshal(t.GetPayload())
+	payload, err := envelope.Wrap(t.GetTaskType(), t.GetPayload())
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, err
 	}
 
 	return asynq.NewTask(t.GetTaskType(), payload, opts...), nil
 }
 
 // Process task payload helper to send payload to actual handler
+// old payload versions are migrated to the current version, invalid or unknown payloads are not retried
 func processTaskPayload[T any](ctx context.Context, task *asynq.Task, handler func(context.Context, *T) error) error {
+	data, err := envelope.Unwrap(task.Type(), task.Payload())
+	if err != nil {
+		// A newer payload version is retried until a worker running the new code reads it
+		if envelope.IsRetryable(err) {
+			return fmt.Errorf("failed to read payload: %w", err)
+		}
+		return fmt.Errorf("failed to read payload: %w: %w", err, asynq.SkipRetry)
+	}
+
 	var payload T
-	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w: %w", err, asynq.SkipRetry)
 	}
 	return handler(ctx, &payload)
 }
 
+// isLastAttempt checks if the task won't be retried after the error (outside of a worker the first attempt is the last)
+func isLastAttempt(ctx context.Context, err error) bool {
+	if !RetryPolicies().IsRetryable(err) {
+		return true
+	}
+
+	retried, ok := asynq.GetRetryCount(ctx)
+	maxRetry, okMax := asynq.GetMaxRetry(ctx)
+	return !ok || !okMax || retried >= maxRetry
+}
+
 // Chain - helper to create new chainq.Chain which can be used to create new chain of tasks
 func Chain() *chainq.Chain {
 	return chainq.NewChain(
 		deps.Queue().Client,
 		adapters.NewLoggerAdapter(deps.Log().Log()),
 		&chainq.ChainOptions{
-			MaxRetries:   3,
+			MaxRetries:   ChainMaxRetry(QueueDefault),
 			Timeout:      5 * time.Second,
-			DefaultQueue: "default",
+			DefaultQueue: QueueDefault,
 		},
 	)
 }
+
+// ChainMaxRetry returns the max retry of each chain step on the queue (from the retry policies)
+func ChainMaxRetry(queue string) int {
+	return RetryPolicies().For(chainq.TypeChainOrchestrator, queue).MaxRetry
+}
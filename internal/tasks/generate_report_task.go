@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/notification"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	"taskgo/pkg/envelope"
+	pkgErrors "taskgo/pkg/errors"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// GenerateReportTask implement Task interface also it's used as payload for task
+type GenerateReportTask struct {
+	ReportJobID uint `json:"report_job_id"`
+}
+
+func NewGenerateReportTask(reportJobID uint) *GenerateReportTask {
+	return &GenerateReportTask{ReportJobID: reportJobID}
+}
+
+func (t *GenerateReportTask) GetTaskType() string {
+	return TypeGenerateReport
+}
+
+func (t *GenerateReportTask) GetPayload() interface{} {
+	return *t // Return itself as payload
+}
+
+func (t *GenerateReportTask) CreateTask() (*asynq.Task, error) {
+	message, err := t.Message()
+	if err != nil {
+		return nil, err
+	}
+	return message.Task(), nil
+}
+
+// Message builds the task message (stored in the outbox with the report job)
+func (t *GenerateReportTask) Message() (*chainq.TaskMessage, error) {
+	payload, err := envelope.Wrap(t.GetTaskType(), t.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chainq.TaskMessage{
+		Type:     TypeGenerateReport,
+		Payload:  payload,
+		Queue:    QueueLow,
+		TaskID:   fmt.Sprintf("report:%d", t.ReportJobID),
+		MaxRetry: RetryPolicies().For(TypeGenerateReport, QueueLow).MaxRetry,
+	}, nil
+}
+
+/*
+|------------------------------------------
+|  Task handler: GenerateReportHandler
+|------------------------------------------
+*/
+type GenerateReportHandler struct {
+	reportService *services.ReportService
+}
+
+// Return a new generate report task Handler
+func NewGenerateReportHandler(reportService *services.ReportService) *GenerateReportHandler {
+	return &GenerateReportHandler{
+		reportService: reportService,
+	}
+}
+
+// Handler method for the generate report task implement Handler interface
+func (h *GenerateReportHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	return processTaskPayload(ctx, t, h.handle)
+}
+
+/*
+|-------------------------------------------------
+|  Actual task handling code goes here:
+|-------------------------------------------------
+*/
+func (h *GenerateReportHandler) handle(ctx context.Context, payload *GenerateReportTask) error {
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	job, err := h.reportService.RunReportJob(ctx, payload.ReportJobID, retryCount >= maxRetry)
+	if err != nil {
+		// Tell the requester the report failed on the last attempt (invalid params are not retried)
+		if retryCount >= maxRetry || errors.As(err, new(*pkgErrors.ValidationError)) {
+			if failedJob, findErr := h.reportService.GetReportJobById(ctx, payload.ReportJobID); findErr == nil && failedJob.Status == enums.ReportJobStatusFailed {
+				h.notify(failedJob)
+			}
+		}
+		return err
+	}
+
+	h.notify(job)
+	return nil
+}
+
+func (h *GenerateReportHandler) notify(job *models.ReportJob) {
+	requester := &models.User{Base: models.Base{ID: job.UserID}}
+	if err := deps.Notify().Send(notification.NewReportReadyNotification(job), requester); err != nil {
+		deps.Log().Channel("queue_log").Error("Failed to send report notification", zap.Uint("report_job_id", job.ID), zap.Error(err))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"taskgo/internal/services"
 
 	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
 )
 
 // InventoryCheckTask implement Task interface also it's used as payload for task
@@ -28,7 +29,7 @@ func (t *InventoryCheckTask) GetPayload() interface{} {
 }
 
 func (t *InventoryCheckTask) CreateTask() (*asynq.Task, error) {
-	return CreateAsynqTask(t, asynq.Queue(QueueInventoryCheck), asynq.MaxRetry(3))
+	return CreateAsynqTask(t, asynq.Queue(QueueInventoryCheck), RetryPolicies().MaxRetry(TypeInventoryCheck, QueueInventoryCheck))
 }
 
 /*
@@ -72,6 +73,11 @@ func (p *InventoryCheckHandler) handle(ctx context.Context, task *InventoryCheck
 		return fmt.Errorf("failed to reserve inventory:  %w", err)
 	}
 
+	// Alert the products which reached their reorder point
+	if err := p.inventoryService.PublishLowStock(ctx, order.OrderItems); err != nil {
+		deps.Log().Channel("queue_log").Error(fmt.Sprintf("Failed to check low stock for Order: %d", task.OrderID), zap.Error(err))
+	}
+
 	deps.Log().Channel("queue_log").Info(fmt.Sprintf("Inventory check task processed for Order:  %d", task.OrderID))
 	return nil
 }
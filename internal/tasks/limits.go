@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"sync"
+	"taskgo/internal/deps"
+	limitq "taskgo/pkg/asynq_limit"
+	"time"
+)
+
+var (
+	taskLimiter     *limitq.Limiter
+	taskLimiterOnce sync.Once
+)
+
+// Limiter returns the tasks concurrency and rate limiter loaded from the queue.consumer.limits config
+// the redis backend is used if the cache redis is loaded (limits shared by all the workers)
+func Limiter() *limitq.Limiter {
+	taskLimiterOnce.Do(func() {
+		base := "queue.consumer.limits"
+		cfg := deps.Config()
+
+		var backend limitq.Backend = limitq.NewLocalBackend()
+		if cache := deps.Cache(); cfg.GetString(base+".backend", "redis") == "redis" && cache != nil && cache.Redis != nil {
+			backend = limitq.NewRedisBackend(cache.Redis)
+		}
+
+		taskLimiter = limitq.New(backend).
+			SetSlotTTL(cfg.GetDuration(base+".slot_ttl", 30*time.Minute))
+
+		for queue := range cfg.GetMap(base+".queues", nil) {
+			taskLimiter.SetQueue(queue, loadLimit(base+".queues."+queue))
+		}
+
+		for taskType := range cfg.GetMap(base+".task_types", nil) {
+			taskLimiter.SetTaskType(taskType, loadLimit(base+".task_types."+taskType))
+		}
+	})
+
+	return taskLimiter
+}
+
+// loadLimit loads the limit from the config key
+func loadLimit(key string) limitq.Limit {
+	cfg := deps.Config()
+
+	return limitq.Limit{
+		Concurrency: cfg.GetInt(key+".concurrency", 0),
+		Rate:        cfg.GetInt(key+".rate", 0),
+		Period:      cfg.GetDuration(key+".period", time.Second),
+		RetryIn:     cfg.GetDuration(key+".retry_in", time.Second),
+	}
+}
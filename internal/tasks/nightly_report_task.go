@@ -0,0 +1,99 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// NightlyReportTask implement Task interface also it's used as payload for task (enqueued by the scheduler)
+type NightlyReportTask struct {
+	Format string `json:"format"` // csv | xlsx
+}
+
+func NewNightlyReportTask(format string) *NightlyReportTask {
+	return &NightlyReportTask{Format: format}
+}
+
+func (t *NightlyReportTask) GetTaskType() string {
+	return TypeNightlyReport
+}
+
+func (t *NightlyReportTask) GetPayload() interface{} {
+	return *t // Return itself as payload
+}
+
+func (t *NightlyReportTask) CreateTask() (*asynq.Task, error) {
+	return CreateAsynqTask(t,
+		asynq.Queue(QueueLow),
+		RetryPolicies().MaxRetry(TypeNightlyReport, QueueLow),
+	)
+}
+
+/*
+|------------------------------------------
+|  Task handler: NightlyReportHandler
+|------------------------------------------
+*/
+type NightlyReportHandler struct {
+	reportService  *services.ReportService
+	userRepository *repository.UserRepository
+}
+
+// Return a new nightly report task Handler
+func NewNightlyReportHandler(reportService *services.ReportService, userRepo *repository.UserRepository) *NightlyReportHandler {
+	return &NightlyReportHandler{
+		reportService:  reportService,
+		userRepository: userRepo,
+	}
+}
+
+// Handler method for the nightly report task implement Handler interface
+func (h *NightlyReportHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	return processTaskPayload(ctx, t, h.handle)
+}
+
+/*
+|-------------------------------------------------
+|  Actual task handling code goes here:
+|-------------------------------------------------
+*/
+// handle queues the previous day sales report of every active admin, each report is generated
+// by its own GenerateReportTask and the admin is notified when it's ready.
+// The task is retried only if no report was queued so the admins don't get the report twice
+func (h *NightlyReportHandler) handle(ctx context.Context, payload *NightlyReportTask) error {
+	admins, err := h.userRepository.FindActiveAdmins()
+	if err != nil {
+		return fmt.Errorf("failed to find the report requesters: %w", err)
+	}
+
+	log := deps.Log().Channel("queue_log")
+
+	var errs []error
+	for _, admin := range admins {
+		job, err := h.reportService.CreateDailyReportJob(ctx, admin.ID, payload.Format, time.Now(), func(job *models.ReportJob) (*chainq.TaskMessage, error) {
+			return NewGenerateReportTask(job.ID).Message()
+		})
+		if err != nil {
+			log.Error("Failed to queue the nightly report", zap.Uint("user_id", admin.ID), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+
+		log.Info("Nightly report queued", zap.Uint("report_job_id", job.ID), zap.Uint("user_id", admin.ID))
+	}
+
+	if len(errs) > 0 && len(errs) == len(admins) {
+		return fmt.Errorf("failed to queue the nightly reports: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -3,9 +3,18 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"taskgo/internal/database/models"
 	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/events"
+	"taskgo/internal/notification"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	pkgErrors "taskgo/pkg/errors"
 
 	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
 )
 
 // ProcessPaymentTask implement Task interface also it's used as payload for task
@@ -26,7 +35,7 @@ func (t *ProcessPaymentTask) GetPayload() interface{} {
 }
 
 func (t *ProcessPaymentTask) CreateTask() (*asynq.Task, error) {
-	return CreateAsynqTask(t, asynq.Queue(QueuePayments), asynq.MaxRetry(3))
+	return CreateAsynqTask(t, asynq.Queue(QueuePayments), RetryPolicies().MaxRetry(TypeProcessPayment, QueuePayments))
 }
 
 /*
@@ -35,11 +44,18 @@ func (t *ProcessPaymentTask) CreateTask() (*asynq.Task, error) {
 |------------------------------------------
 */
 type ProcessPaymentHandler struct {
+	orderService    *services.OrderService
+	orderRepository *repository.OrderRepository
+	paymentGateway  services.PaymentGateway
 }
 
 // Return a new payment task Handler
-func NewProcessPaymentHandler() *ProcessPaymentHandler {
-	return &ProcessPaymentHandler{}
+func NewProcessPaymentHandler(orderService *services.OrderService, orderRepo *repository.OrderRepository, paymentGateway services.PaymentGateway) *ProcessPaymentHandler {
+	return &ProcessPaymentHandler{
+		orderService:    orderService,
+		orderRepository: orderRepo,
+		paymentGateway:  paymentGateway,
+	}
 }
 
 // Handler method for the payment task implement Handler interface
@@ -53,9 +69,44 @@ func (h *ProcessPaymentHandler) ProcessTask(ctx context.Context, t *asynq.Task)
 |-------------------------------------------------
 */
 func (p *ProcessPaymentHandler) handle(ctx context.Context, payload *ProcessPaymentTask) error {
-	// Here is the actual payment processing logic
-	// ...
-	deps.Log().Channel("queue_log").Info(fmt.Sprintf("Processed payment for Order: %d", payload.OrderID))
+	order, err := p.orderRepository.FindById(payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// The order may be cancelled while its chain is running
+	if order.Status != enums.OrderStatusPending && order.Status != enums.OrderStatusConfirmed {
+		return pkgErrors.NewValidationError(map[string]any{
+			"status": fmt.Sprintf("Payment can't be processed for a %s order", order.Status),
+		})
+	}
+
+	// The chain step idempotency key is sent to the payment provider so a re-run of the step can't double charge
+	idempotencyKey := chainq.StepIdempotencyKey(ctx)
+	if err := p.paymentGateway.Charge(ctx, order, idempotencyKey); err != nil {
+		return p.paymentFailed(ctx, order, fmt.Errorf("failed to charge order %d: %w", order.ID, err))
+	}
+	deps.Log().Channel("queue_log").Info(fmt.Sprintf("Processed payment for Order: %d", payload.OrderID), zap.String("idempotency_key", idempotencyKey))
+
+	event, err := events.PaymentCaptured(order, idempotencyKey)
+	events.Publish(ctx, event, err)
+
+	// Payment captured and inventory reserved (previous chain step)
+	if _, err := p.orderService.UpdateStatus(ctx, order.ID, enums.OrderStatusConfirmed, "payment captured"); err != nil {
+		return fmt.Errorf("failed to confirm order: %w", err)
+	}
 
 	return nil
 }
+
+// paymentFailed cancels the order when its payment fails for the last time (the customer is notified of the payment failure)
+func (p *ProcessPaymentHandler) paymentFailed(ctx context.Context, order *models.Order, err error) error {
+	if !isLastAttempt(ctx, err) {
+		return err
+	}
+
+	if _, cancelErr := p.orderService.UpdateStatus(ctx, order.ID, enums.OrderStatusCancelled, notification.PaymentFailedReason); cancelErr != nil {
+		deps.Log().Channel("queue_log").Error("Failed to cancel the unpaid order", zap.Uint("order_id", order.ID), zap.Error(cancelErr))
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package tasks
+
+import (
+	"errors"
+	"sync"
+	"taskgo/internal/deps"
+	retryq "taskgo/pkg/asynq_retry"
+	pkgErrors "taskgo/pkg/errors"
+	"time"
+)
+
+var (
+	retryPolicies     *retryq.Policies
+	retryPoliciesOnce sync.Once
+)
+
+// RetryPolicies returns the tasks retry policies loaded from the queue.consumer.retry config
+func RetryPolicies() *retryq.Policies {
+	retryPoliciesOnce.Do(func() {
+		base := "queue.consumer.retry"
+		defaultPolicy := loadRetryPolicy(base, retryq.Policy{
+			Strategy: retryq.StrategyExponential,
+			MaxRetry: 3,
+			Delay:    15 * time.Second,
+			MaxDelay: 10 * time.Minute,
+			Factor:   2,
+		})
+
+		retryPolicies = retryq.NewPolicies(defaultPolicy)
+
+		for queue := range deps.Config().GetMap(base+".queues", nil) {
+			retryPolicies.SetQueue(queue, loadRetryPolicy(base+".queues."+queue, defaultPolicy))
+		}
+
+		for taskType := range deps.Config().GetMap(base+".task_types", nil) {
+			retryPolicies.SetTaskType(taskType, loadRetryPolicy(base+".task_types."+taskType, defaultPolicy))
+		}
+
+		// Errors that will fail again on every retry
+		// (the insufficient stock is a validation error of the order creation, the stock isn't reserved by the tasks yet)
+		retryPolicies.
+			SkipRetryIf(func(err error) bool {
+				var validationErr *pkgErrors.ValidationError
+				return errors.As(err, &validationErr)
+			})
+	})
+
+	return retryPolicies
+}
+
+// loadRetryPolicy loads the retry policy from the config key (missing values are taken from the fallback policy)
+func loadRetryPolicy(key string, fallback retryq.Policy) retryq.Policy {
+	cfg := deps.Config()
+
+	policy := retryq.Policy{
+		Strategy: retryq.Strategy(cfg.GetString(key+".strategy", string(fallback.Strategy))),
+		MaxRetry: cfg.GetInt(key+".max_attempts", fallback.MaxRetry),
+		Delay:    cfg.GetDuration(key+".delay", fallback.Delay),
+		MaxDelay: cfg.GetDuration(key+".max_delay", fallback.MaxDelay),
+		Factor:   fallback.Factor,
+	}
+
+	if factor, err := cfg.Get(key + ".factor"); err == nil {
+		switch v := factor.(type) {
+		case int:
+			policy.Factor = float64(v)
+		case float64:
+			policy.Factor = v
+		}
+	}
+
+	return policy
+}
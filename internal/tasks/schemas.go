@@ -0,0 +1,24 @@
+package tasks
+
+import "taskgo/pkg/envelope"
+
+/*
+|------------------------------------------
+|  Tasks payload schemas
+|------------------------------------------
+|	Bump the version when the task payload struct changes (add, rename or remove a field)
+|	and register an upcaster from the previous version so the in-flight tasks keep working.
+|	Unknown (newer) versions are retried (read by the workers running the new code during deploys),
+|	malformed payloads are rejected without retrying.
+|------------------------------------------
+*/
+
+func init() {
+	envelope.Register(TypeProcessPayment, envelope.Schema{Version: 1})
+	envelope.Register(TypeInventoryCheck, envelope.Schema{Version: 1})
+	envelope.Register(TypeSendNotification, envelope.Schema{Version: 1})
+	envelope.Register(TypeWebhookDelivery, envelope.Schema{Version: 1})
+	envelope.Register(TypeGenerateReport, envelope.Schema{Version: 1})
+	envelope.Register(TypeNightlyReport, envelope.Schema{Version: 1})
+	envelope.Register(TypeApplySalesRollup, envelope.Schema{Version: 1})
+}
@@ -26,7 +26,7 @@ func (t *SendNotificationTask) GetPayload() interface{} {
 }
 
 func (t *SendNotificationTask) CreateTask() (*asynq.Task, error) {
-	return CreateAsynqTask(t, asynq.Queue(QueueNotifications), asynq.MaxRetry(3))
+	return CreateAsynqTask(t, asynq.Queue(QueueNotifications), RetryPolicies().MaxRetry(TypeSendNotification, QueueNotifications))
 }
 
 /*
@@ -0,0 +1,23 @@
+package tasks
+
+import (
+	"encoding/json"
+	chainq "taskgo/pkg/asynq_chain"
+
+	"github.com/hibiken/asynq"
+)
+
+// TraceIDFromTask returns the trace id carried by the task (used by the tracing middleware)
+func TraceIDFromTask(task *asynq.Task) string {
+	if task.Type() != chainq.TypeChainOrchestrator {
+		return ""
+	}
+
+	var payload struct {
+		TraceID string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return ""
+	}
+	return payload.TraceID
+}
@@ -5,6 +5,10 @@ const (
 	TypeProcessPayment   = "process:payment"
 	TypeInventoryCheck   = "inventory:check"
 	TypeSendNotification = "send:notification"
+	TypeWebhookDelivery  = "webhook:deliver"
+	TypeGenerateReport   = "report:generate"
+	TypeNightlyReport    = "report:nightly"
+	TypeApplySalesRollup = "rollups:apply"
 )
 
 // Queue names
@@ -15,6 +19,7 @@ const (
 	QueuePayments       = "payments"
 	QueueInventoryCheck = "inventory_check"
 	QueueNotifications  = "notifications"
+	QueueWebhooks       = "webhooks"
 
 	QueueOrderProcessingChain = "order_processing_chain"
 )
@@ -0,0 +1,138 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"taskgo/internal/deps"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	"taskgo/pkg/envelope"
+	pkgEvents "taskgo/pkg/events"
+	"taskgo/pkg/webhook"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryTask implement Task interface also it's used as payload for task
+type WebhookDeliveryTask struct {
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Body           json.RawMessage `json:"body"` // the delivered event (signed as is)
+}
+
+func NewWebhookDeliveryTask(subscriptionID uint, event pkgEvents.Event) (*WebhookDeliveryTask, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	return &WebhookDeliveryTask{
+		SubscriptionID: subscriptionID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Body:           body,
+	}, nil
+}
+
+func (t *WebhookDeliveryTask) GetTaskType() string {
+	return TypeWebhookDelivery
+}
+
+func (t *WebhookDeliveryTask) GetPayload() interface{} {
+	return *t // Return itself as payload
+}
+
+func (t *WebhookDeliveryTask) CreateTask() (*asynq.Task, error) {
+	message, err := t.Message()
+	if err != nil {
+		return nil, err
+	}
+	return message.Task(), nil
+}
+
+// Message builds the task message (stored in the outbox with the change delivering the event),
+// the task id deduplicates the event delivery to the subscription
+func (t *WebhookDeliveryTask) Message() (*chainq.TaskMessage, error) {
+	payload, err := envelope.Wrap(t.GetTaskType(), t.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chainq.TaskMessage{
+		Type:     TypeWebhookDelivery,
+		Payload:  payload,
+		Queue:    QueueWebhooks,
+		TaskID:   fmt.Sprintf("webhook:%d:%s", t.SubscriptionID, t.EventID),
+		MaxRetry: RetryPolicies().For(TypeWebhookDelivery, QueueWebhooks).MaxRetry,
+	}, nil
+}
+
+// AddWebhookEvent writes the event delivery to every active subscription receiving it to the outbox
+// in the given transaction, so the deliveries are enqueued by the outbox relay only if the change is committed
+func AddWebhookEvent(ctx context.Context, tx *gorm.DB, event pkgEvents.Event) error {
+	webhookService := deps.App[*services.WebhookService]()
+	if webhookService == nil {
+		return fmt.Errorf("webhook service not initialized")
+	}
+
+	subscriptions, err := webhookService.MatchingSubscriptions(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+
+	outboxService := deps.App[*services.OutboxService]()
+	for _, subscription := range subscriptions {
+		task, err := NewWebhookDeliveryTask(subscription.ID, event)
+		if err != nil {
+			return err
+		}
+		message, err := task.Message()
+		if err != nil {
+			return err
+		}
+		if _, err := outboxService.Add(tx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+|------------------------------------------
+|  Task handler: WebhookDeliveryHandler
+|------------------------------------------
+*/
+type WebhookDeliveryHandler struct {
+	webhookService *services.WebhookService
+}
+
+// Return a new webhook delivery task Handler
+func NewWebhookDeliveryHandler(webhookService *services.WebhookService) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Handler method for the webhook delivery task implement Handler interface
+func (h *WebhookDeliveryHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	return processTaskPayload(ctx, t, h.handle)
+}
+
+/*
+|-------------------------------------------------
+|  Actual task handling code goes here:
+|-------------------------------------------------
+*/
+func (h *WebhookDeliveryHandler) handle(ctx context.Context, payload *WebhookDeliveryTask) error {
+	retryCount, _ := asynq.GetRetryCount(ctx)
+
+	return h.webhookService.Deliver(ctx, payload.SubscriptionID, webhook.Message{
+		ID:      payload.EventID,
+		Event:   payload.EventType,
+		Attempt: retryCount + 1,
+		Body:    payload.Body,
+	})
+}
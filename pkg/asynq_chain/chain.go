@@ -3,6 +3,7 @@ package chainq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"taskgo/internal/deps"
@@ -25,6 +26,8 @@ import (
 |	5- and it will check the ChainOrchestratorPayload to see the registered tasks to handle
 |	6- then it will handle first task and then dispatch new task as TypeChainOrchestrator but in next step (next task)
 |	7- and so on until the last task will be handled and the chain will be completed
+|	8- before each step the orchestrator checks the chain Control (cancelled / paused) see control.go
+|	9- completed steps are tracked and steps are enqueued with deterministic task ids see idempotency.go
 |------------------------------------------
 |	Example:
 |------------------------------------------
@@ -62,6 +65,8 @@ type Logger interface {
 }
 
 type Chain struct {
+	id         string
+	traceID    string
 	client     *asynq.Client
 	tasks      []Task
 	onSuccess  func(any) error
@@ -101,6 +106,7 @@ func NewChain(client *asynq.Client, logger Logger, opt *ChainOptions) *Chain {
 	}
 
 	return &Chain{
+		id:         generateChainID(),
 		tasks:      make([]Task, 0),
 		client:     client,
 		logger:     logger,
@@ -110,6 +116,25 @@ func NewChain(client *asynq.Client, logger Logger, opt *ChainOptions) *Chain {
 	}
 }
 
+// WithID sets a known chain id so the chain can be controlled later (cancel, pause, resume)
+func (c *Chain) WithID(id string) *Chain {
+	if id != "" {
+		c.id = id
+	}
+	return c
+}
+
+// WithTraceID sets the trace id propagated to all the chain steps (e.g. the http request id)
+func (c *Chain) WithTraceID(traceID string) *Chain {
+	c.traceID = traceID
+	return c
+}
+
+// ID returns the chain id
+func (c *Chain) ID() string {
+	return c.id
+}
+
 // Then adds a task to the chain
 func (c *Chain) Then(task Task) *Chain {
 	c.tasks = append(c.tasks, task)
@@ -148,14 +173,43 @@ func (c *Chain) OnFailure(callback func(error) error) *Chain {
 
 // Dispatch dispatches the chain to the queue
 func (c *Chain) Dispatch() error {
+	message, err := c.Build()
+	if err != nil {
+		return err
+	}
+
+	err = dispatchAsynqTask(c.client, c.logger, message.Task()) // Dispatch the orchestrator task
+	log := deps.Log().Channel("queue_log")
+	if err != nil {
+		log.Error("Failed to dispatch chain", zap.Error(err))
+		return err
+	}
+
+	log.Info("Chain dispatched successfully",
+		zap.String("chain_id", c.id),
+		zap.Int("task_count", len(c.tasks)),
+	)
+
+	return nil
+}
+
+// Build builds the chain orchestrator task without dispatching it (e.g. to store it in an outbox and enqueue it later)
+func (c *Chain) Build() (*TaskMessage, error) {
 	if len(c.tasks) == 0 {
-		return fmt.Errorf("no tasks in chain")
+		return nil, fmt.Errorf("no tasks in chain")
+	}
+
+	serializedTasks, err := c.serializeTasks()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the chain orchestrator payload
 	chainPayload := ChainPayload{
-		ChainID:     generateChainID(),
-		Tasks:       c.serializeTasks(),
+		ChainID:     c.id,
+		RunID:       uuid.New().String(),
+		TraceID:     c.traceID,
+		Tasks:       serializedTasks,
 		CurrentStep: 0,
 		MaxRetries:  c.maxRetries,
 		Timeout:     c.timeout,
@@ -164,54 +218,87 @@ func (c *Chain) Dispatch() error {
 
 	payload, err := json.Marshal(chainPayload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal chain payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal chain payload: %w", err)
 	}
 
-	task := asynq.NewTask(TypeChainOrchestrator, payload,
-		asynq.MaxRetry(c.maxRetries),
-		asynq.Timeout(c.timeout),
-		asynq.Queue(c.queue),
-	)
-	err = dispatchAsynqTask(c.client, c.logger, task) // Dispatch the orchestrator task
-	log := deps.Log().Channel("queue_log")
-	if err != nil {
-		log.Error("Failed to dispatch chain", zap.Error(err))
-		return err
-	}
+	return &TaskMessage{
+		Type:     TypeChainOrchestrator,
+		Payload:  payload,
+		Queue:    c.queue,
+		TaskID:   StepTaskID(chainPayload.RunKey(), chainPayload.CurrentStep),
+		MaxRetry: c.maxRetries,
+		Timeout:  c.timeout,
+	}, nil
+}
 
-	log.Info("Chain dispatched successfully",
-		zap.String("chain_id", chainPayload.ChainID),
-		zap.Int("task_count", len(c.tasks)),
-	)
+// TaskMessage is a built task with its enqueue options, it can be stored and enqueued later
+type TaskMessage struct {
+	Type     string        `json:"type"`
+	Payload  []byte        `json:"payload"`
+	Queue    string        `json:"queue"`
+	TaskID   string        `json:"task_id,omitempty"`
+	MaxRetry int           `json:"max_retry"`
+	Timeout  time.Duration `json:"timeout"`
+}
 
-	return nil
+// Task returns the asynq task of the message with its enqueue options
+func (m *TaskMessage) Task() *asynq.Task {
+	opts := []asynq.Option{asynq.MaxRetry(m.MaxRetry)}
+	if m.Queue != "" {
+		opts = append(opts, asynq.Queue(m.Queue))
+	}
+	if m.Timeout > 0 {
+		opts = append(opts, asynq.Timeout(m.Timeout))
+	}
+	if m.TaskID != "" {
+		opts = append(opts, asynq.TaskID(m.TaskID))
+	}
+
+	return asynq.NewTask(m.Type, m.Payload, opts...)
 }
 
 type ChainPayload struct {
 	ChainID     string                 `json:"chain_id"`
+	RunID       string                 `json:"run_id,omitempty"` // Unique per build, scopes the steps markers to one dispatch of the chain
+	TraceID     string                 `json:"trace_id,omitempty"`
 	Tasks       []SerializedTask       `json:"tasks"`
 	CurrentStep int                    `json:"current_step"`
+	PausePolls  int                    `json:"pause_polls,omitempty"` // How many times the current step was re-scheduled while paused
 	MaxRetries  int                    `json:"max_retries"`
 	Timeout     time.Duration          `json:"timeout"`
 	Queue       string                 `json:"queue"`
 	Context     map[string]interface{} `json:"context,omitempty"` // Shared data between tasks
 }
 
+// RunKey returns the key that scopes the steps markers and task ids to the current run of the chain
+// the chain id is kept as is for payloads built before the run id was added
+func (p ChainPayload) RunKey() string {
+	if p.RunID == "" {
+		return p.ChainID
+	}
+	return p.ChainID + ":" + p.RunID
+}
+
 type SerializedTask struct {
 	Type    string      `json:"type"`
-	Payload interface{} `json:"payload"`
+	Payload interface{} `json:"payload"` // The task payload as created by the task (keeps its versioned envelope)
 }
 
 // serializeTasks converts Task to SerializedTask so it can be stored inside the ChainPayload
-func (c *Chain) serializeTasks() []SerializedTask {
+func (c *Chain) serializeTasks() ([]SerializedTask, error) {
 	serialized := make([]SerializedTask, len(c.tasks))
 	for i, task := range c.tasks {
+		asynqTask, err := task.CreateTask()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create chain task %s: %w", task.GetTaskType(), err)
+		}
+
 		serialized[i] = SerializedTask{
 			Type:    task.GetTaskType(),
-			Payload: task.GetPayload(),
+			Payload: json.RawMessage(asynqTask.Payload()),
 		}
 	}
-	return serialized
+	return serialized, nil
 }
 
 // generateChainID creates a unique ID for the chain
@@ -222,19 +309,36 @@ func generateChainID() string {
 
 // ChainOrchestrator handles the execution of chained tasks
 type ChainOrchestrator struct {
-	handlers map[string]asynq.Handler // Map of task type to handler
-	client   *asynq.Client
-	logger   Logger
+	handlers          map[string]asynq.Handler // Map of task type to handler
+	client            *asynq.Client
+	logger            Logger
+	control           Control       // Optional chain control (cancel, pause, resume)
+	tracker           StepTracker   // Optional completed steps tracker (skip already completed steps)
+	pausePollInterval time.Duration // Delay before checking a paused chain again
 }
 
 func NewChainOrchestrator(client *asynq.Client, logger Logger) *ChainOrchestrator {
 	return &ChainOrchestrator{
-		handlers: make(map[string]asynq.Handler),
-		client:   client,
-		logger:   logger,
+		handlers:          make(map[string]asynq.Handler),
+		client:            client,
+		logger:            logger,
+		pausePollInterval: 30 * time.Second,
+	}
+}
+
+// SetControl sets the control checked before running each step of the chain
+func (co *ChainOrchestrator) SetControl(control Control, pausePollInterval time.Duration) {
+	co.control = control
+	if pausePollInterval > 0 {
+		co.pausePollInterval = pausePollInterval
 	}
 }
 
+// SetStepTracker sets the tracker used to skip the already completed steps
+func (co *ChainOrchestrator) SetStepTracker(tracker StepTracker) {
+	co.tracker = tracker
+}
+
 // RegisterHandler registers a task handler
 func (co *ChainOrchestrator) RegisterHandler(taskType string, handler asynq.Handler) {
 	co.handlers[taskType] = handler
@@ -262,9 +366,30 @@ func (co *ChainOrchestrator) ProcessTask(ctx context.Context, t *asynq.Task) err
 		return nil
 	}
 
+	// Check if the chain is cancelled or paused before running the step
+	canRun, err := co.checkControl(ctx, payload)
+	if err != nil || !canRun {
+		return err
+	}
+
 	// Get current task
 	currentTask := payload.Tasks[payload.CurrentStep]
 
+	// Skip the handler if the step is already completed (e.g. worker crashed before dispatching the next step)
+	done, err := co.isStepDone(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	if done {
+		log.Warn("Chain step already completed, skipping handler",
+			zap.String("chain_id", payload.ChainID),
+			zap.String("task_type", currentTask.Type),
+			zap.Int("step", payload.CurrentStep+1),
+		)
+		return co.completeStep(payload)
+	}
+
 	// Find handler for current task
 	handler, exists := co.handlers[currentTask.Type]
 	if !exists {
@@ -280,7 +405,19 @@ func (co *ChainOrchestrator) ProcessTask(ctx context.Context, t *asynq.Task) err
 	task := asynq.NewTask(currentTask.Type, taskPayload)
 
 	// Execute the current task
-	if err := handler.ProcessTask(ctx, task); err != nil {
+	stepCtx := withStepIdempotencyKey(ctx, payload.RunKey(), payload.CurrentStep)
+	if err := handler.ProcessTask(stepCtx, task); err != nil {
+		// The step is over a concurrency / rate limit, it will run again
+		var throttled interface{ Throttled() bool }
+		if errors.As(err, &throttled) && throttled.Throttled() {
+			log.Info("Chain step throttled, step re-scheduled",
+				zap.String("chain_id", payload.ChainID),
+				zap.String("task_type", currentTask.Type),
+				zap.Int("step", payload.CurrentStep+1),
+			)
+			return err
+		}
+
 		log.Error("Chain task failed",
 			zap.String("chain_id", payload.ChainID),
 			zap.String("task_type", currentTask.Type),
@@ -291,8 +428,20 @@ func (co *ChainOrchestrator) ProcessTask(ctx context.Context, t *asynq.Task) err
 			payload.CurrentStep+1, currentTask.Type, err)
 	}
 
-	// Task succeeded, move to next step
+	// Task succeeded, mark the step as completed so it never runs again
+	if co.tracker != nil {
+		if err := co.tracker.MarkStepDone(ctx, payload.RunKey(), payload.CurrentStep); err != nil {
+			return err
+		}
+	}
+
+	return co.completeStep(payload)
+}
+
+// completeStep moves the chain to the next step and dispatch it if there are more tasks
+func (co *ChainOrchestrator) completeStep(payload ChainPayload) error {
 	payload.CurrentStep++
+	payload.PausePolls = 0
 
 	// If there are more tasks, dispatch the next step
 	if payload.CurrentStep < len(payload.Tasks) {
@@ -300,14 +449,78 @@ func (co *ChainOrchestrator) ProcessTask(ctx context.Context, t *asynq.Task) err
 	}
 
 	// Chain completed
-	log.Info("Chain completed successfully",
+	deps.Log().Channel("queue_log").Info("Chain completed successfully",
 		zap.String("chain_id", payload.ChainID),
 	)
 	return nil
 }
 
+// isStepDone checks if the current step of the chain is already completed
+func (co *ChainOrchestrator) isStepDone(ctx context.Context, payload ChainPayload) (bool, error) {
+	if co.tracker == nil {
+		return false, nil
+	}
+
+	done, err := co.tracker.IsStepDone(ctx, payload.RunKey(), payload.CurrentStep)
+	if err != nil {
+		return false, fmt.Errorf("failed to check chain step: %w", err)
+	}
+	return done, nil
+}
+
+// checkControl returns false if the current step shouldn't run (chain cancelled or paused)
+func (co *ChainOrchestrator) checkControl(ctx context.Context, payload ChainPayload) (bool, error) {
+	if co.control == nil {
+		return true, nil
+	}
+
+	log := deps.Log().Channel("queue_log")
+
+	state, err := co.control.State(ctx, payload.ChainID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check chain control: %w", err)
+	}
+
+	if state == ChainStateCancelled {
+		log.Warn("Chain cancelled, remaining steps are skipped",
+			zap.String("chain_id", payload.ChainID),
+			zap.Int("step", payload.CurrentStep+1),
+			zap.Int("total", len(payload.Tasks)),
+		)
+		return false, nil
+	}
+
+	allPaused, err := co.control.IsAllPaused(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check chain control: %w", err)
+	}
+
+	if state == ChainStatePaused || allPaused {
+		log.Info("Chain paused, step re-scheduled",
+			zap.String("chain_id", payload.ChainID),
+			zap.Int("step", payload.CurrentStep+1),
+			zap.Duration("retry_in", co.pausePollInterval),
+		)
+		// Each poll gets its own deterministic task id so a redelivered step can't re-schedule it twice
+		payload.PausePolls++
+		return false, co.dispatchStep(payload, co.pausePollInterval,
+			asynq.TaskID(PausedStepTaskID(payload.RunKey(), payload.CurrentStep, payload.PausePolls)),
+		)
+	}
+
+	return true, nil
+}
+
 // dispatchNextStep dispatches the next step in the chain
+// it's enqueued with deterministic task id so it can't be enqueued twice
 func (co *ChainOrchestrator) dispatchNextStep(payload ChainPayload) error {
+	return co.dispatchStep(payload, 1*time.Second, // delay between steps
+		asynq.TaskID(StepTaskID(payload.RunKey(), payload.CurrentStep)),
+	)
+}
+
+// dispatchStep dispatches the chain current step after the given delay
+func (co *ChainOrchestrator) dispatchStep(payload ChainPayload, delay time.Duration, opts ...asynq.Option) error {
 	nextPayload, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal next step payload: %w", err)
@@ -317,10 +530,10 @@ func (co *ChainOrchestrator) dispatchNextStep(payload ChainPayload) error {
 		asynq.MaxRetry(payload.MaxRetries),
 		asynq.Timeout(payload.Timeout),
 		asynq.Queue(payload.Queue),
-		asynq.ProcessIn(1*time.Second), // delay between steps
+		asynq.ProcessIn(delay),
 	)
 
-	return dispatchAsynqTask(co.client, co.logger, task)
+	return dispatchAsynqTask(co.client, co.logger, task, opts...)
 }
 
 func dispatchAsynqTask(client *asynq.Client, log Logger, task *asynq.Task, opts ...asynq.Option) error {
@@ -330,6 +543,12 @@ func dispatchAsynqTask(client *asynq.Client, log Logger, task *asynq.Task, opts
 	}
 
 	info, err := client.Enqueue(task, opts...)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		// The task is already enqueued (duplicate dispatch) so there is nothing to do
+		log.Warn(fmt.Sprintf("task already enqueued (duplicate task id): Type=%s", task.Type()))
+		return nil
+	}
+
 	if err != nil {
 		log.Error(fmt.Sprintf("failed to enqueue task: %v", err))
 		return fmt.Errorf("failed to enqueue task: %w", err)
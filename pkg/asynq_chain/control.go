@@ -0,0 +1,151 @@
+package chainq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Chain Control
+|------------------------------------------
+|	The orchestrator checks the control before running each step:
+|	- cancelled chain: the remaining steps are dropped (never executed)
+|	- paused chain (or all chains paused): the current step is re-scheduled
+|	  after the pause poll interval until the chain is resumed
+|------------------------------------------
+*/
+
+// ChainState represents the current control state of a chain
+type ChainState string
+
+const (
+	ChainStateRunning   ChainState = "running"
+	ChainStatePaused    ChainState = "paused"
+	ChainStateCancelled ChainState = "cancelled"
+)
+
+// Control is used by the orchestrator to know if a chain can run the next step
+type Control interface {
+	Cancel(ctx context.Context, chainID string) error
+	Pause(ctx context.Context, chainID string) error
+	Resume(ctx context.Context, chainID string) error
+	PauseAll(ctx context.Context) error
+	ResumeAll(ctx context.Context) error
+	State(ctx context.Context, chainID string) (ChainState, error)
+	IsAllPaused(ctx context.Context) (bool, error)
+}
+
+// The state is changed atomically so a pause / resume racing a cancel never downgrades the cancelled state
+// returns 0 if the chain is cancelled, ARGV[1] = "" resumes the chain (deletes its state)
+var transitionScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == "cancelled" then
+	return 0
+end
+if ARGV[1] == "" then
+	redis.call("DEL", KEYS[1])
+else
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+end
+return 1
+`)
+
+// RedisControl stores the chains control flags in redis so it's shared between all the processes (api, workers)
+type RedisControl struct {
+	redis  *redis.Client
+	prefix string
+	ttl    time.Duration // how long the chain flags are kept
+}
+
+// NewRedisControl creates a new redis chain control
+func NewRedisControl(client *redis.Client, ttl time.Duration) *RedisControl {
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+
+	return &RedisControl{
+		redis:  client,
+		prefix: "chainq",
+		ttl:    ttl,
+	}
+}
+
+// Cancel marks the chain as cancelled, cancel is final and can't be resumed
+func (c *RedisControl) Cancel(ctx context.Context, chainID string) error {
+	return c.setState(ctx, chainID, ChainStateCancelled)
+}
+
+// Pause pauses the chain before running its next step
+func (c *RedisControl) Pause(ctx context.Context, chainID string) error {
+	return c.transition(ctx, chainID, ChainStatePaused, "paused")
+}
+
+// Resume resumes a paused chain
+func (c *RedisControl) Resume(ctx context.Context, chainID string) error {
+	return c.transition(ctx, chainID, "", "resumed")
+}
+
+// PauseAll pauses all the chains (e.g. payment provider outage)
+func (c *RedisControl) PauseAll(ctx context.Context) error {
+	return c.redis.Set(ctx, c.pauseAllKey(), "1", 0).Err()
+}
+
+// ResumeAll resumes all the chains paused by PauseAll
+func (c *RedisControl) ResumeAll(ctx context.Context) error {
+	return c.redis.Del(ctx, c.pauseAllKey()).Err()
+}
+
+// State returns the chain control state (running if there is no flag for the chain)
+func (c *RedisControl) State(ctx context.Context, chainID string) (ChainState, error) {
+	state, err := c.redis.Get(ctx, c.stateKey(chainID)).Result()
+	if err == redis.Nil {
+		return ChainStateRunning, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain state: %w", err)
+	}
+	return ChainState(state), nil
+}
+
+// IsAllPaused checks if all the chains are paused
+func (c *RedisControl) IsAllPaused(ctx context.Context) (bool, error) {
+	n, err := c.redis.Exists(ctx, c.pauseAllKey()).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check chains pause flag: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (c *RedisControl) setState(ctx context.Context, chainID string, state ChainState) error {
+	if chainID == "" {
+		return fmt.Errorf("chain id is required")
+	}
+	return c.redis.Set(ctx, c.stateKey(chainID), string(state), c.ttl).Err()
+}
+
+// transition moves the chain to the state ("" = running) unless it's cancelled
+func (c *RedisControl) transition(ctx context.Context, chainID string, state ChainState, action string) error {
+	if chainID == "" {
+		return fmt.Errorf("chain id is required")
+	}
+
+	changed, err := transitionScript.Run(ctx, c.redis, []string{c.stateKey(chainID)}, string(state), c.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to change chain state: %w", err)
+	}
+	if changed == 0 {
+		return fmt.Errorf("chain %s is cancelled and can't be %s", chainID, action)
+	}
+	return nil
+}
+
+func (c *RedisControl) stateKey(chainID string) string {
+	return fmt.Sprintf("%s:chain:%s:state", c.prefix, chainID)
+}
+
+func (c *RedisControl) pauseAllKey() string {
+	return c.prefix + ":paused:all"
+}
@@ -0,0 +1,100 @@
+package chainq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Chain Steps Idempotency
+|------------------------------------------
+|	asynq is at-least-once so a step can run more than one time if:
+|	- the worker crashed after the step handler returned and before the next step is dispatched
+|	- the same step was enqueued twice
+|	To prevent that:
+|	- every completed step is marked with a (run key, step) completion marker
+|	  and the orchestrator skips the handler if the step is already completed
+|	- every step is enqueued with a deterministic asynq TaskID (run key:step)
+|	  so a duplicate enqueue is rejected by asynq (ErrTaskIDConflict)
+|	  (a paused step is re-scheduled with run key:step:paused:poll, the poll is counted in the payload)
+|	The run key is the chain id plus the run id generated on every Build, so a chain
+|	rebuilt with the same id (e.g. the order chain dispatched again) runs all its steps,
+|	while a redelivered message of the same run still skips its completed steps
+|	- the step handler can get the step idempotency key from the context
+|	  using StepIdempotencyKey(ctx) to make its own side effects idempotent (e.g. payment provider key)
+|------------------------------------------
+*/
+
+type stepKeyCtx struct{}
+
+// StepTracker tracks the completed steps of the chains
+type StepTracker interface {
+	IsStepDone(ctx context.Context, chainID string, step int) (bool, error)
+	MarkStepDone(ctx context.Context, chainID string, step int) error
+}
+
+// RedisStepTracker stores the chains steps completion markers in redis
+type RedisStepTracker struct {
+	redis  *redis.Client
+	prefix string
+	ttl    time.Duration // how long the completion markers are kept
+}
+
+// NewRedisStepTracker creates a new redis step tracker
+func NewRedisStepTracker(client *redis.Client, ttl time.Duration) *RedisStepTracker {
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+
+	return &RedisStepTracker{
+		redis:  client,
+		prefix: "chainq",
+		ttl:    ttl,
+	}
+}
+
+// IsStepDone checks if the chain step is already completed
+func (t *RedisStepTracker) IsStepDone(ctx context.Context, chainID string, step int) (bool, error) {
+	n, err := t.redis.Exists(ctx, t.stepKey(chainID, step)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check chain step completion marker: %w", err)
+	}
+	return n > 0, nil
+}
+
+// MarkStepDone sets the chain step completion marker
+func (t *RedisStepTracker) MarkStepDone(ctx context.Context, chainID string, step int) error {
+	if err := t.redis.Set(ctx, t.stepKey(chainID, step), time.Now().Unix(), t.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set chain step completion marker: %w", err)
+	}
+	return nil
+}
+
+func (t *RedisStepTracker) stepKey(chainID string, step int) string {
+	return fmt.Sprintf("%s:chain:%s:step:%d:done", t.prefix, chainID, step)
+}
+
+// StepTaskID returns the deterministic asynq task id of the chain step
+func StepTaskID(chainID string, step int) string {
+	return fmt.Sprintf("%s:%d", chainID, step)
+}
+
+// PausedStepTaskID returns the deterministic asynq task id of the chain step re-scheduled while paused
+func PausedStepTaskID(chainID string, step, poll int) string {
+	return fmt.Sprintf("%s:paused:%d", StepTaskID(chainID, step), poll)
+}
+
+// StepIdempotencyKey returns the idempotency key of the chain step being processed (empty if not inside a chain)
+func StepIdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(stepKeyCtx{}).(string)
+	return key
+}
+
+// withStepIdempotencyKey adds the chain step idempotency key to the context
+func withStepIdempotencyKey(ctx context.Context, chainID string, step int) context.Context {
+	return context.WithValue(ctx, stepKeyCtx{}, StepTaskID(chainID, step))
+}
@@ -0,0 +1,98 @@
+package chainq_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	chainq "taskgo/pkg/asynq_chain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisControl_PauseResumeCancel(t *testing.T) {
+	_, client := newRedis(t)
+	control := chainq.NewRedisControl(client, time.Hour)
+	ctx := context.Background()
+
+	state, err := control.State(ctx, "CHAIN_ORDER_1")
+	require.NoError(t, err)
+	assert.Equal(t, chainq.ChainStateRunning, state)
+
+	require.NoError(t, control.Pause(ctx, "CHAIN_ORDER_1"))
+	state, _ = control.State(ctx, "CHAIN_ORDER_1")
+	assert.Equal(t, chainq.ChainStatePaused, state)
+
+	require.NoError(t, control.Resume(ctx, "CHAIN_ORDER_1"))
+	state, _ = control.State(ctx, "CHAIN_ORDER_1")
+	assert.Equal(t, chainq.ChainStateRunning, state)
+
+	// Cancel is final
+	require.NoError(t, control.Cancel(ctx, "CHAIN_ORDER_1"))
+	assert.ErrorContains(t, control.Pause(ctx, "CHAIN_ORDER_1"), "cancelled")
+	assert.ErrorContains(t, control.Resume(ctx, "CHAIN_ORDER_1"), "cancelled")
+	state, _ = control.State(ctx, "CHAIN_ORDER_1")
+	assert.Equal(t, chainq.ChainStateCancelled, state)
+
+	// A paused chain can be cancelled
+	require.NoError(t, control.Pause(ctx, "CHAIN_ORDER_2"))
+	require.NoError(t, control.Cancel(ctx, "CHAIN_ORDER_2"))
+	state, _ = control.State(ctx, "CHAIN_ORDER_2")
+	assert.Equal(t, chainq.ChainStateCancelled, state)
+
+	assert.Error(t, control.Pause(ctx, ""))
+}
+
+func TestRedisControl_PauseNeverOverwritesCancel(t *testing.T) {
+	_, client := newRedis(t)
+	control := chainq.NewRedisControl(client, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		var wg sync.WaitGroup
+		wg.Add(3)
+		go func() { defer wg.Done(); _ = control.Pause(ctx, "CHAIN_RACE") }()
+		go func() { defer wg.Done(); _ = control.Cancel(ctx, "CHAIN_RACE") }()
+		go func() { defer wg.Done(); _ = control.Resume(ctx, "CHAIN_RACE") }()
+		wg.Wait()
+
+		state, err := control.State(ctx, "CHAIN_RACE")
+		require.NoError(t, err)
+		require.Equal(t, chainq.ChainStateCancelled, state)
+		require.NoError(t, client.Del(ctx, "chainq:chain:CHAIN_RACE:state").Err())
+	}
+}
+
+func TestRedisControl_StateExpiresAndPauseAll(t *testing.T) {
+	server, client := newRedis(t)
+	control := chainq.NewRedisControl(client, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, control.Pause(ctx, "CHAIN_TTL"))
+	server.FastForward(2 * time.Minute)
+	state, _ := control.State(ctx, "CHAIN_TTL")
+	assert.Equal(t, chainq.ChainStateRunning, state)
+
+	paused, err := control.IsAllPaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	require.NoError(t, control.PauseAll(ctx))
+	paused, _ = control.IsAllPaused(ctx)
+	assert.True(t, paused)
+
+	require.NoError(t, control.ResumeAll(ctx))
+	paused, _ = control.IsAllPaused(ctx)
+	assert.False(t, paused)
+}
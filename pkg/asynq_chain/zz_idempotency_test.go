@@ -0,0 +1,76 @@
+package chainq_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	chainq "taskgo/pkg/asynq_chain"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTask struct{}
+
+func (stubTask) CreateTask() (*asynq.Task, error) {
+	return asynq.NewTask("test:stub", []byte(`{}`)), nil
+}
+func (stubTask) GetTaskType() string { return "test:stub" }
+func (stubTask) GetPayload() any     { return nil }
+
+func TestRedisStepTracker_MarkAndExpire(t *testing.T) {
+	server, client := newRedis(t)
+	tracker := chainq.NewRedisStepTracker(client, time.Hour)
+	ctx := context.Background()
+
+	done, err := tracker.IsStepDone(ctx, "CHAIN_ORDER_1:run", 0)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	require.NoError(t, tracker.MarkStepDone(ctx, "CHAIN_ORDER_1:run", 0))
+
+	done, _ = tracker.IsStepDone(ctx, "CHAIN_ORDER_1:run", 0)
+	assert.True(t, done)
+
+	// Other steps and other runs of the same chain aren't marked
+	done, _ = tracker.IsStepDone(ctx, "CHAIN_ORDER_1:run", 1)
+	assert.False(t, done)
+	done, _ = tracker.IsStepDone(ctx, "CHAIN_ORDER_1:other", 0)
+	assert.False(t, done)
+
+	server.FastForward(time.Hour + time.Second)
+	done, _ = tracker.IsStepDone(ctx, "CHAIN_ORDER_1:run", 0)
+	assert.False(t, done)
+}
+
+func TestChainBuild_RunKeyIsUniquePerBuild(t *testing.T) {
+	build := func() (*chainq.TaskMessage, chainq.ChainPayload) {
+		msg, err := chainq.NewChain(nil, nil, nil).WithID("CHAIN_ORDER_1").Then(stubTask{}).Build()
+		require.NoError(t, err)
+
+		var payload chainq.ChainPayload
+		require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+		return msg, payload
+	}
+
+	first, firstPayload := build()
+	second, secondPayload := build()
+
+	assert.Equal(t, "CHAIN_ORDER_1", firstPayload.ChainID)
+	assert.Equal(t, firstPayload.ChainID, secondPayload.ChainID)
+	assert.NotEmpty(t, firstPayload.RunID)
+	assert.NotEqual(t, firstPayload.RunKey(), secondPayload.RunKey())
+
+	assert.Equal(t, chainq.StepTaskID(firstPayload.RunKey(), 0), first.TaskID)
+	assert.NotEqual(t, first.TaskID, second.TaskID)
+}
+
+func TestChainPayload_RunKeyWithoutRunID(t *testing.T) {
+	payload := chainq.ChainPayload{ChainID: "CHAIN_ORDER_1"}
+	assert.Equal(t, "CHAIN_ORDER_1", payload.RunKey())
+	assert.Equal(t, "CHAIN_ORDER_1:2", chainq.StepTaskID(payload.RunKey(), 2))
+	assert.Equal(t, "CHAIN_ORDER_1:2:paused:3", chainq.PausedStepTaskID(payload.RunKey(), 2, 3))
+}
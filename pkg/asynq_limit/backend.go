@@ -0,0 +1,165 @@
+package limitq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Local backend (limits of the worker process)
+|------------------------------------------
+*/
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// LocalBackend keeps the limits counters in memory
+type LocalBackend struct {
+	mu      sync.Mutex
+	slots   map[string]int
+	windows map[string]*rateWindow
+}
+
+// NewLocalBackend creates a new in memory backend
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{
+		slots:   make(map[string]int),
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// AcquireSlot acquires a concurrency slot of the key (the ttl is not needed in memory)
+func (b *LocalBackend) AcquireSlot(ctx context.Context, key string, limit int, ttl time.Duration) (func(), bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.slots[key] >= limit {
+		return nil, false, nil
+	}
+	b.slots[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.slots[key]--
+		})
+	}, true, nil
+}
+
+// Allow counts the task start in the current fixed window of the key
+func (b *LocalBackend) Allow(ctx context.Context, key string, rate int, period time.Duration) (bool, time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	window, ok := b.windows[key]
+	if !ok || now.Sub(window.start) >= period {
+		window = &rateWindow{start: now}
+		b.windows[key] = window
+	}
+
+	if window.count >= rate {
+		return false, window.start.Add(period).Sub(now), nil
+	}
+	window.count++
+	return true, 0, nil
+}
+
+/*
+|------------------------------------------
+|  Redis backend (limits shared by all the workers)
+|------------------------------------------
+|	- concurrency slots: sorted set of the slots holders scored by their expiry time
+|	- rate: fixed window counter expiring with the window
+|------------------------------------------
+*/
+
+// acquireSlotScript removes the expired slots then adds the holder if there is a free slot
+var acquireSlotScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+if redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call("ZADD", KEYS[1], ARGV[3], ARGV[4])
+redis.call("PEXPIRE", KEYS[1], ARGV[5])
+return 1
+`)
+
+// allowScript increments the window counter and returns {count, window ttl in ms}
+var allowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// RedisBackend keeps the limits counters in redis so they are shared by the worker processes
+type RedisBackend struct {
+	redis  *redis.Client
+	prefix string
+}
+
+// NewRedisBackend creates a new redis backend
+func NewRedisBackend(client *redis.Client) *RedisBackend {
+	return &RedisBackend{
+		redis:  client,
+		prefix: "limitq",
+	}
+}
+
+// AcquireSlot acquires a concurrency slot of the key, the slot expires after the ttl if it's not released
+func (b *RedisBackend) AcquireSlot(ctx context.Context, key string, limit int, ttl time.Duration) (func(), bool, error) {
+	slotsKey := b.prefix + ":slots:" + key
+	holder := uuid.NewString()
+	now := time.Now()
+
+	acquired, err := acquireSlotScript.Run(ctx, b.redis, []string{slotsKey},
+		now.UnixMilli(), limit, now.Add(ttl).UnixMilli(), holder, ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return nil, false, err
+	}
+	if acquired != 1 {
+		return nil, false, nil
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// The task context may be done already
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			b.redis.ZRem(ctx, slotsKey, holder)
+		})
+	}, true, nil
+}
+
+// Allow counts the task start in the current fixed window of the key
+func (b *RedisBackend) Allow(ctx context.Context, key string, rate int, period time.Duration) (bool, time.Duration, error) {
+	windowKey := b.prefix + ":rate:" + key
+
+	result, err := allowScript.Run(ctx, b.redis, []string{windowKey}, period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	count, ttl := result[0], time.Duration(result[1])*time.Millisecond
+	if count > int64(rate) {
+		if ttl <= 0 {
+			ttl = period
+		}
+		return false, ttl, nil
+	}
+	return true, 0, nil
+}
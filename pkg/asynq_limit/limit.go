@@ -0,0 +1,225 @@
+package limitq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+/*
+|------------------------------------------
+|  Task concurrency and rate limits
+|------------------------------------------
+|	Limits are keyed by task type or queue (both are checked if both are set):
+|	- concurrency: max tasks processed at the same time
+|	- rate:        max tasks started per period
+|
+|	The limits are shared by the worker processes with the redis backend (local backend = per process).
+|	A task over the limit returns a RateLimitError, asynq re-schedules it without counting a failure
+|	(use IsFailure and RetryDelayFunc in the asynq server config).
+|------------------------------------------
+*/
+
+// Limit is the concurrency and rate limit of a task type or queue (0 = unlimited)
+type Limit struct {
+	Concurrency int
+	Rate        int
+	Period      time.Duration // rate period (default 1s)
+	RetryIn     time.Duration // delay before re-running a task over the concurrency limit (default 1s)
+}
+
+// Backend holds the limits counters
+type Backend interface {
+	// AcquireSlot acquires a concurrency slot of the key, the slot is released by the returned func
+	// or expires after the ttl (e.g. the worker crashed)
+	AcquireSlot(ctx context.Context, key string, limit int, ttl time.Duration) (release func(), ok bool, err error)
+
+	// Allow checks and counts a task start in the current rate window of the key,
+	// returns the time left in the window if the rate is reached
+	Allow(ctx context.Context, key string, rate int, period time.Duration) (ok bool, retryIn time.Duration, err error)
+}
+
+// Limiter applies the task types and queues limits
+type Limiter struct {
+	mu        sync.RWMutex
+	backend   Backend
+	queues    map[string]Limit
+	taskTypes map[string]Limit
+	slotTTL   time.Duration
+}
+
+// New creates a new limiter using the given backend
+func New(backend Backend) *Limiter {
+	return &Limiter{
+		backend:   backend,
+		queues:    make(map[string]Limit),
+		taskTypes: make(map[string]Limit),
+		slotTTL:   30 * time.Minute,
+	}
+}
+
+// SetQueue sets the limit of all the tasks processed from the queue
+func (l *Limiter) SetQueue(queue string, limit Limit) *Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queues[queue] = limit
+	return l
+}
+
+// SetTaskType sets the limit of the task type
+func (l *Limiter) SetTaskType(taskType string, limit Limit) *Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.taskTypes[taskType] = limit
+	return l
+}
+
+// SetSlotTTL sets the max time a concurrency slot is held if the task has no deadline
+func (l *Limiter) SetSlotTTL(ttl time.Duration) *Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ttl > 0 {
+		l.slotTTL = ttl
+	}
+	return l
+}
+
+// Middleware returns the task middleware applying the limits of the task type and the task queue
+func (l *Limiter) Middleware() asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			release, err := l.Acquire(ctx, task)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			return next.ProcessTask(ctx, task)
+		})
+	}
+}
+
+// Acquire acquires the concurrency slots and the rate tokens of the task,
+// returns a RateLimitError if one of the limits is reached
+func (l *Limiter) Acquire(ctx context.Context, task *asynq.Task) (func(), error) {
+	queue, _ := asynq.GetQueueName(ctx)
+
+	l.mu.RLock()
+	var keys []string
+	var limits []Limit
+	if limit, ok := l.taskTypes[task.Type()]; ok {
+		keys, limits = append(keys, "task_type:"+task.Type()), append(limits, limit)
+	}
+	if limit, ok := l.queues[queue]; ok && queue != "" {
+		keys, limits = append(keys, "queue:"+queue), append(limits, limit)
+	}
+	slotTTL := l.slotTTL
+	l.mu.RUnlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		slotTTL = time.Until(deadline) + time.Minute
+	}
+
+	var releases []func()
+	releaseAll := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+
+	for i, key := range keys {
+		limit := limits[i]
+
+		if limit.Concurrency > 0 {
+			release, ok, err := l.backend.AcquireSlot(ctx, key, limit.Concurrency, slotTTL)
+			if err != nil {
+				releaseAll()
+				return nil, fmt.Errorf("failed to acquire a concurrency slot of %s: %w", key, err)
+			}
+			if !ok {
+				releaseAll()
+				return nil, &RateLimitError{Key: key, RetryIn: limit.retryIn()}
+			}
+			releases = append(releases, release)
+		}
+
+		if limit.Rate > 0 {
+			ok, retryIn, err := l.backend.Allow(ctx, key, limit.Rate, limit.period())
+			if err != nil {
+				releaseAll()
+				return nil, fmt.Errorf("failed to check the rate limit of %s: %w", key, err)
+			}
+			if !ok {
+				releaseAll()
+				return nil, &RateLimitError{Key: key, RetryIn: retryIn}
+			}
+		}
+	}
+
+	return releaseAll, nil
+}
+
+func (l Limit) period() time.Duration {
+	if l.Period <= 0 {
+		return time.Second
+	}
+	return l.Period
+}
+
+func (l Limit) retryIn() time.Duration {
+	if l.RetryIn <= 0 {
+		return time.Second
+	}
+	return l.RetryIn
+}
+
+/*
+|------------------------------------------
+|  Rate limit error
+|------------------------------------------
+*/
+
+// RateLimitError is returned when the task is over its limit, the task is re-scheduled after RetryIn
+type RateLimitError struct {
+	Key     string
+	RetryIn time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("task limit reached for %s, retry in %s", e.Key, e.RetryIn)
+}
+
+// Throttled marks the error as a throttled (not failed) task
+func (e *RateLimitError) Throttled() bool {
+	return true
+}
+
+// IsRateLimitError checks if the task was throttled by a limit
+func IsRateLimitError(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// IsFailure is the asynq IsFailure func, the throttled tasks are not counted as failures
+func IsFailure(err error) bool {
+	return !IsRateLimitError(err)
+}
+
+// RetryDelayFunc re-schedules the throttled tasks after their retry delay, the other errors use the fallback
+func RetryDelayFunc(fallback asynq.RetryDelayFunc) asynq.RetryDelayFunc {
+	if fallback == nil {
+		fallback = asynq.DefaultRetryDelayFunc
+	}
+
+	return func(n int, err error, task *asynq.Task) time.Duration {
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			return max(rateLimitErr.RetryIn, time.Second)
+		}
+		return fallback(n, err, task)
+	}
+}
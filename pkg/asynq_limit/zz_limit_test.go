@@ -0,0 +1,73 @@
+package limitq_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	limitq "taskgo/pkg/asynq_limit"
+
+	"github.com/hibiken/asynq"
+)
+
+func TestConcurrencyLimitThrottles(t *testing.T) {
+	limiter := limitq.New(limitq.NewLocalBackend()).
+		SetTaskType("process:payment", limitq.Limit{Concurrency: 1, RetryIn: 3 * time.Second})
+	task := asynq.NewTask("process:payment", nil)
+
+	release, err := limiter.Acquire(context.Background(), task)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = limiter.Acquire(context.Background(), task)
+	var rateLimitErr *limitq.RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryIn != 3*time.Second {
+		t.Fatalf("expected a rate limit error with 3s retry, got %v", err)
+	}
+
+	release()
+	if _, err := limiter.Acquire(context.Background(), task); err != nil {
+		t.Fatalf("expected a free slot after release, got %v", err)
+	}
+}
+
+func TestRateLimitThrottles(t *testing.T) {
+	limiter := limitq.New(limitq.NewLocalBackend()).
+		SetTaskType("process:payment", limitq.Limit{Rate: 2, Period: time.Minute})
+	task := asynq.NewTask("process:payment", nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Acquire(context.Background(), task); err != nil {
+			t.Fatalf("start %d: unexpected error: %v", i, err)
+		}
+	}
+
+	_, err := limiter.Acquire(context.Background(), task)
+	if !limitq.IsRateLimitError(err) {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+
+	// Other task types are not limited
+	if _, err := limiter.Acquire(context.Background(), asynq.NewTask("inventory:check", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestThrottledTasksAreNotFailures(t *testing.T) {
+	err := &limitq.RateLimitError{Key: "queue:payments", RetryIn: 5 * time.Second}
+	wrapped := errors.Join(errors.New("chain failed"), err)
+
+	if limitq.IsFailure(wrapped) {
+		t.Fatal("expected throttled task not to be a failure")
+	}
+
+	delay := limitq.RetryDelayFunc(func(n int, e error, task *asynq.Task) time.Duration { return time.Hour })
+	if got := delay(0, wrapped, asynq.NewTask("test", nil)); got != 5*time.Second {
+		t.Fatalf("expected 5s delay, got %s", got)
+	}
+	if got := delay(0, errors.New("other"), asynq.NewTask("test", nil)); got != time.Hour {
+		t.Fatalf("expected the fallback delay, got %s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package metricsq
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Worker health handlers
+|------------------------------------------
+|	GET /livez    -> the worker process is alive
+|	GET /readyz   -> the last readiness check result (set by the worker every check interval)
+|	GET /metrics  -> tasks metrics in the prometheus text format
+|------------------------------------------
+*/
+
+// Readiness holds the last readiness check result of the worker dependencies
+type Readiness struct {
+	mu        sync.RWMutex
+	ready     bool
+	checks    map[string]string
+	checkedAt time.Time
+}
+
+// Set stores the readiness check result (checks maps every dependency to "ok" or its error)
+func (r *Readiness) Set(ready bool, checks map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ready, r.checks, r.checkedAt = ready, checks, time.Now()
+}
+
+// Get returns the last readiness check result (not ready until the first check)
+func (r *Readiness) Get() (bool, map[string]string, time.Time) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ready, r.checks, r.checkedAt
+}
+
+// NewHealthHandler returns the health server handler (liveness, readiness and metrics)
+func NewHealthHandler(collector *Collector, readiness *Readiness) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, map[string]any{"status": "alive"})
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ready, checks, checkedAt := readiness.Get()
+		code, status := http.StatusOK, "ready"
+		if !ready {
+			code, status = http.StatusServiceUnavailable, "not_ready"
+		}
+		writeJson(w, code, map[string]any{"status": status, "checks": checks, "checked_at": checkedAt})
+	})
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := collector.WritePrometheus(w); err != nil {
+			log.Println("Failed to write worker metrics:", err)
+		}
+	})
+
+	return mux
+}
+
+func writeJson(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,172 @@
+package metricsq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Worker Tasks Metrics
+|------------------------------------------
+|	Per task type counters collected by the worker handlers wrapper:
+|	- processed / failed / throttled tasks
+|	- processing duration (sum + count)
+|	- in-flight tasks
+|	Exposed in the prometheus text format (no client library needed)
+|------------------------------------------
+*/
+
+type taskMetrics struct {
+	processed     uint64
+	failed        uint64
+	throttled     uint64
+	durationSum   float64 // seconds
+	durationCount uint64
+	inFlight      int64
+}
+
+// Collector collects the tasks metrics of the worker
+type Collector struct {
+	mu        sync.Mutex
+	namespace string
+	tasks     map[string]*taskMetrics
+}
+
+// TaskSnapshot is a copy of the metrics of a task type
+type TaskSnapshot struct {
+	TaskType      string  `json:"task_type"`
+	Processed     uint64  `json:"processed"`
+	Failed        uint64  `json:"failed"`
+	Throttled     uint64  `json:"throttled"`
+	DurationSum   float64 `json:"duration_seconds_sum"`
+	DurationCount uint64  `json:"duration_seconds_count"`
+	InFlight      int64   `json:"in_flight"`
+}
+
+// NewCollector creates a new metrics collector (namespace is the prefix of the metrics names)
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "worker"
+	}
+
+	return &Collector{
+		namespace: namespace,
+		tasks:     make(map[string]*taskMetrics),
+	}
+}
+
+// Start marks the task as in-flight, the returned function must be called when the task is done
+func (c *Collector) Start(taskType string) func(err error) {
+	start := time.Now()
+
+	c.mu.Lock()
+	c.task(taskType).inFlight++
+	c.mu.Unlock()
+
+	return func(err error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		m := c.task(taskType)
+		m.inFlight--
+
+		// Throttled tasks (over a limit) were not processed and will run again
+		if isThrottled(err) {
+			m.throttled++
+			return
+		}
+
+		m.processed++
+		m.durationSum += time.Since(start).Seconds()
+		m.durationCount++
+		if err != nil {
+			m.failed++
+		}
+	}
+}
+
+// Snapshot returns a copy of the metrics sorted by task type
+func (c *Collector) Snapshot() []TaskSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots := make([]TaskSnapshot, 0, len(c.tasks))
+	for taskType, m := range c.tasks {
+		snapshots = append(snapshots, TaskSnapshot{
+			TaskType:      taskType,
+			Processed:     m.processed,
+			Failed:        m.failed,
+			Throttled:     m.throttled,
+			DurationSum:   m.durationSum,
+			DurationCount: m.durationCount,
+			InFlight:      m.inFlight,
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TaskType < snapshots[j].TaskType
+	})
+
+	return snapshots
+}
+
+// WritePrometheus writes the metrics in the prometheus text exposition format
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	snapshots := c.Snapshot()
+
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(s TaskSnapshot) string
+	}{
+		{"tasks_processed_total", "Total number of processed tasks.", "counter", func(s TaskSnapshot) string { return fmt.Sprint(s.Processed) }},
+		{"tasks_failed_total", "Total number of failed tasks.", "counter", func(s TaskSnapshot) string { return fmt.Sprint(s.Failed) }},
+		{"tasks_throttled_total", "Total number of tasks re-scheduled by a concurrency or rate limit.", "counter", func(s TaskSnapshot) string { return fmt.Sprint(s.Throttled) }},
+		{"task_duration_seconds_sum", "Total time spent processing tasks in seconds.", "counter", func(s TaskSnapshot) string { return fmt.Sprint(s.DurationSum) }},
+		{"task_duration_seconds_count", "Number of observed task durations.", "counter", func(s TaskSnapshot) string { return fmt.Sprint(s.DurationCount) }},
+		{"tasks_in_flight", "Number of tasks currently being processed.", "gauge", func(s TaskSnapshot) string { return fmt.Sprint(s.InFlight) }},
+	}
+
+	for _, metric := range metrics {
+		name := c.namespace + "_" + metric.name
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, metric.help, name, metric.kind); err != nil {
+			return err
+		}
+		for _, s := range snapshots {
+			if _, err := fmt.Fprintf(w, "%s{task_type=\"%s\"} %s\n", name, escapeLabel(s.TaskType), metric.value(s)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// task returns the metrics of the task type (must be called with the lock held)
+func (c *Collector) task(taskType string) *taskMetrics {
+	m, ok := c.tasks[taskType]
+	if !ok {
+		m = &taskMetrics{}
+		c.tasks[taskType] = m
+	}
+	return m
+}
+
+// isThrottled checks if the error marks a throttled task (e.g. limitq.RateLimitError)
+func isThrottled(err error) bool {
+	var throttled interface{ Throttled() bool }
+	return errors.As(err, &throttled) && throttled.Throttled()
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelReplacer.Replace(value)
+}
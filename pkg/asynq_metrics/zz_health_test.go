@@ -0,0 +1,75 @@
+package metricsq_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metricsq "taskgo/pkg/asynq_metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func TestHealthHandler_Livez(t *testing.T) {
+	handler := metricsq.NewHealthHandler(metricsq.NewCollector("test"), &metricsq.Readiness{})
+
+	w := get(handler, "/livez")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "alive", decode(t, w)["status"])
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	readiness := &metricsq.Readiness{}
+	handler := metricsq.NewHealthHandler(metricsq.NewCollector("test"), readiness)
+
+	// Not ready until the first check
+	w := get(handler, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "not_ready", decode(t, w)["status"])
+
+	readiness.Set(true, map[string]string{"redis": "ok", "database": "ok"})
+	w = get(handler, "/readyz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := decode(t, w)
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, map[string]any{"redis": "ok", "database": "ok"}, body["checks"])
+	assert.NotEmpty(t, body["checked_at"])
+
+	readiness.Set(false, map[string]string{"redis": "dial tcp: connection refused", "database": "ok"})
+	w = get(handler, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	body = decode(t, w)
+	assert.Equal(t, "not_ready", body["status"])
+	assert.Equal(t, "dial tcp: connection refused", body["checks"].(map[string]any)["redis"])
+}
+
+func TestHealthHandler_Metrics(t *testing.T) {
+	collector := metricsq.NewCollector("taskgo_worker")
+	collector.Start("process:payment")(nil)
+	handler := metricsq.NewHealthHandler(collector, &metricsq.Readiness{})
+
+	w := get(handler, "/metrics")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `taskgo_worker_tasks_processed_total{task_type="process:payment"} 1`+"\n")
+}
+
+func TestHealthHandler_UnknownPath(t *testing.T) {
+	handler := metricsq.NewHealthHandler(metricsq.NewCollector("test"), &metricsq.Readiness{})
+	assert.Equal(t, http.StatusNotFound, get(handler, "/health").Code)
+}
@@ -0,0 +1,110 @@
+package metricsq_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	metricsq "taskgo/pkg/asynq_metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type throttledError struct{}
+
+func (throttledError) Error() string   { return "over the rate limit" }
+func (throttledError) Throttled() bool { return true }
+
+func TestCollector_CountsTheTasks(t *testing.T) {
+	collector := metricsq.NewCollector("test")
+
+	first := collector.Start("process:payment")
+	second := collector.Start("process:payment")
+
+	// Both tasks are in flight until they are done
+	snapshots := collector.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, int64(2), snapshots[0].InFlight)
+	assert.Zero(t, snapshots[0].Processed)
+
+	time.Sleep(10 * time.Millisecond)
+	first(nil)
+	second(errors.New("card declined"))
+
+	snapshots = collector.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, metricsq.TaskSnapshot{
+		TaskType:      "process:payment",
+		Processed:     2,
+		Failed:        1,
+		DurationSum:   snapshots[0].DurationSum,
+		DurationCount: 2,
+	}, snapshots[0])
+	assert.GreaterOrEqual(t, snapshots[0].DurationSum, (20 * time.Millisecond).Seconds())
+}
+
+func TestCollector_ThrottledTasksAreNotProcessed(t *testing.T) {
+	collector := metricsq.NewCollector("test")
+
+	// Wrapped like the chain steps errors
+	collector.Start("process:payment")(errors.Join(errors.New("chain failed"), throttledError{}))
+
+	snapshots := collector.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, uint64(1), snapshots[0].Throttled)
+	assert.Zero(t, snapshots[0].Processed)
+	assert.Zero(t, snapshots[0].Failed)
+	assert.Zero(t, snapshots[0].DurationCount)
+	assert.Zero(t, snapshots[0].InFlight)
+}
+
+func TestCollector_SnapshotIsSortedByTaskType(t *testing.T) {
+	collector := metricsq.NewCollector("test")
+	collector.Start("send:email")(nil)
+	collector.Start("check:inventory")(nil)
+	collector.Start("process:payment")(nil)
+
+	var taskTypes []string
+	for _, snapshot := range collector.Snapshot() {
+		taskTypes = append(taskTypes, snapshot.TaskType)
+	}
+	assert.Equal(t, []string{"check:inventory", "process:payment", "send:email"}, taskTypes)
+}
+
+func TestCollector_WritePrometheus(t *testing.T) {
+	collector := metricsq.NewCollector("taskgo_worker")
+	collector.Start("process:payment")(nil)
+	collector.Start("process:payment")(errors.New("card declined"))
+	collector.Start("process:payment")(throttledError{})
+	collector.Start(`odd"type`)
+	collector.Start(`odd"type`)
+
+	var out bytes.Buffer
+	require.NoError(t, collector.WritePrometheus(&out))
+
+	body := out.String()
+	for _, line := range []string{
+		"# HELP taskgo_worker_tasks_processed_total Total number of processed tasks.",
+		"# TYPE taskgo_worker_tasks_processed_total counter",
+		`taskgo_worker_tasks_processed_total{task_type="process:payment"} 2`,
+		`taskgo_worker_tasks_failed_total{task_type="process:payment"} 1`,
+		`taskgo_worker_tasks_throttled_total{task_type="process:payment"} 1`,
+		`taskgo_worker_task_duration_seconds_count{task_type="process:payment"} 2`,
+		"# TYPE taskgo_worker_tasks_in_flight gauge",
+		`taskgo_worker_tasks_in_flight{task_type="process:payment"} 0`,
+		`taskgo_worker_tasks_in_flight{task_type="odd\"type"} 2`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+}
+
+func TestNewCollector_DefaultNamespace(t *testing.T) {
+	collector := metricsq.NewCollector("")
+	collector.Start("send:email")(nil)
+
+	var out bytes.Buffer
+	require.NoError(t, collector.WritePrometheus(&out))
+	assert.Contains(t, out.String(), `worker_tasks_processed_total{task_type="send:email"} 1`)
+}
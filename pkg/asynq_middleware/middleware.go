@@ -0,0 +1,194 @@
+package middlewareq
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	chainq "taskgo/pkg/asynq_chain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+/*
+|------------------------------------------
+|  Task Middleware (the worker equivalent of gin middleware)
+|------------------------------------------
+|	Global middleware are applied on the worker mux (all the tasks),
+|	handler middleware are applied on the handler itself so they also run when the handler is executed as a chain step.
+|------------------------------------------
+|	Example:
+|------------------------------------------
+|	tasks.TypeProcessPayment: middlewareq.Wrap(handler,
+|		middlewareq.Timeout(30*time.Second),
+|		middlewareq.ConcurrencyLimit(5),
+|	),
+|------------------------------------------
+*/
+
+type Middleware = asynq.MiddlewareFunc
+
+// Wrap wraps the handler with the middleware (the first middleware is the outermost one)
+func Wrap(handler asynq.Handler, middleware ...Middleware) asynq.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+/*
+|------------------------------------------
+|  Recover
+|------------------------------------------
+*/
+
+// Recover recovers the handler panics, logs the stack trace and fails the task
+func Recover(logger chainq.Logger) Middleware {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					taskID, _ := asynq.GetTaskID(ctx)
+					logger.Error(fmt.Sprintf("task handler panic: %v", r),
+						"task_type", task.Type(),
+						"task_id", taskID,
+						"trace_id", TraceID(ctx),
+						"stack", string(debug.Stack()),
+					)
+					err = fmt.Errorf("task handler panic: %v", r)
+				}
+			}()
+
+			return next.ProcessTask(ctx, task)
+		})
+	}
+}
+
+/*
+|------------------------------------------
+|  Timeout
+|------------------------------------------
+*/
+
+// Timeout cancels the handler context after the given duration
+func Timeout(timeout time.Duration) Middleware {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return next.ProcessTask(ctx, task)
+		})
+	}
+}
+
+/*
+|------------------------------------------
+|  Tracing
+|------------------------------------------
+*/
+
+type traceIDKey struct{}
+
+// Tracing propagates the trace id (e.g. the http request id which dispatched the task) into the handler context
+// the trace id is read from the task by the extractor, the task id is used if there is no trace id
+func Tracing(extract func(task *asynq.Task) string) Middleware {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			// Keep the parent trace (e.g. chain step executed by the orchestrator)
+			if TraceID(ctx) != "" {
+				return next.ProcessTask(ctx, task)
+			}
+
+			traceID := ""
+			if extract != nil {
+				traceID = extract(task)
+			}
+			if traceID == "" {
+				traceID, _ = asynq.GetTaskID(ctx)
+			}
+			if traceID == "" {
+				traceID = uuid.NewString()
+			}
+
+			return next.ProcessTask(WithTraceID(ctx, traceID), task)
+		})
+	}
+}
+
+// WithTraceID returns a new context holding the trace id
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns the trace id of the task context (empty if there is no trace)
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+/*
+|------------------------------------------
+|  Concurrency limit
+|------------------------------------------
+*/
+
+// ConcurrencyLimit limits the number of tasks processed at the same time by the handler in this worker process
+// the task waits for a free slot until its context is done
+func ConcurrencyLimit(limit int) Middleware {
+	slots := make(chan struct{}, max(limit, 1))
+
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return fmt.Errorf("task %s waiting for a concurrency slot: %w", task.Type(), ctx.Err())
+			}
+			defer func() { <-slots }()
+
+			return next.ProcessTask(ctx, task)
+		})
+	}
+}
+
+/*
+|------------------------------------------
+|  Database transaction scoping
+|------------------------------------------
+*/
+
+type txKey struct{}
+
+// Transaction runs the handler inside a database transaction,
+// committed if the handler succeeds and rolled back if it fails (or panics)
+func Transaction(db *gorm.DB) Middleware {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			// Already inside a transaction (nested handler)
+			if _, ok := Tx(ctx); ok {
+				return next.ProcessTask(ctx, task)
+			}
+
+			return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return next.ProcessTask(context.WithValue(ctx, txKey{}, tx), task)
+			})
+		})
+	}
+}
+
+// Tx returns the transaction of the task context
+func Tx(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// DB returns the transaction of the task context or the given db if the task is not running in a transaction
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := Tx(ctx); ok {
+		return tx
+	}
+	return db
+}
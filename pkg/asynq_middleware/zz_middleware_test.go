@@ -0,0 +1,95 @@
+package middlewareq_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	middlewareq "taskgo/pkg/asynq_middleware"
+
+	"github.com/hibiken/asynq"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...any)  {}
+func (nopLogger) Error(msg string, fields ...any) {}
+func (nopLogger) Warn(msg string, fields ...any)  {}
+
+func TestWrapOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) middlewareq.Middleware {
+		return func(next asynq.Handler) asynq.Handler {
+			return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+				calls = append(calls, name)
+				return next.ProcessTask(ctx, task)
+			})
+		}
+	}
+
+	handler := middlewareq.Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		calls = append(calls, "handler")
+		return nil
+	}), record("first"), record("second"))
+
+	if err := handler.ProcessTask(context.Background(), asynq.NewTask("test", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "first,second,handler" {
+		t.Fatalf("expected first,second,handler got %s", got)
+	}
+}
+
+func TestRecoverReturnsError(t *testing.T) {
+	handler := middlewareq.Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		panic("boom")
+	}), middlewareq.Recover(nopLogger{}))
+
+	err := handler.ProcessTask(context.Background(), asynq.NewTask("test", nil))
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+}
+
+func TestTracingKeepsTraceID(t *testing.T) {
+	var traceID string
+	handler := middlewareq.Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		traceID = middlewareq.TraceID(ctx)
+		return nil
+	}), middlewareq.Tracing(func(task *asynq.Task) string { return "request-id" }))
+
+	_ = handler.ProcessTask(context.Background(), asynq.NewTask("test", nil))
+	if traceID != "request-id" {
+		t.Fatalf("expected request-id got %q", traceID)
+	}
+
+	_ = handler.ProcessTask(middlewareq.WithTraceID(context.Background(), "parent"), asynq.NewTask("test", nil))
+	if traceID != "parent" {
+		t.Fatalf("expected the parent trace id got %q", traceID)
+	}
+}
+
+func TestConcurrencyLimitWaitsForSlot(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	handler := middlewareq.Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		close(started)
+		<-release
+		return nil
+	}), middlewareq.ConcurrencyLimit(1))
+
+	go func() { _ = handler.ProcessTask(context.Background(), asynq.NewTask("test", nil)) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := handler.ProcessTask(ctx, asynq.NewTask("test", nil))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while waiting for a slot, got %v", err)
+	}
+	close(release)
+}
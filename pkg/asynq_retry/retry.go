@@ -0,0 +1,194 @@
+package retryq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+/*
+|------------------------------------------
+|  Retry Policies
+|------------------------------------------
+|	A policy decides how many times a task is retried and how long to wait between the retries:
+|	- constant:    delay, delay, delay, ...
+|	- exponential: delay, delay*factor, delay*factor^2, ... (capped by max_delay)
+|	- jitter:      random delay between 0 and the exponential delay (full jitter)
+|
+|	Policies are resolved by: task type -> queue -> default
+|	The errors matching the non-retryable checks are archived directly (asynq.SkipRetry)
+|------------------------------------------
+*/
+
+type Strategy string
+
+const maxBackoff = time.Duration(1 << 62) // avoid overflowing the delay on big retry counts
+
+const (
+	StrategyConstant    Strategy = "constant"
+	StrategyExponential Strategy = "exponential"
+	StrategyJitter      Strategy = "jitter"
+)
+
+// Policy is the retry policy of a task type or queue
+type Policy struct {
+	Strategy Strategy
+	MaxRetry int
+	Delay    time.Duration // base delay
+	MaxDelay time.Duration // max delay for exponential and jitter strategies (0 = no max)
+	Factor   float64       // exponential growth factor (default 2)
+}
+
+// Backoff returns the delay before the next retry (retried is the number of times the task was retried)
+func (p Policy) Backoff(retried int) time.Duration {
+	delay := p.Delay
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	if p.Strategy == StrategyConstant || p.Strategy == "" {
+		return delay
+	}
+
+	factor := p.Factor
+	if factor <= 1 {
+		factor = 2
+	}
+
+	backoff := float64(delay) * math.Pow(factor, float64(retried))
+	if p.MaxDelay > 0 && backoff > float64(p.MaxDelay) {
+		backoff = float64(p.MaxDelay)
+	}
+	if backoff > float64(maxBackoff) {
+		backoff = float64(maxBackoff)
+	}
+
+	if p.Strategy == StrategyJitter {
+		return time.Duration(rand.Int64N(int64(backoff) + 1))
+	}
+
+	return time.Duration(backoff)
+}
+
+// Policies holds the default, per queue and per task type retry policies
+type Policies struct {
+	mu           sync.RWMutex
+	defaultP     Policy
+	queues       map[string]Policy
+	taskTypes    map[string]Policy
+	nonRetryable []func(error) bool
+}
+
+// NewPolicies creates a new policies registry with the default policy
+func NewPolicies(defaultPolicy Policy) *Policies {
+	return &Policies{
+		defaultP:  defaultPolicy,
+		queues:    make(map[string]Policy),
+		taskTypes: make(map[string]Policy),
+	}
+}
+
+// SetQueue sets the retry policy of all the tasks of the queue
+func (p *Policies) SetQueue(queue string, policy Policy) *Policies {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queues[queue] = policy
+	return p
+}
+
+// SetTaskType sets the retry policy of the task type (has priority over the queue policy)
+func (p *Policies) SetTaskType(taskType string, policy Policy) *Policies {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.taskTypes[taskType] = policy
+	return p
+}
+
+// SkipRetryIf registers a check of the errors that should never be retried
+func (p *Policies) SkipRetryIf(check func(error) bool) *Policies {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nonRetryable = append(p.nonRetryable, check)
+	return p
+}
+
+// For returns the retry policy of the task type on the queue
+func (p *Policies) For(taskType, queue string) Policy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if policy, ok := p.taskTypes[taskType]; ok {
+		return policy
+	}
+	if policy, ok := p.queues[queue]; ok {
+		return policy
+	}
+	return p.defaultP
+}
+
+// MaxRetry returns the asynq max retry option of the task type on the queue
+func (p *Policies) MaxRetry(taskType, queue string) asynq.Option {
+	return asynq.MaxRetry(p.For(taskType, queue).MaxRetry)
+}
+
+// IsRetryable checks if the error can be retried
+func (p *Policies) IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, asynq.SkipRetry) {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, check := range p.nonRetryable {
+		if check(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// Classify wraps the handler error with the task queue (used to resolve the retry delay)
+// and marks the non-retryable errors with asynq.SkipRetry
+func (p *Policies) Classify(queue string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, asynq.SkipRetry) && !p.IsRetryable(err) {
+		err = fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+	}
+
+	return &queueError{queue: queue, err: err}
+}
+
+// RetryDelayFunc returns the asynq retry delay func using the task type and queue policy
+func (p *Policies) RetryDelayFunc() asynq.RetryDelayFunc {
+	return func(n int, err error, task *asynq.Task) time.Duration {
+		var qErr *queueError
+		queue := ""
+		if errors.As(err, &qErr) {
+			queue = qErr.queue
+		}
+		return p.For(task.Type(), queue).Backoff(n)
+	}
+}
+
+// queueError keeps the queue of the failed task so the retry delay can be resolved by the queue policy
+type queueError struct {
+	queue string
+	err   error
+}
+
+func (e *queueError) Error() string {
+	return e.err.Error()
+}
+
+func (e *queueError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,99 @@
+package retryq_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	retryq "taskgo/pkg/asynq_retry"
+
+	"github.com/hibiken/asynq"
+)
+
+var errNotRetryable = errors.New("not retryable")
+
+func newPolicies() *retryq.Policies {
+	return retryq.NewPolicies(retryq.Policy{Strategy: retryq.StrategyConstant, MaxRetry: 3, Delay: 10 * time.Second}).
+		SetQueue("payments", retryq.Policy{Strategy: retryq.StrategyExponential, MaxRetry: 5, Delay: time.Second, MaxDelay: 5 * time.Second}).
+		SetTaskType("process:payment", retryq.Policy{Strategy: retryq.StrategyJitter, MaxRetry: 8, Delay: time.Second, MaxDelay: time.Minute}).
+		SkipRetryIf(func(err error) bool { return errors.Is(err, errNotRetryable) })
+}
+
+func TestConstantBackoff(t *testing.T) {
+	p := retryq.Policy{Strategy: retryq.StrategyConstant, Delay: 10 * time.Second}
+	for n := 0; n < 5; n++ {
+		if got := p.Backoff(n); got != 10*time.Second {
+			t.Fatalf("retry %d: expected 10s, got %s", n, got)
+		}
+	}
+}
+
+func TestExponentialBackoffIsCapped(t *testing.T) {
+	p := retryq.Policy{Strategy: retryq.StrategyExponential, Delay: time.Second, MaxDelay: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for n, want := range expected {
+		if got := p.Backoff(n); got != want {
+			t.Fatalf("retry %d: expected %s, got %s", n, want, got)
+		}
+	}
+
+	if got := (retryq.Policy{Strategy: retryq.StrategyExponential, Delay: time.Second}).Backoff(1000); got <= 0 {
+		t.Fatalf("expected positive delay on big retry counts, got %s", got)
+	}
+}
+
+func TestJitterBackoffIsWithinBounds(t *testing.T) {
+	p := retryq.Policy{Strategy: retryq.StrategyJitter, Delay: time.Second, MaxDelay: 8 * time.Second}
+	for i := 0; i < 100; i++ {
+		if got := p.Backoff(10); got < 0 || got > 8*time.Second {
+			t.Fatalf("jitter delay out of bounds: %s", got)
+		}
+	}
+}
+
+func TestPolicyResolution(t *testing.T) {
+	p := newPolicies()
+
+	if got := p.For("process:payment", "payments").MaxRetry; got != 8 {
+		t.Fatalf("expected task type policy (8), got %d", got)
+	}
+	if got := p.For("refund:payment", "payments").MaxRetry; got != 5 {
+		t.Fatalf("expected queue policy (5), got %d", got)
+	}
+	if got := p.For("inventory:check", "default").MaxRetry; got != 3 {
+		t.Fatalf("expected default policy (3), got %d", got)
+	}
+}
+
+func TestClassifySkipsNonRetryableErrors(t *testing.T) {
+	p := newPolicies()
+
+	err := p.Classify("payments", fmt.Errorf("step failed: %w", errNotRetryable))
+	if !errors.Is(err, asynq.SkipRetry) {
+		t.Fatalf("expected SkipRetry, got %v", err)
+	}
+
+	err = p.Classify("payments", errors.New("provider timeout"))
+	if errors.Is(err, asynq.SkipRetry) {
+		t.Fatalf("expected retryable error, got %v", err)
+	}
+
+	if p.Classify("payments", nil) != nil {
+		t.Fatal("expected nil error")
+	}
+}
+
+func TestRetryDelayFuncUsesQueuePolicy(t *testing.T) {
+	p := newPolicies()
+	delayFunc := p.RetryDelayFunc()
+	task := asynq.NewTask("refund:payment", nil)
+
+	if got := delayFunc(2, p.Classify("payments", errors.New("failed")), task); got != 4*time.Second {
+		t.Fatalf("expected payments queue exponential delay (4s), got %s", got)
+	}
+	if got := delayFunc(2, errors.New("failed"), task); got != 10*time.Second {
+		t.Fatalf("expected default constant delay (10s), got %s", got)
+	}
+}
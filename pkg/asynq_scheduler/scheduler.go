@@ -0,0 +1,297 @@
+package schedulerq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	chainq "taskgo/pkg/asynq_chain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+)
+
+/*
+|------------------------------------------
+|  Recurring Tasks Scheduler
+|  (asynq scheduler + redis leader lock)
+|------------------------------------------
+|	1- Schedules are defined in Go (bootstrap/registers.go) as Entry list
+|	2- Every worker process runs the Scheduler but only the leader (the one holding the redis lock)
+|	   runs the asynq scheduler so the recurring tasks are enqueued only one time per tick
+|	3- The leader renews the lock every (lock ttl / 3), if it loses the lock it stops its asynq scheduler
+|	   and another worker will take the leadership when the lock expires
+|------------------------------------------
+|	Example:
+|------------------------------------------
+|	schedulerq.Entry{
+|		Name:     "nightly sales report",
+|		Cronspec: "0 2 * * *",
+|		Task:     tasks.NewNightlyReportTask("xlsx"),
+|	}
+|------------------------------------------
+*/
+
+// Entry is a recurring task definition
+type Entry struct {
+	Name     string         // Human readable name
+	Cronspec string         // Cron spec (e.g. "0 2 * * *", "@every 1h")
+	Task     chainq.Task    // Task to enqueue on every tick
+	Opts     []asynq.Option // Extra enqueue options
+}
+
+// Options of the scheduler
+type Options struct {
+	Location     *time.Location // Time zone of the cron specs (default UTC)
+	LockKey      string         // Redis key of the leader lock
+	LockTTL      time.Duration  // Leader lock ttl
+	PollInterval time.Duration  // How often a follower tries to take the leadership
+}
+
+type Scheduler struct {
+	redisOpt asynq.RedisConnOpt
+	redis    *redis.Client
+	opts     Options
+	entries  []Entry
+	logger   chainq.Logger
+	id       string
+
+	mu       sync.Mutex
+	running  *asynq.Scheduler
+	isLeader bool
+}
+
+// New creates a new leader safe scheduler
+func New(redisOpt asynq.RedisConnOpt, lockClient *redis.Client, logger chainq.Logger, opts Options) *Scheduler {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.LockKey == "" {
+		opts.LockKey = "schedulerq:leader"
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 30 * time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = opts.LockTTL / 3
+	}
+
+	host, _ := os.Hostname()
+
+	return &Scheduler{
+		redisOpt: redisOpt,
+		redis:    lockClient,
+		opts:     opts,
+		logger:   logger,
+		id:       fmt.Sprintf("%s:%d:%s", host, os.Getpid(), uuid.New().String()),
+	}
+}
+
+// Register adds the entries to the scheduler (must be called before Run)
+func (s *Scheduler) Register(entries ...Entry) error {
+	for _, entry := range entries {
+		if _, err := ParseCronspec(entry.Cronspec); err != nil {
+			return fmt.Errorf("invalid cronspec %q for %s: %w", entry.Cronspec, entry.Task.GetTaskType(), err)
+		}
+	}
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+// Run runs the leader election loop until the context is cancelled [BLOCKING]
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.stop()
+			s.releaseLock()
+			s.mu.Lock()
+			s.isLeader = false
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader returns true if this scheduler is the one enqueuing the recurring tasks
+func (s *Scheduler) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isLeader
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	leader, err := s.acquireOrRenewLock(ctx)
+	if err != nil {
+		s.logger.Error("scheduler: failed to acquire leader lock", "error", err.Error())
+		leader = false
+	}
+
+	s.mu.Lock()
+	wasLeader := s.isLeader
+	s.isLeader = leader
+	s.mu.Unlock()
+
+	switch {
+	case leader && !wasLeader:
+		if err := s.start(); err != nil {
+			s.logger.Error("scheduler: failed to start", "error", err.Error())
+			s.releaseLock()
+			s.mu.Lock()
+			s.isLeader = false
+			s.mu.Unlock()
+			return
+		}
+		s.logger.Info("scheduler: became leader, recurring tasks scheduler started", "scheduler_id", s.id, "entries", len(s.entries))
+	case !leader && wasLeader:
+		s.stop()
+		s.logger.Warn("scheduler: lost leadership, recurring tasks scheduler stopped", "scheduler_id", s.id)
+	}
+}
+
+// start creates and starts a new asynq scheduler (asynq scheduler can't be restarted after shutdown)
+func (s *Scheduler) start() error {
+	scheduler := asynq.NewScheduler(s.redisOpt, &asynq.SchedulerOpts{
+		Location: s.opts.Location,
+		PostEnqueueFunc: func(info *asynq.TaskInfo, err error) {
+			if err != nil {
+				s.logger.Error("scheduler: failed to enqueue recurring task", "error", err.Error())
+				return
+			}
+			s.logger.Info(fmt.Sprintf("scheduler: recurring task enqueued: ID=%s, Queue=%s, Type=%s", info.ID, info.Queue, info.Type))
+		},
+	})
+
+	for _, entry := range s.entries {
+		task, err := entry.Task.CreateTask()
+		if err != nil {
+			return fmt.Errorf("failed to create scheduled task %s: %w", entry.Task.GetTaskType(), err)
+		}
+
+		if _, err := scheduler.Register(entry.Cronspec, task, entry.Opts...); err != nil {
+			return fmt.Errorf("failed to register scheduled task %s: %w", entry.Task.GetTaskType(), err)
+		}
+	}
+
+	if err := scheduler.Start(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.running = scheduler
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running != nil {
+		s.running.Shutdown()
+		s.running = nil
+	}
+}
+
+// Lua script: renew the lock only if it's owned by this scheduler
+var renewLockScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// Lua script: release the lock only if it's owned by this scheduler
+var releaseLockScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+	return 0
+`)
+
+func (s *Scheduler) acquireOrRenewLock(ctx context.Context) (bool, error) {
+	acquired, err := s.redis.SetNX(ctx, s.opts.LockKey, s.id, s.opts.LockTTL).Result()
+	if err != nil {
+		return false, err
+	}
+	if acquired {
+		return true, nil
+	}
+
+	renewed, err := renewLockScript.Run(ctx, s.redis, []string{s.opts.LockKey}, s.id, s.opts.LockTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+func (s *Scheduler) releaseLock() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := releaseLockScript.Run(ctx, s.redis, []string{s.opts.LockKey}, s.id).Err(); err != nil {
+		s.logger.Warn("scheduler: failed to release leader lock", "error", err.Error())
+	}
+}
+
+/*
+|------------------------------------------
+|  Upcoming runs helpers (CLI / admin view)
+|------------------------------------------
+*/
+
+// UpcomingRun is the next run time of a scheduled entry
+type UpcomingRun struct {
+	Name     string    `json:"name"`
+	TaskType string    `json:"task_type"`
+	Cronspec string    `json:"cronspec"`
+	NextRun  time.Time `json:"next_run"`
+}
+
+// ParseCronspec parses the cron spec the same way asynq scheduler does (standard cron spec + descriptors)
+func ParseCronspec(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(spec)
+}
+
+// UpcomingRuns returns the next `count` runs of every entry after `from`, sorted by run time
+func UpcomingRuns(entries []Entry, from time.Time, count int, loc *time.Location) ([]UpcomingRun, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if count <= 0 {
+		count = 1
+	}
+
+	var runs []UpcomingRun
+	for _, entry := range entries {
+		schedule, err := ParseCronspec(entry.Cronspec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cronspec %q for %s: %w", entry.Cronspec, entry.Task.GetTaskType(), err)
+		}
+
+		next := from.In(loc)
+		for i := 0; i < count; i++ {
+			next = schedule.Next(next)
+			runs = append(runs, UpcomingRun{
+				Name:     entry.Name,
+				TaskType: entry.Task.GetTaskType(),
+				Cronspec: entry.Cronspec,
+				NextRun:  next,
+			})
+		}
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].NextRun.Before(runs[j].NextRun)
+	})
+
+	return runs, nil
+}
@@ -0,0 +1,136 @@
+package schedulerq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	schedulerq "taskgo/pkg/asynq_scheduler"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...any)  {}
+func (nopLogger) Error(msg string, fields ...any) {}
+func (nopLogger) Warn(msg string, fields ...any)  {}
+
+type testTask struct{ taskType string }
+
+func (t testTask) CreateTask() (*asynq.Task, error) { return asynq.NewTask(t.taskType, nil), nil }
+func (t testTask) GetTaskType() string              { return t.taskType }
+func (t testTask) GetPayload() any                  { return nil }
+
+func newScheduler(t *testing.T, server *miniredis.Miniredis) *schedulerq.Scheduler {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return schedulerq.New(asynq.RedisClientOpt{Addr: server.Addr()}, client, nopLogger{}, schedulerq.Options{
+		LockKey:      "schedulerq:test:leader",
+		LockTTL:      time.Second,
+		PollInterval: 20 * time.Millisecond,
+	})
+}
+
+func run(s *schedulerq.Scheduler) (cancel func()) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancelCtx()
+		<-done
+	}
+}
+
+func TestScheduler_OnlyOneLeader(t *testing.T) {
+	server := miniredis.RunT(t)
+	first := newScheduler(t, server)
+	second := newScheduler(t, server)
+
+	stopFirst := run(first)
+	require.Eventually(t, first.IsLeader, 2*time.Second, 10*time.Millisecond)
+
+	stopSecond := run(second)
+	defer stopSecond()
+
+	// The leader keeps renewing the lock so the follower never takes it
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// The leader releases the lock on shutdown and the follower takes the leadership
+	stopFirst()
+	require.Eventually(t, second.IsLeader, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, first.IsLeader())
+}
+
+func TestScheduler_StepsDownWhenLockIsLost(t *testing.T) {
+	server := miniredis.RunT(t)
+	scheduler := newScheduler(t, server)
+
+	stop := run(scheduler)
+	defer stop()
+	require.Eventually(t, scheduler.IsLeader, 2*time.Second, 10*time.Millisecond)
+
+	// Another process owns the lock now (e.g. this one was paused longer than the lock ttl)
+	require.NoError(t, server.Set("schedulerq:test:leader", "other-scheduler"))
+	require.Eventually(t, func() bool { return !scheduler.IsLeader() }, 2*time.Second, 10*time.Millisecond)
+
+	value, _ := server.Get("schedulerq:test:leader")
+	assert.Equal(t, "other-scheduler", value)
+}
+
+func TestScheduler_RegisterRejectsInvalidCronspec(t *testing.T) {
+	scheduler := newScheduler(t, miniredis.RunT(t))
+
+	err := scheduler.Register(schedulerq.Entry{Name: "broken", Cronspec: "every night", Task: testTask{"test:broken"}})
+	assert.Error(t, err)
+	assert.NoError(t, scheduler.Register(schedulerq.Entry{Name: "nightly", Cronspec: "0 2 * * *", Task: testTask{"test:nightly"}}))
+}
+
+func TestUpcomingRuns(t *testing.T) {
+	entries := []schedulerq.Entry{
+		{Name: "nightly", Cronspec: "0 2 * * *", Task: testTask{"test:nightly"}},
+		{Name: "hourly", Cronspec: "@every 1h", Task: testTask{"test:hourly"}},
+	}
+	from := time.Date(2026, 1, 10, 0, 30, 0, 0, time.UTC)
+
+	runs, err := schedulerq.UpcomingRuns(entries, from, 2, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, runs, 4)
+
+	// Sorted by the run time across the entries
+	assert.Equal(t, "hourly", runs[0].Name)
+	assert.Equal(t, from.Add(time.Hour), runs[0].NextRun)
+	assert.Equal(t, "nightly", runs[1].Name)
+	assert.Equal(t, "test:nightly", runs[1].TaskType)
+	assert.Equal(t, time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC), runs[1].NextRun)
+	assert.Equal(t, "hourly", runs[2].Name)
+	assert.Equal(t, from.Add(2*time.Hour), runs[2].NextRun)
+	assert.Equal(t, "nightly", runs[3].Name)
+	assert.Equal(t, time.Date(2026, 1, 11, 2, 0, 0, 0, time.UTC), runs[3].NextRun)
+}
+
+func TestUpcomingRuns_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	entries := []schedulerq.Entry{{Name: "nightly", Cronspec: "0 2 * * *", Task: testTask{"test:nightly"}}}
+
+	// 2026-01-10 00:30 UTC is 03:30 in UTC+3 so the next 02:00 is the next day
+	runs, err := schedulerq.UpcomingRuns(entries, time.Date(2026, 1, 10, 0, 30, 0, 0, time.UTC), 0, loc)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.True(t, runs[0].NextRun.Equal(time.Date(2026, 1, 11, 2, 0, 0, 0, loc)))
+}
+
+func TestUpcomingRuns_InvalidCronspec(t *testing.T) {
+	_, err := schedulerq.UpcomingRuns([]schedulerq.Entry{{Name: "broken", Cronspec: "61 * * * *", Task: testTask{"test:broken"}}}, time.Now(), 1, nil)
+	assert.Error(t, err)
+}
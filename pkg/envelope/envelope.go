@@ -0,0 +1,195 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+/*
+|------------------------------------------
+|  Versioned Task Payload Envelope
+|------------------------------------------
+|	Task payloads are wrapped inside an envelope holding the payload schema version:
+|		{"_v": 2, "_data": {...task payload...}}
+|
+|	When the payload struct of a task type changes:
+|	1- Bump the task type schema version
+|	2- Register an upcaster that converts the payload from the previous version to the new one
+|
+|	Unwrap migrates the old payloads (in-flight during deploys) to the current version step by step (v1 -> v2 -> v3)
+|	Payloads without an envelope (enqueued before versioning) are treated as version 1
+|------------------------------------------
+|	Example:
+|------------------------------------------
+|	envelope.Register("inventory:check", envelope.Schema{
+|		Version: 2,
+|		Upcasters: map[int]envelope.Upcaster{
+|			// v1 {"order_id": 1} -> v2 {"order_ids": [1]}
+|			1: func(data json.RawMessage) (json.RawMessage, error) {...},
+|		},
+|	})
+|------------------------------------------
+*/
+
+var (
+	// ErrUnknownVersion is returned when the payload version is newer than the known version (e.g. old worker during deploy)
+	ErrUnknownVersion = errors.New("unknown payload version")
+
+	// ErrMissingUpcaster is returned when there is no upcaster to migrate the payload to the next version
+	ErrMissingUpcaster = errors.New("missing payload upcaster")
+)
+
+// IsRetryable reports if the Unwrap error can go away on a retry: an unknown (newer) version is read
+// by the workers running the new code, the malformed payloads fail again on every retry
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrUnknownVersion)
+}
+
+// Upcaster migrates the payload data from its version to the next version
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// Schema of a task type payload
+type Schema struct {
+	Version   int              // Current payload version (default 1)
+	Upcasters map[int]Upcaster // Upcasters keyed by the version they migrate from
+}
+
+// Envelope wraps the task payload with its version
+type Envelope struct {
+	Version int             `json:"_v"`
+	Data    json.RawMessage `json:"_data"`
+}
+
+// Registry holds the payload schemas of the task types
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]Schema
+}
+
+// NewRegistry creates a new payload schemas registry
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas: make(map[string]Schema),
+	}
+}
+
+// Register sets the payload schema of the task type
+func (r *Registry) Register(taskType string, schema Schema) {
+	if schema.Version <= 0 {
+		schema.Version = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[taskType] = schema
+}
+
+// Version returns the current payload version of the task type (1 if the task type is not registered)
+func (r *Registry) Version(taskType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if schema, ok := r.schemas[taskType]; ok {
+		return schema.Version
+	}
+	return 1
+}
+
+// Wrap marshals the payload inside an envelope with the current version of the task type
+func (r *Registry) Wrap(taskType string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	return json.Marshal(Envelope{
+		Version: r.Version(taskType),
+		Data:    data,
+	})
+}
+
+// Unwrap returns the payload data migrated to the current version of the task type
+func (r *Registry) Unwrap(taskType string, raw []byte) (json.RawMessage, error) {
+	env, err := Open(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	schema, ok := r.schemas[taskType]
+	r.mu.RUnlock()
+	if !ok {
+		schema = Schema{Version: 1}
+	}
+
+	if env.Version > schema.Version {
+		return nil, fmt.Errorf("%w: %s payload version %d, latest known version %d", ErrUnknownVersion, taskType, env.Version, schema.Version)
+	}
+
+	data := env.Data
+	for version := env.Version; version < schema.Version; version++ {
+		upcaster, ok := schema.Upcasters[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s payload from version %d to %d", ErrMissingUpcaster, taskType, version, version+1)
+		}
+
+		data, err = upcaster(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast %s payload from version %d to %d: %w", taskType, version, version+1, err)
+		}
+	}
+
+	return data, nil
+}
+
+// Open returns the envelope of the raw payload, the payloads without envelope are returned as version 1
+func Open(raw []byte) (*Envelope, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return &Envelope{Version: 1, Data: raw}, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	rawVersion, hasVersion := fields["_v"]
+	data, hasData := fields["_data"]
+	if !hasVersion || !hasData {
+		return &Envelope{Version: 1, Data: raw}, nil // Legacy payload (no envelope)
+	}
+
+	var version int
+	if err := json.Unmarshal(rawVersion, &version); err != nil || version <= 0 {
+		return nil, fmt.Errorf("invalid payload version: %s", string(rawVersion))
+	}
+
+	return &Envelope{Version: version, Data: data}, nil
+}
+
+/*
+|------------------------------------------
+|  Default registry (shared by the producers and the workers)
+|------------------------------------------
+*/
+
+var defaultRegistry = NewRegistry()
+
+// Register sets the payload schema of the task type in the default registry
+func Register(taskType string, schema Schema) {
+	defaultRegistry.Register(taskType, schema)
+}
+
+// Wrap wraps the payload using the default registry
+func Wrap(taskType string, payload any) ([]byte, error) {
+	return defaultRegistry.Wrap(taskType, payload)
+}
+
+// Unwrap unwraps and migrates the payload using the default registry
+func Unwrap(taskType string, raw []byte) (json.RawMessage, error) {
+	return defaultRegistry.Unwrap(taskType, raw)
+}
@@ -0,0 +1,152 @@
+package envelope_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"taskgo/pkg/envelope"
+)
+
+type payloadV1 struct {
+	OrderID uint `json:"order_id"`
+}
+
+type payloadV2 struct {
+	OrderIDs []uint `json:"order_ids"`
+}
+
+func newRegistry() *envelope.Registry {
+	r := envelope.NewRegistry()
+	r.Register("order:check", envelope.Schema{
+		Version: 2,
+		Upcasters: map[int]envelope.Upcaster{
+			1: func(data json.RawMessage) (json.RawMessage, error) {
+				var v1 payloadV1
+				if err := json.Unmarshal(data, &v1); err != nil {
+					return nil, err
+				}
+				return json.Marshal(payloadV2{OrderIDs: []uint{v1.OrderID}})
+			},
+		},
+	})
+	return r
+}
+
+func TestWrapAndUnwrapCurrentVersion(t *testing.T) {
+	r := newRegistry()
+
+	raw, err := r.Wrap("order:check", payloadV2{OrderIDs: []uint{1, 2}})
+	if err != nil {
+		t.Fatalf("failed to wrap payload: %v", err)
+	}
+
+	env, err := envelope.Open(raw)
+	if err != nil {
+		t.Fatalf("failed to open envelope: %v", err)
+	}
+	if env.Version != 2 {
+		t.Fatalf("expected version 2, got %d", env.Version)
+	}
+
+	data, err := r.Unwrap("order:check", raw)
+	if err != nil {
+		t.Fatalf("failed to unwrap payload: %v", err)
+	}
+
+	var payload payloadV2
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	if len(payload.OrderIDs) != 2 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestUnwrapUpcastsOldVersion(t *testing.T) {
+	r := newRegistry()
+
+	raw, _ := json.Marshal(map[string]any{"_v": 1, "_data": payloadV1{OrderID: 7}})
+
+	data, err := r.Unwrap("order:check", raw)
+	if err != nil {
+		t.Fatalf("failed to unwrap payload: %v", err)
+	}
+
+	var payload payloadV2
+	_ = json.Unmarshal(data, &payload)
+	if len(payload.OrderIDs) != 1 || payload.OrderIDs[0] != 7 {
+		t.Fatalf("expected upcasted payload, got %+v", payload)
+	}
+}
+
+func TestUnwrapLegacyPayloadAsVersionOne(t *testing.T) {
+	r := newRegistry()
+
+	raw, _ := json.Marshal(payloadV1{OrderID: 3})
+
+	data, err := r.Unwrap("order:check", raw)
+	if err != nil {
+		t.Fatalf("failed to unwrap legacy payload: %v", err)
+	}
+
+	var payload payloadV2
+	_ = json.Unmarshal(data, &payload)
+	if len(payload.OrderIDs) != 1 || payload.OrderIDs[0] != 3 {
+		t.Fatalf("expected upcasted legacy payload, got %+v", payload)
+	}
+}
+
+func TestUnwrapUnknownVersion(t *testing.T) {
+	r := newRegistry()
+
+	raw, _ := json.Marshal(map[string]any{"_v": 3, "_data": map[string]any{}})
+
+	if _, err := r.Unwrap("order:check", raw); !errors.Is(err, envelope.ErrUnknownVersion) {
+		t.Fatalf("expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestUnwrapMissingUpcaster(t *testing.T) {
+	r := envelope.NewRegistry()
+	r.Register("order:check", envelope.Schema{Version: 3})
+
+	raw, _ := json.Marshal(map[string]any{"_v": 1, "_data": map[string]any{}})
+
+	if _, err := r.Unwrap("order:check", raw); !errors.Is(err, envelope.ErrMissingUpcaster) {
+		t.Fatalf("expected ErrMissingUpcaster, got %v", err)
+	}
+}
+
+func TestUnwrapUnregisteredTaskType(t *testing.T) {
+	r := envelope.NewRegistry()
+
+	raw, err := r.Wrap("unknown:type", payloadV1{OrderID: 1})
+	if err != nil {
+		t.Fatalf("failed to wrap payload: %v", err)
+	}
+
+	data, err := r.Unwrap("unknown:type", raw)
+	if err != nil {
+		t.Fatalf("failed to unwrap payload: %v", err)
+	}
+
+	var payload payloadV1
+	_ = json.Unmarshal(data, &payload)
+	if payload.OrderID != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	r := newRegistry()
+
+	newer, _ := json.Marshal(map[string]any{"_v": 3, "_data": map[string]any{}})
+	if _, err := r.Unwrap("order:check", newer); !envelope.IsRetryable(err) {
+		t.Fatalf("expected the unknown version to be retryable, got %v", err)
+	}
+
+	if _, err := r.Unwrap("order:check", []byte("not json")); err == nil || envelope.IsRetryable(err) {
+		t.Fatalf("expected the malformed payload not to be retryable, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+|------------------------------------------
+|  Domain Events
+|------------------------------------------
+|	Events are published through a Publisher so the broker can be swapped without touching the publishers:
+|	- MemoryBus:             in process subscribers (dev, tests)
+|	- RedisStreamPublisher:  redis streams, consumed by other services with consumer groups
+|	- ProducerPublisher:     adapter for any broker client (kafka, amqp, ...) satisfying the Producer interface
+|------------------------------------------
+*/
+
+// Event is a domain event
+type Event struct {
+	ID         string            `json:"id"`   // unique event id (consumers use it to deduplicate)
+	Type       string            `json:"type"` // e.g. order.placed
+	Key        string            `json:"key"`  // aggregate id (e.g. the order id), used as the partition / routing key
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       json.RawMessage   `json:"data"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Publisher publishes domain events to a broker
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+// Handler handles a consumed event
+type Handler func(ctx context.Context, event Event) error
+
+// PublisherFunc is a function implementing the Publisher interface
+type PublisherFunc func(ctx context.Context, events ...Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, events ...Event) error {
+	return f(ctx, events...)
+}
+
+// New creates a new event with a random id
+func New(eventType, key string, data any) (Event, error) {
+	return NewWithID(uuid.NewString(), eventType, key, data)
+}
+
+// NewWithID creates a new event with a known id (e.g. the same event published again on a task retry)
+func NewWithID(id, eventType, key string, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s event data: %w", eventType, err)
+	}
+
+	return Event{
+		ID:         id,
+		Type:       eventType,
+		Key:        key,
+		OccurredAt: time.Now().UTC(),
+		Data:       raw,
+	}, nil
+}
+
+// Decode decodes the event data into v
+func (e Event) Decode(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode %s event data: %w", e.Type, err)
+	}
+	return nil
+}
+
+// WithMetadata returns a copy of the event with the metadata value (e.g. trace id)
+func (e Event) WithMetadata(key, value string) Event {
+	metadata := make(map[string]string, len(e.Metadata)+1)
+	for k, v := range e.Metadata {
+		metadata[k] = v
+	}
+	metadata[key] = value
+	e.Metadata = metadata
+	return e
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MemoryBus delivers the events to the in process subscribers synchronously
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler // event type ("*" = all events) -> handlers
+}
+
+// NewMemoryBus creates a new in memory bus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers the handler on the event types (all the events if no type is given)
+func (b *MemoryBus) Subscribe(handler Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(eventTypes) == 0 {
+		eventTypes = []string{"*"}
+	}
+	for _, eventType := range eventTypes {
+		b.handlers[eventType] = append(b.handlers[eventType], handler)
+	}
+}
+
+// Publish delivers the events to their subscribers, the handlers errors are joined
+func (b *MemoryBus) Publish(ctx context.Context, events ...Event) error {
+	var errs []error
+
+	for _, event := range events {
+		b.mu.RLock()
+		handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers["*"]...)
+		b.mu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := handler(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Producer is the shape of a broker client producing messages (e.g. a thin wrapper of a kafka writer or an amqp channel)
+type Producer interface {
+	Produce(ctx context.Context, topic string, key, value []byte, headers map[string]string) error
+}
+
+// ProducerPublisher publishes the events with a broker producer
+type ProducerPublisher struct {
+	producer Producer
+	topic    func(event Event) string
+}
+
+// NewProducerPublisher creates a new producer publisher,
+// topic returns the topic / exchange of the event (all the events are produced to defaultTopic if it's nil)
+func NewProducerPublisher(producer Producer, defaultTopic string, topic func(event Event) string) *ProducerPublisher {
+	if topic == nil {
+		topic = func(Event) string { return defaultTopic }
+	}
+
+	return &ProducerPublisher{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Publish produces the events keyed by their aggregate key (keeps the order of the events of the same aggregate)
+func (p *ProducerPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s event: %w", event.Type, err)
+		}
+
+		headers := map[string]string{"event_id": event.ID, "event_type": event.Type}
+		for k, v := range event.Metadata {
+			headers[k] = v
+		}
+
+		if err := p.producer.Produce(ctx, p.topic(event), []byte(event.Key), value, headers); err != nil {
+			return fmt.Errorf("failed to produce %s event: %w", event.Type, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Redis Streams
+|------------------------------------------
+|	All the events are appended to one stream (capped to max len), each consumer group gets every event
+|	and its consumers share the work. An event is acknowledged only when its handler succeeds,
+|	the unacknowledged events are claimed again after the claim idle time (at-least-once delivery).
+|	The events that can't be decoded or failed max deliveries times are moved to the dead letter stream
+|	(<stream>:dead by default) with their error and acknowledged so they aren't claimed forever.
+|------------------------------------------
+*/
+
+// RedisStreamPublisher appends the events to a redis stream
+type RedisStreamPublisher struct {
+	redis  *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamPublisher creates a new redis stream publisher (maxLen = 0 keeps all the events)
+func NewRedisStreamPublisher(client *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		redis:  client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Publish appends the events to the stream
+func (p *RedisStreamPublisher) Publish(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	pipe := p.redis.Pipeline()
+	for _, event := range events {
+		values, err := streamValues(event)
+		if err != nil {
+			return err
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: p.stream,
+			MaxLen: p.maxLen,
+			Approx: p.maxLen > 0,
+			Values: values,
+		})
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to publish events to stream %s: %w", p.stream, err)
+	}
+	return nil
+}
+
+// RedisStreamConsumer consumes the events of a redis stream as a member of a consumer group
+type RedisStreamConsumer struct {
+	redis     *redis.Client
+	stream    string
+	group     string
+	consumer  string
+	batch     int64
+	block     time.Duration
+	claimIdle time.Duration
+
+	maxDeliveries    int64 // 0 = the failed events are claimed again until their handler succeeds
+	deadLetterStream string
+}
+
+// NewRedisStreamConsumer creates a new consumer of the group (the group is created if it doesn't exist)
+func NewRedisStreamConsumer(client *redis.Client, stream, group, consumer string) *RedisStreamConsumer {
+	return &RedisStreamConsumer{
+		redis:     client,
+		stream:    stream,
+		group:     group,
+		consumer:  consumer,
+		batch:     50,
+		block:     5 * time.Second,
+		claimIdle: time.Minute,
+
+		maxDeliveries:    10,
+		deadLetterStream: stream + ":dead",
+	}
+}
+
+// WithBlock sets how long a read waits for new events
+func (c *RedisStreamConsumer) WithBlock(block time.Duration) *RedisStreamConsumer {
+	c.block = block
+	return c
+}
+
+// WithClaimIdle sets how long an event is left unacknowledged before it's claimed again
+func (c *RedisStreamConsumer) WithClaimIdle(idle time.Duration) *RedisStreamConsumer {
+	c.claimIdle = idle
+	return c
+}
+
+// WithMaxDeliveries sets how many times a failing event is delivered before it's dead-lettered (0 = no limit)
+func (c *RedisStreamConsumer) WithMaxDeliveries(max int64) *RedisStreamConsumer {
+	c.maxDeliveries = max
+	return c
+}
+
+// WithDeadLetterStream sets the stream the dead-lettered events are moved to
+func (c *RedisStreamConsumer) WithDeadLetterStream(stream string) *RedisStreamConsumer {
+	c.deadLetterStream = stream
+	return c
+}
+
+// Consume reads the events of the group and calls the handler until the context is cancelled
+func (c *RedisStreamConsumer) Consume(ctx context.Context, handler Handler) error {
+	err := c.redis.XGroupCreateMkStream(ctx, c.stream, c.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s: %w", c.group, err)
+	}
+
+	for ctx.Err() == nil {
+		// Claim the events left unacknowledged by a crashed consumer
+		claimed, _, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.stream,
+			Group:    c.group,
+			Consumer: c.consumer,
+			MinIdle:  c.claimIdle,
+			Start:    "0",
+			Count:    c.batch,
+		}).Result()
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to claim pending events: %w", err)
+		}
+		c.handle(ctx, claimed, handler)
+
+		streams, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.consumer,
+			Streams:  []string{c.stream, ">"},
+			Count:    c.batch,
+			Block:    c.block,
+		}).Result()
+		if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+
+		for _, stream := range streams {
+			c.handle(ctx, stream.Messages, handler)
+		}
+	}
+
+	return nil
+}
+
+// handle calls the handler for each message and acknowledges the handled ones
+func (c *RedisStreamConsumer) handle(ctx context.Context, messages []redis.XMessage, handler Handler) {
+	for _, message := range messages {
+		event, err := eventFromStream(message.Values)
+		if err != nil {
+			// It will never be decoded, no need to claim it again
+			c.deadLetter(ctx, message, err)
+			continue
+		}
+
+		if err := handler(ctx, event); err != nil {
+			if c.maxDeliveries > 0 && c.deliveries(ctx, message.ID) >= c.maxDeliveries {
+				c.deadLetter(ctx, message, err)
+			}
+			continue // claimed again after the claim idle time
+		}
+
+		c.redis.XAck(ctx, c.stream, c.group, message.ID)
+	}
+}
+
+// deliveries returns how many times the pending message was delivered to the group consumers
+func (c *RedisStreamConsumer) deliveries(ctx context.Context, id string) int64 {
+	pending, err := c.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.stream,
+		Group:  c.group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 0
+	}
+	return pending[0].RetryCount
+}
+
+// deadLetter moves the message to the dead letter stream with its error and acknowledges it
+func (c *RedisStreamConsumer) deadLetter(ctx context.Context, message redis.XMessage, reason error) {
+	values := make(map[string]any, len(message.Values)+3)
+	maps.Copy(values, message.Values)
+	values["dead_letter_error"] = reason.Error()
+	values["dead_letter_group"] = c.group
+	values["dead_letter_id"] = message.ID
+
+	pipe := c.redis.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{Stream: c.deadLetterStream, Values: values})
+	pipe.XAck(ctx, c.stream, c.group, message.ID)
+	_, _ = pipe.Exec(ctx) // claimed again if it fails
+}
+
+func streamValues(event Event) (map[string]any, error) {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event metadata: %w", event.Type, err)
+	}
+
+	return map[string]any{
+		"id":          event.ID,
+		"type":        event.Type,
+		"key":         event.Key,
+		"occurred_at": event.OccurredAt.Format(time.RFC3339Nano),
+		"data":        string(event.Data),
+		"metadata":    string(metadata),
+	}, nil
+}
+
+func eventFromStream(values map[string]any) (Event, error) {
+	get := func(key string) string {
+		value, _ := values[key].(string)
+		return value
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339Nano, get("occurred_at"))
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event occurred_at: %w", err)
+	}
+
+	event := Event{
+		ID:         get("id"),
+		Type:       get("type"),
+		Key:        get("key"),
+		OccurredAt: occurredAt,
+		Data:       json.RawMessage(get("data")),
+	}
+
+	if metadata := get("metadata"); metadata != "" && metadata != "null" {
+		if err := json.Unmarshal([]byte(metadata), &event.Metadata); err != nil {
+			return Event{}, fmt.Errorf("invalid event metadata: %w", err)
+		}
+	}
+
+	return event, nil
+}
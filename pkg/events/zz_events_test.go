@@ -0,0 +1,103 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"taskgo/pkg/events"
+)
+
+type orderPlaced struct {
+	OrderID uint `json:"order_id"`
+}
+
+func TestMemoryBusDeliversByType(t *testing.T) {
+	bus := events.NewMemoryBus()
+
+	var placed, all []string
+	bus.Subscribe(func(ctx context.Context, event events.Event) error {
+		placed = append(placed, event.ID)
+		return nil
+	}, "order.placed")
+	bus.Subscribe(func(ctx context.Context, event events.Event) error {
+		all = append(all, event.ID)
+		return nil
+	})
+
+	first, _ := events.NewWithID("1", "order.placed", "order:1", orderPlaced{OrderID: 1})
+	second, _ := events.NewWithID("2", "payment.captured", "order:1", nil)
+
+	if err := bus.Publish(context.Background(), first, second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(placed) != 1 || placed[0] != "1" {
+		t.Fatalf("expected only the order.placed event, got %v", placed)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected all the events, got %v", all)
+	}
+
+	var data orderPlaced
+	if err := first.Decode(&data); err != nil || data.OrderID != 1 {
+		t.Fatalf("expected order id 1, got %d (%v)", data.OrderID, err)
+	}
+}
+
+func TestMemoryBusJoinsHandlerErrors(t *testing.T) {
+	bus := events.NewMemoryBus()
+	errFailed := errors.New("handler failed")
+	bus.Subscribe(func(ctx context.Context, event events.Event) error { return errFailed })
+
+	event, _ := events.New("order.placed", "order:1", nil)
+	if err := bus.Publish(context.Background(), event); !errors.Is(err, errFailed) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+}
+
+type producedMessage struct {
+	topic   string
+	key     string
+	value   []byte
+	headers map[string]string
+}
+
+type fakeProducer struct {
+	messages []producedMessage
+}
+
+func (p *fakeProducer) Produce(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	p.messages = append(p.messages, producedMessage{topic, string(key), value, headers})
+	return nil
+}
+
+func TestProducerPublisherKeysByAggregate(t *testing.T) {
+	producer := &fakeProducer{}
+	publisher := events.NewProducerPublisher(producer, "orders", nil)
+
+	event, _ := events.NewWithID("1", "order.placed", "order:1", orderPlaced{OrderID: 1})
+	event = event.WithMetadata("trace_id", "request-id")
+
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(producer.messages) != 1 {
+		t.Fatalf("expected 1 produced message, got %d", len(producer.messages))
+	}
+
+	message := producer.messages[0]
+	if message.topic != "orders" || message.key != "order:1" {
+		t.Fatalf("expected topic orders and key order:1, got %s %s", message.topic, message.key)
+	}
+	if message.headers["event_type"] != "order.placed" || message.headers["trace_id"] != "request-id" {
+		t.Fatalf("unexpected headers %v", message.headers)
+	}
+
+	var produced events.Event
+	if err := json.Unmarshal(message.value, &produced); err != nil || produced.ID != "1" {
+		t.Fatalf("expected the event as value, got %s (%v)", message.value, err)
+	}
+}
@@ -0,0 +1,135 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskgo/pkg/events"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newStreamRedis(t *testing.T) *redis.Client {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// consumeFor runs the consumer until the timeout
+func consumeFor(t *testing.T, consumer *events.RedisStreamConsumer, timeout time.Duration, handler events.Handler) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := consumer.Consume(ctx, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func pendingCount(t *testing.T, client *redis.Client, stream, group string) int64 {
+	pending, err := client.XPending(context.Background(), stream, group).Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return pending.Count
+}
+
+func TestRedisStreamConsumerAcksTheHandledEvents(t *testing.T) {
+	client := newStreamRedis(t)
+	event, _ := events.NewWithID("1", "order.placed", "order:1", orderPlaced{OrderID: 1})
+	if err := events.NewRedisStreamPublisher(client, "events", 0).Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var handled []string
+	consumer := events.NewRedisStreamConsumer(client, "events", "reports", "worker-1").WithBlock(50 * time.Millisecond)
+	consumeFor(t, consumer, 200*time.Millisecond, func(ctx context.Context, event events.Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+
+	if len(handled) != 1 || handled[0] != "1" {
+		t.Fatalf("expected the event to be handled once, got %v", handled)
+	}
+	if count := pendingCount(t, client, "events", "reports"); count != 0 {
+		t.Fatalf("expected the event to be acknowledged, got %d pending", count)
+	}
+}
+
+func TestRedisStreamConsumerDeadLettersTheUndecodableEvents(t *testing.T) {
+	client := newStreamRedis(t)
+	ctx := context.Background()
+	id, err := client.XAdd(ctx, &redis.XAddArgs{Stream: "events", Values: map[string]any{"id": "1", "occurred_at": "yesterday"}}).Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	called := false
+	consumer := events.NewRedisStreamConsumer(client, "events", "reports", "worker-1").WithBlock(50 * time.Millisecond)
+	consumeFor(t, consumer, 200*time.Millisecond, func(ctx context.Context, event events.Event) error {
+		called = true
+		return nil
+	})
+
+	if called {
+		t.Fatalf("expected the handler not to be called")
+	}
+	if count := pendingCount(t, client, "events", "reports"); count != 0 {
+		t.Fatalf("expected the event to be acknowledged, got %d pending", count)
+	}
+
+	dead, err := client.XRange(ctx, "events:dead", "-", "+").Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dead) != 1 {
+		t.Fatalf("expected one dead-lettered event, got %d", len(dead))
+	}
+	values := dead[0].Values
+	if values["occurred_at"] != "yesterday" || values["dead_letter_id"] != id || values["dead_letter_group"] != "reports" {
+		t.Fatalf("expected the original values with the stream id and group, got %v", values)
+	}
+	if values["dead_letter_error"] == "" {
+		t.Fatalf("expected the decode error, got %v", values)
+	}
+}
+
+func TestRedisStreamConsumerDeadLettersAfterMaxDeliveries(t *testing.T) {
+	client := newStreamRedis(t)
+	ctx := context.Background()
+	event, _ := events.NewWithID("1", "order.placed", "order:1", orderPlaced{OrderID: 1})
+	if err := events.NewRedisStreamPublisher(client, "events", 0).Publish(ctx, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deliveries := 0
+	consumer := events.NewRedisStreamConsumer(client, "events", "reports", "worker-1").
+		WithBlock(50 * time.Millisecond).
+		WithClaimIdle(time.Millisecond).
+		WithMaxDeliveries(3).
+		WithDeadLetterStream("events:failed")
+	handler := func(ctx context.Context, event events.Event) error {
+		deliveries++
+		return errors.New("report store down")
+	}
+
+	// The failed event is claimed again after the claim idle time until max deliveries
+	consumeFor(t, consumer, 300*time.Millisecond, handler)
+
+	if deliveries != 3 {
+		t.Fatalf("expected the event not to be delivered after max deliveries, got %d deliveries", deliveries)
+	}
+	if count := pendingCount(t, client, "events", "reports"); count != 0 {
+		t.Fatalf("expected the event to be acknowledged, got %d pending", count)
+	}
+
+	dead, err := client.XRange(ctx, "events:failed", "-", "+").Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Values["dead_letter_error"] != "report store down" || dead[0].Values["id"] != "1" {
+		t.Fatalf("expected the event to be dead-lettered with its error, got %v", dead)
+	}
+}
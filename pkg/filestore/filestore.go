@@ -0,0 +1,162 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+|------------------------------------------
+|  File store
+|------------------------------------------
+|	Files are written as a stream (e.g. reports exports) and read back by their name (relative path).
+|	A file is only visible once its writer is closed, a failed write never leaves a partial file.
+|------------------------------------------
+*/
+
+var (
+	ErrNotFound    = errors.New("file not found")
+	ErrInvalidName = errors.New("invalid file name")
+)
+
+// Store stores the files by name
+type Store interface {
+	// Create returns a writer of the file, the file is stored when the writer is closed
+	Create(ctx context.Context, name string) (Writer, error)
+
+	// Open returns a reader of the file (ErrNotFound if it doesn't exist)
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// Delete deletes the file (no error if it doesn't exist)
+	Delete(ctx context.Context, name string) error
+}
+
+// Writer writes a file, Close stores it and Abort discards it
+type Writer interface {
+	io.Writer
+	Close() error
+	Abort() error
+	Size() int64
+}
+
+/*
+|------------------------------------------
+|  Local store (files under the root directory)
+|------------------------------------------
+*/
+
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates a new store keeping the files under the root directory
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{root: root}
+}
+
+// Create writes the file to a temporary file renamed on close
+func (s *LocalStore) Create(ctx context.Context, name string) (Writer, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create file directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	return &localWriter{file: tmp, path: path}, nil
+}
+
+// Open opens the file
+func (s *LocalStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete deletes the file
+func (s *LocalStore) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path under the root (names escaping the root are rejected)
+func (s *LocalStore) path(name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
+
+type localWriter struct {
+	file *os.File
+	path string
+	size int64
+	done bool
+}
+
+func (w *localWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *localWriter) Size() int64 {
+	return w.size
+}
+
+func (w *localWriter) Close() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+func (w *localWriter) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	w.file.Close()
+	return os.Remove(w.file.Name())
+}
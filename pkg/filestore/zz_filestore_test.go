@@ -0,0 +1,70 @@
+package filestore_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"taskgo/pkg/filestore"
+)
+
+func TestLocalStoreWritesOnClose(t *testing.T) {
+	ctx := context.Background()
+	store := filestore.NewLocalStore(t.TempDir())
+
+	w, err := store.Create(ctx, "reports/1/sales.csv")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := io.WriteString(w, "a,b\n1,2\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if _, err := store.Open(ctx, "reports/1/sales.csv"); !errors.Is(err, filestore.ErrNotFound) {
+		t.Fatalf("expected the file to be hidden before close, got %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if w.Size() != 8 {
+		t.Fatalf("expected size 8 got %d", w.Size())
+	}
+
+	r, err := store.Open(ctx, "reports/1/sales.csv")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer r.Close()
+
+	data, _ := io.ReadAll(r)
+	if string(data) != "a,b\n1,2\n" {
+		t.Fatalf("unexpected content %q", data)
+	}
+}
+
+func TestLocalStoreAbortDiscardsTheFile(t *testing.T) {
+	ctx := context.Background()
+	store := filestore.NewLocalStore(t.TempDir())
+
+	w, _ := store.Create(ctx, "partial.csv")
+	_, _ = io.WriteString(w, "a,b\n")
+	if err := w.Abort(); err != nil {
+		t.Fatalf("abort: %v", err)
+	}
+
+	if _, err := store.Open(ctx, "partial.csv"); !errors.Is(err, filestore.ErrNotFound) {
+		t.Fatalf("expected not found after abort, got %v", err)
+	}
+}
+
+func TestLocalStoreRejectsNamesOutsideRoot(t *testing.T) {
+	store := filestore.NewLocalStore(t.TempDir())
+
+	for _, name := range []string{"", "../secret", "/etc/passwd", "a/../../b"} {
+		if _, err := store.Create(context.Background(), name); !errors.Is(err, filestore.ErrInvalidName) {
+			t.Fatalf("expected invalid name for %q, got %v", name, err)
+		}
+	}
+}
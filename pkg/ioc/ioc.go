@@ -184,8 +184,13 @@ func (c *Container) ShutdownAll() {
 func getServiceName[T any]() (string, error) {
 	typeForT := reflect.TypeOf((*T)(nil)).Elem()
 
+	// Named interfaces are services too (e.g. a driver picked from the config)
+	if typeForT.Kind() == reflect.Interface && typeForT.PkgPath() != "" && typeForT.Name() != "" {
+		return typeForT.PkgPath() + "." + typeForT.Name(), nil
+	}
+
 	if typeForT.Kind() != reflect.Pointer {
-		return "", fmt.Errorf("Resolve (Make[*T]()): type must be a pointer, got %v", typeForT)
+		return "", fmt.Errorf("Resolve (Make[*T]()): type must be a pointer or a named interface, got %v", typeForT)
 	}
 
 	elem := typeForT.Elem()
@@ -89,6 +89,37 @@ func TestShutdownIsCalled(t *testing.T) {
 	}
 }
 
+type Valuer interface {
+	GetValue() string
+}
+
+func (s *ServiceA) GetValue() string {
+	return s.Value
+}
+
+func TestInterfaceResolution(t *testing.T) {
+	c := ioc.NewContainer()
+
+	err := ioc.Singleton(c, func(c *ioc.Container) (Valuer, error) {
+		return &ServiceA{Value: "interface"}, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register interface singleton: %v", err)
+	}
+
+	c.Bootstrap()
+
+	v1, err := ioc.Resolve[Valuer](c)
+	if err != nil {
+		t.Fatalf("failed to resolve interface: %v", err)
+	}
+	v2, _ := ioc.Resolve[Valuer](c)
+
+	if v1.GetValue() != "interface" || v1 != v2 {
+		t.Fatal("expected the same interface singleton instance")
+	}
+}
+
 func TestResolveNonExistent(t *testing.T) {
 	c := ioc.NewContainer()
 
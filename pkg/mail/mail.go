@@ -0,0 +1,199 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Mail
+|------------------------------------------
+|	The mailer sends the messages through a transport (smtp, file for dev or memory for the tests)
+|	and renders their bodies from templates within a layout.
+|------------------------------------------
+*/
+
+var ErrNoRecipients = errors.New("mail: no recipients")
+
+// Message is an email with a text and/or html body
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Content is the email of a notification, its body is rendered from the template (or Text without template)
+type Content struct {
+	Subject  string         `json:"subject"`
+	Template string         `json:"template,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+	Text     string         `json:"text,omitempty"`
+}
+
+// Transport delivers the messages
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Mailer sends the messages from the default sender
+type Mailer struct {
+	transport Transport
+	from      string
+	templates *Templates
+}
+
+// NewMailer creates a new mailer (templates is optional)
+func NewMailer(transport Transport, from string, templates *Templates) *Mailer {
+	return &Mailer{
+		transport: transport,
+		from:      from,
+		templates: templates,
+	}
+}
+
+// Transport returns the mailer transport (e.g. the memory transport outbox in the tests)
+func (m *Mailer) Transport() Transport {
+	return m.transport
+}
+
+// Send sends the message (from the default sender if it has no sender)
+func (m *Mailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	return m.transport.Send(ctx, msg)
+}
+
+// SendContent renders the content then sends it to the recipients
+func (m *Mailer) SendContent(ctx context.Context, to []string, content *Content) error {
+	msg := &Message{To: to, Subject: content.Subject, Text: content.Text}
+
+	if content.Template != "" {
+		if m.templates == nil {
+			return fmt.Errorf("mail: no templates to render %s", content.Template)
+		}
+
+		html, text, err := m.templates.Render(content.Template, content.Data)
+		if err != nil {
+			return err
+		}
+		msg.HTML, msg.Text = html, text
+	}
+
+	return m.Send(ctx, msg)
+}
+
+// Bytes formats the message as a MIME email (multipart/alternative if it has both bodies)
+func (msg *Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid sender %q: %w", msg.From, err)
+	}
+
+	to := make([]string, len(msg.To))
+	for i, recipient := range msg.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("mail: invalid recipient %q: %w", recipient, err)
+		}
+		to[i] = address.String()
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", strings.Join(to, ", "))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(from.Address))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	switch {
+	case msg.HTML != "" && msg.Text != "":
+		boundary := randomHex(16)
+		writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		buf.WriteString("\r\n")
+
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain", msg.Text},
+			{"text/html", msg.HTML},
+		} {
+			fmt.Fprintf(&buf, "--%s\r\n", boundary)
+			if err := writeBody(&buf, part.contentType, part.body); err != nil {
+				return nil, err
+			}
+			buf.WriteString("\r\n")
+		}
+		fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	case msg.HTML != "":
+		if err := writeBody(&buf, "text/html", msg.HTML); err != nil {
+			return nil, err
+		}
+
+	default:
+		if err := writeBody(&buf, "text/plain", msg.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Recipients returns the recipients email addresses (without their names)
+func (msg *Message) Recipients() ([]string, error) {
+	addresses := make([]string, len(msg.To))
+	for i, recipient := range msg.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("mail: invalid recipient %q: %w", recipient, err)
+		}
+		addresses[i] = address.Address
+	}
+	return addresses, nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+}
+
+// writeBody writes the part headers and its quoted-printable body
+func writeBody(buf *bytes.Buffer, contentType, body string) error {
+	writeHeader(buf, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(buf, "Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	textTemplate "text/template"
+)
+
+// Templates renders the emails bodies, a template "name" is made of the files:
+//   - name.html.tmpl and/or name.txt.tmpl defining the "content" block (and optionally "title")
+//   - layouts/<layout>.html.tmpl and layouts/<layout>.txt.tmpl rendering the "content" block
+type Templates struct {
+	fsys   fs.FS
+	layout string
+}
+
+// NewTemplates creates the templates of the file system with the layout (e.g. "default")
+func NewTemplates(fsys fs.FS, layout string) *Templates {
+	return &Templates{
+		fsys:   fsys,
+		layout: layout,
+	}
+}
+
+// Render renders the html and text bodies of the template (a missing body is empty, at least one is required)
+func (t *Templates) Render(name string, data any) (html string, text string, err error) {
+	htmlFile, textFile := name+".html.tmpl", name+".txt.tmpl"
+	hasHTML, hasText := t.exists(htmlFile), t.exists(textFile)
+	if !hasHTML && !hasText {
+		return "", "", errors.New("mail: template not found: " + name)
+	}
+
+	if hasHTML {
+		files, entry := t.withLayout(htmlFile, ".html.tmpl")
+		tmpl, err := htmlTemplate.ParseFS(t.fsys, files...)
+		if err != nil {
+			return "", "", err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, entry, data); err != nil {
+			return "", "", err
+		}
+		html = buf.String()
+	}
+
+	if hasText {
+		files, entry := t.withLayout(textFile, ".txt.tmpl")
+		tmpl, err := textTemplate.ParseFS(t.fsys, files...)
+		if err != nil {
+			return "", "", err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, entry, data); err != nil {
+			return "", "", err
+		}
+		text = buf.String()
+	}
+
+	return html, text, nil
+}
+
+// withLayout returns the files to parse and the executed template (the layout or the "content" block without layout)
+func (t *Templates) withLayout(file, ext string) ([]string, string) {
+	layout := path.Join("layouts", t.layout+ext)
+	if t.layout == "" || !t.exists(layout) {
+		return []string{file}, "content"
+	}
+	return []string{layout, file}, path.Base(layout)
+}
+
+func (t *Templates) exists(file string) bool {
+	_, err := fs.Stat(t.fsys, file)
+	return err == nil
+}
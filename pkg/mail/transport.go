@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SMTPTransport sends the messages to an smtp server (STARTTLS is used if the server supports it)
+type SMTPTransport struct {
+	host     string
+	port     int
+	username string
+	password string
+}
+
+func NewSMTPTransport(host string, port int, username, password string) *SMTPTransport {
+	return &SMTPTransport{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+	}
+}
+
+// Send sends the message to the smtp server
+func (t *SMTPTransport) Send(ctx context.Context, msg *Message) error {
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	recipients, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("mail: invalid sender %q: %w", msg.From, err)
+	}
+
+	var auth smtp.Auth
+	if t.username != "" {
+		auth = smtp.PlainAuth("", t.username, t.password, t.host)
+	}
+
+	addr := net.JoinHostPort(t.host, strconv.Itoa(t.port))
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(addr, auth, from.Address, recipients, body) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("mail: smtp send to %s failed: %w", addr, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FileTransport writes the messages as .eml files in the directory (dev)
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{dir: dir}
+}
+
+// Send writes the message to the directory
+func (t *FileTransport) Send(ctx context.Context, msg *Message) error {
+	body, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), randomHex(4))
+	return os.WriteFile(filepath.Join(t.dir, name), body, 0o644)
+}
+
+// MemoryTransport captures the messages in its outbox (tests)
+type MemoryTransport struct {
+	mu     sync.Mutex
+	outbox []Message
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+// Send captures the message
+func (t *MemoryTransport) Send(ctx context.Context, msg *Message) error {
+	if _, err := msg.Bytes(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outbox = append(t.outbox, *msg)
+	return nil
+}
+
+// Outbox returns the captured messages
+func (t *MemoryTransport) Outbox() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Message(nil), t.outbox...)
+}
+
+// Reset empties the outbox
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outbox = nil
+}
@@ -0,0 +1,114 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"taskgo/pkg/mail"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var templatesFS = fstest.MapFS{
+	"layouts/default.html.tmpl": {Data: []byte(`<html><body>{{template "content" .}}</body></html>`)},
+	"layouts/default.txt.tmpl":  {Data: []byte(`{{template "content" .}}\n-- TaskGo`)},
+	"welcome.html.tmpl":         {Data: []byte(`{{define "content"}}<h1>Hello {{.name}}</h1>{{end}}`)},
+	"welcome.txt.tmpl":          {Data: []byte(`{{define "content"}}Hello {{.name}}{{end}}`)},
+	"html_only.html.tmpl":       {Data: []byte(`{{define "content"}}<p>{{.name}}</p>{{end}}`)},
+}
+
+func TestTemplates_RenderWithLayout(t *testing.T) {
+	templates := mail.NewTemplates(templatesFS, "default")
+
+	html, text, err := templates.Render("welcome", map[string]any{"name": "<Sara>"})
+	require.NoError(t, err)
+	assert.Equal(t, "<html><body><h1>Hello &lt;Sara&gt;</h1></body></html>", html)
+	assert.Equal(t, `Hello <Sara>\n-- TaskGo`, text)
+
+	html, text, err = templates.Render("html_only", map[string]any{"name": "Sara"})
+	require.NoError(t, err)
+	assert.Equal(t, "<html><body><p>Sara</p></body></html>", html)
+	assert.Empty(t, text)
+
+	_, _, err = templates.Render("missing", nil)
+	assert.Error(t, err)
+}
+
+func TestTemplates_RenderWithoutLayout(t *testing.T) {
+	templates := mail.NewTemplates(templatesFS, "")
+
+	html, _, err := templates.Render("welcome", map[string]any{"name": "Sara"})
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Hello Sara</h1>", html)
+}
+
+func TestMailer_SendContentCapturedInOutbox(t *testing.T) {
+	transport := mail.NewMemoryTransport()
+	mailer := mail.NewMailer(transport, "TaskGo <no-reply@taskgo.test>", mail.NewTemplates(templatesFS, "default"))
+
+	err := mailer.SendContent(context.Background(), []string{"Sara <sara@example.com>"}, &mail.Content{
+		Subject:  "Welcome",
+		Template: "welcome",
+		Data:     map[string]any{"name": "Sara"},
+	})
+	require.NoError(t, err)
+
+	outbox := transport.Outbox()
+	require.Len(t, outbox, 1)
+	assert.Equal(t, "TaskGo <no-reply@taskgo.test>", outbox[0].From)
+	assert.Equal(t, []string{"Sara <sara@example.com>"}, outbox[0].To)
+	assert.Equal(t, "Welcome", outbox[0].Subject)
+	assert.Contains(t, outbox[0].HTML, "<h1>Hello Sara</h1>")
+	assert.Contains(t, outbox[0].Text, "Hello Sara")
+
+	assert.ErrorIs(t, mailer.Send(context.Background(), &mail.Message{Subject: "nobody"}), mail.ErrNoRecipients)
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := &mail.Message{
+		From:    "TaskGo <no-reply@taskgo.test>",
+		To:      []string{"sara@example.com", "Omar <omar@example.com>"},
+		Subject: "Your order is confirmed ✔",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+	}
+
+	raw, err := msg.Bytes()
+	require.NoError(t, err)
+
+	content := string(raw)
+	assert.Contains(t, content, "From: \"TaskGo\" <no-reply@taskgo.test>\r\n")
+	assert.Contains(t, content, "To: <sara@example.com>, \"Omar\" <omar@example.com>\r\n")
+	assert.Contains(t, content, "Subject: =?utf-8?q?")
+	assert.Contains(t, content, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, content, "Content-Type: text/plain; charset=utf-8")
+	assert.Contains(t, content, "Content-Type: text/html; charset=utf-8")
+
+	recipients, err := msg.Recipients()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sara@example.com", "omar@example.com"}, recipients)
+
+	_, err = (&mail.Message{From: "invalid", To: []string{"sara@example.com"}}).Bytes()
+	assert.Error(t, err)
+}
+
+func TestFileTransport_WritesEmlFiles(t *testing.T) {
+	dir := t.TempDir()
+	transport := mail.NewFileTransport(dir)
+
+	err := transport.Send(context.Background(), &mail.Message{From: "no-reply@taskgo.test", To: []string{"sara@example.com"}, Subject: "Hi", Text: "Hello"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(content), "Subject: Hi"))
+}
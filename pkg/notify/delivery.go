@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hibiken/asynq"
+)
+
+// Attempt is the outcome of a channel delivery attempt
+type Attempt struct {
+	Err   error // nil if delivered
+	Final bool  // no more retries (delivered, skipped or out of retries)
+}
+
+// DeliveryRecorder keeps the delivery state of every (notification, channel)
+type DeliveryRecorder interface {
+	// Queued is called when the channel task is dispatched to the queue
+	Queued(ctx context.Context, task *NotificationTask) error
+	// Attempted is called after every attempt to send the channel task
+	Attempted(ctx context.Context, task *NotificationTask, attempt Attempt) error
+}
+
+// UseDeliveryRecorder records the deliveries with the recorder (nil stops recording)
+func (n *Notify) UseDeliveryRecorder(recorder DeliveryRecorder) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.recorder = recorder
+}
+
+// Redeliver dispatches the channel task again to the queue (e.g. a failed delivery resent by an admin)
+func (n *Notify) Redeliver(task *NotificationTask) error {
+	task.Redelivery++ // a new payload, not deduplicated with the previous one
+	return n.dispatch(task, nil)
+}
+
+func (n *Notify) deliveryRecorder() DeliveryRecorder {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.recorder
+}
+
+// recordQueued records the dispatched task (the recording failures never fail the notification)
+func (n *Notify) recordQueued(ctx context.Context, task *NotificationTask) {
+	recorder := n.deliveryRecorder()
+	if recorder == nil || task.ID == "" {
+		return
+	}
+	if err := recorder.Queued(ctx, task); err != nil {
+		n.logErr("failed to record the queued notification: " + err.Error())
+	}
+}
+
+// recordAttempt records the delivery attempt (the recording failures never fail the notification)
+func (n *Notify) recordAttempt(ctx context.Context, task *NotificationTask, attempt Attempt) {
+	recorder := n.deliveryRecorder()
+	if recorder == nil || task.ID == "" {
+		return
+	}
+	if err := recorder.Attempted(ctx, task, attempt); err != nil {
+		n.logErr("failed to record the notification delivery attempt: " + err.Error())
+	}
+}
+
+// finalAttempt checks if the queued task won't be retried after the error
+func finalAttempt(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, asynq.SkipRetry) {
+		return true
+	}
+
+	retried, ok := asynq.GetRetryCount(ctx)
+	maxRetry, okMax := asynq.GetMaxRetry(ctx)
+	return !ok || !okMax || retried >= maxRetry
+}
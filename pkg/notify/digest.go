@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+/*
+|------------------------------------------
+|  Digests
+|------------------------------------------
+|	A digestible notification is not sent on its own:
+|	1- Its data is added to the open batch of the notifiable (per digest key)
+|	2- The first entry opens the batch for the digest window and schedules the digest notification
+|	   at its end (SendScheduled), the next entries join the batch until it's closed
+|	3- The digest channels tasks read the batch entries and send them as a single summary
+|	   built by the summarizer registered for the digest key
+|------------------------------------------
+*/
+
+const digestDataKey = "digest"
+
+var ErrDigestExpired = errors.New("digest batch expired")
+
+// Digestible notifications are accumulated per notifiable and sent as a single summary after their window (0 = sent on their own)
+type Digestible interface {
+	Notification
+	DigestKey() string
+	DigestWindow() time.Duration
+}
+
+// DigestStore keeps the digests batches
+type DigestStore interface {
+	// Add appends the entry to the open batch of the key (opened for the window if none), returns the batch and if it was opened
+	Add(ctx context.Context, key string, entry map[string]any, window time.Duration) (batch string, opened bool, err error)
+	// Entries returns the batch entries
+	Entries(ctx context.Context, batch string) ([]map[string]any, error)
+}
+
+// DigestSummarizer builds the summary data (with its channel_messages) of the batch entries
+type DigestSummarizer func(entries []map[string]any) map[string]any
+
+// UseDigests accumulates the digestible notifications in the store (nil sends them on their own)
+func (n *Notify) UseDigests(store DigestStore, summarizers map[string]DigestSummarizer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.digests = store
+	n.summarizers = summarizers
+}
+
+// digestNotification is the summary of a digest batch sent at the end of its window
+type digestNotification struct {
+	Digestible
+	batch string
+}
+
+func (d *digestNotification) ShouldQueue() bool {
+	return true
+}
+
+func (d *digestNotification) ScheduledAt() *time.Time {
+	return nil
+}
+
+func (d *digestNotification) Data() map[string]any {
+	return map[string]any{
+		digestDataKey: map[string]any{"key": d.DigestKey(), "batch": d.batch},
+	}
+}
+
+// digestStore returns the digests store if the notification is sent as a digest
+func (n *Notify) digestStore(notification Notification) (DigestStore, Digestible) {
+	digestible, ok := notification.(Digestible)
+	if !ok || digestible.DigestWindow() <= 0 {
+		return nil, nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.digests == nil {
+		return nil, nil
+	}
+	return n.digests, digestible
+}
+
+// addToDigests adds the notification to the notifiables digests, schedules the digests of the opened batches
+func (n *Notify) addToDigests(store DigestStore, notification Digestible, notifiables ...Notifiable) error {
+	ctx := context.Background()
+	window := notification.DigestWindow()
+
+	for _, notifiable := range notifiables {
+		key := fmt.Sprintf("%s:%s:%d", notification.DigestKey(), NotifiableType(notifiable), notifiable.GetNotifiableID())
+
+		batch, opened, err := store.Add(ctx, key, notification.Data(), window)
+		if err != nil {
+			n.logErr(fmt.Sprintf("failed to add the notification to the %s digest: %s", key, err))
+			return err
+		}
+		if !opened {
+			continue
+		}
+
+		digest := &digestNotification{Digestible: notification, batch: batch}
+		if err := n.SendScheduled(time.Now().Add(window), digest, notifiable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandDigest replaces the digest task data with the summary of its batch entries
+func (n *Notify) expandDigest(ctx context.Context, task *NotificationTask) error {
+	marker, ok := task.Data[digestDataKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	key, _ := marker["key"].(string)
+	batch, _ := marker["batch"].(string)
+	if batch == "" {
+		return nil // already expanded (e.g. resent with the sent data)
+	}
+
+	n.mu.Lock()
+	store, summarize := n.digests, n.summarizers[key]
+	n.mu.Unlock()
+
+	if store == nil || summarize == nil {
+		return fmt.Errorf("no digest summarizer registered for %s: %w", key, asynq.SkipRetry)
+	}
+
+	entries, err := store.Entries(ctx, batch)
+	if err != nil {
+		return fmt.Errorf("failed to read the %s digest batch: %w", key, err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: %s %s: %w", ErrDigestExpired, key, batch, asynq.SkipRetry)
+	}
+
+	data := summarize(entries)
+	data[digestDataKey] = map[string]any{"key": key, "count": len(entries)}
+	task.Data = data
+	return nil
+}
+
+// notificationTypeName returns the notification type stored with its tasks (e.g. LowStockNotificationDigest)
+func notificationTypeName(notification Notification) string {
+	if digest, ok := notification.(*digestNotification); ok {
+		return getTypeName(digest.Digestible) + "Digest"
+	}
+	return getTypeName(notification)
+}
+
+// newDigestBatch returns a new digest batch id
+func newDigestBatch() string {
+	return uuid.NewString()
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// The batch is opened for the window, its entries are kept for the retention after it (read by the retried digest tasks).
+// The entry is added to the expected batch (ARGV[1]) only if it's still the open one, a new batch (ARGV[2] = "1")
+// is opened if none is, otherwise the open batch is returned with -1 so the caller retries with it
+var addDigestScript = redis.NewScript(`
+local batch = redis.call("GET", KEYS[1])
+local opened = 0
+if not batch then
+	if ARGV[2] ~= "1" then
+		return {"", -1}
+	end
+	batch = ARGV[1]
+	redis.call("SET", KEYS[1], batch, "PX", ARGV[3])
+	opened = 1
+elseif batch ~= ARGV[1] then
+	return {batch, -1}
+end
+redis.call("RPUSH", KEYS[2], ARGV[4])
+redis.call("PEXPIRE", KEYS[2], ARGV[5])
+return {batch, opened}
+`)
+
+// maxDigestAddAttempts is how many times the entry is added again when the open batch changed while it was added
+const maxDigestAddAttempts = 5
+
+// RedisDigestStore keeps the digests batches in redis
+type RedisDigestStore struct {
+	client    *redis.Client
+	prefix    string
+	retention time.Duration
+}
+
+// NewRedisDigestStore creates a new store, the batches entries are kept for the retention after their window
+func NewRedisDigestStore(client *redis.Client, prefix string, retention time.Duration) *RedisDigestStore {
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	return &RedisDigestStore{client: client, prefix: prefix, retention: retention}
+}
+
+func (s *RedisDigestStore) Add(ctx context.Context, key string, entry map[string]any, window time.Duration) (string, bool, error) {
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return "", false, err
+	}
+
+	// The batch key is declared to the script so the open batch is read first (or a new one is generated)
+	openKey := s.prefix + "open:" + key
+	batch, err := s.client.Get(ctx, openKey).Result()
+	fresh := errors.Is(err, redis.Nil)
+	if fresh {
+		batch = newDigestBatch()
+	} else if err != nil {
+		return "", false, err
+	}
+
+	for range maxDigestAddAttempts {
+		result, err := addDigestScript.Run(ctx, s.client,
+			[]string{openKey, s.batchKey(batch)},
+			batch,
+			boolArg(fresh),
+			window.Milliseconds(),
+			encoded,
+			(window + s.retention).Milliseconds(),
+		).Slice()
+		if err != nil {
+			return "", false, err
+		}
+
+		current, _ := result[0].(string)
+		status, _ := result[1].(int64)
+		if status >= 0 {
+			return current, status == 1, nil
+		}
+
+		// The open batch expired or was replaced since it was read
+		batch, fresh = current, current == ""
+		if fresh {
+			batch = newDigestBatch()
+		}
+	}
+
+	return "", false, fmt.Errorf("failed to add the %s digest entry: the open batch kept changing", key)
+}
+
+func (s *RedisDigestStore) Entries(ctx context.Context, batch string) ([]map[string]any, error) {
+	raw, err := s.client.LRange(ctx, s.batchKey(batch), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]map[string]any, 0, len(raw))
+	for _, item := range raw {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *RedisDigestStore) batchKey(batch string) string {
+	return s.prefix + "batch:" + batch
+}
+
+func boolArg(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Route is a channel the notification is sent on (At delays it, e.g. after the notifiable quiet hours)
+type Route struct {
+	Channel string
+	At      *time.Time
+}
+
+// Router picks the routes of the notification channels for the notifiable (e.g. its preferences),
+// the channels without a route are not sent
+type Router interface {
+	Route(ctx context.Context, notification Notification, notifiable Notifiable, channels []string) ([]Route, error)
+}
+
+// UseRouter routes the notifications through the router (nil sends them on all their channels)
+func (n *Notify) UseRouter(router Router) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.router = router
+}
+
+// routes returns the routes of the notification channels for the notifiable,
+// all the channels are sent if the router fails (a notification is never lost by the preferences lookup)
+func (n *Notify) routes(notification Notification, notifiable Notifiable) []Route {
+	channels := notification.Channels()
+
+	// The digests are routed as their notifications
+	if digest, ok := notification.(*digestNotification); ok {
+		notification = digest.Digestible
+	}
+
+	n.mu.Lock()
+	router := n.router
+	n.mu.Unlock()
+
+	if router != nil {
+		routes, err := router.Route(context.Background(), notification, notifiable, channels)
+		if err == nil {
+			return routes
+		}
+		n.logErr("failed to route the notification, sending it on all its channels: " + err.Error())
+	}
+
+	routes := make([]Route, len(channels))
+	for i, ch := range channels {
+		routes[i] = Route{Channel: ch}
+	}
+	return routes
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 )
 
@@ -42,6 +43,11 @@ type Notify struct {
 	channels  map[string]NotificationChannelHandler
 	mu        sync.Mutex
 	log       Logger
+	router    Router           // optional
+	recorder  DeliveryRecorder // optional
+
+	digests     DigestStore // optional
+	summarizers map[string]DigestSummarizer
 }
 
 func New(log Logger, queueClient *asynq.Client, queueOpts ...asynq.Option) *Notify {
@@ -61,6 +67,10 @@ func (n *Notify) RegisterChannels(channels map[string]NotificationChannelHandler
 
 // Send - dispatches the notification to the queue if it should be queued otherwise sends it immediately
 func (n *Notify) Send(notification Notification, notifiables ...Notifiable) error {
+	if store, digestible := n.digestStore(notification); store != nil {
+		return n.addToDigests(store, digestible, notifiables...)
+	}
+
 	var mode sendType
 	if notification.ShouldQueue() {
 		mode = SendTypeQueue
@@ -84,12 +94,19 @@ func (n *Notify) sendTasks(tasks []*NotificationTask, mode sendType, scheduleAt
 	for _, task := range tasks {
 		var err error
 
+		// The delayed routes are queued even if sent now
+		at := scheduleAt
+		if task.processAt != nil && (at == nil || task.processAt.After(*at)) {
+			at = task.processAt
+		}
+
 		switch {
-		case mode == SendTypeNow:
+		case mode == SendTypeNow && task.processAt == nil:
 			err = n.handleSendNotification(context.Background(), task)
+			n.recordAttempt(context.Background(), task, Attempt{Err: err, Final: true})
 
-		case scheduleAt != nil:
-			err = n.dispatch(task, scheduleAt)
+		case at != nil:
+			err = n.dispatch(task, at)
 
 		default:
 			err = n.dispatch(task, nil)
@@ -106,13 +123,16 @@ func (n *Notify) sendTasks(tasks []*NotificationTask, mode sendType, scheduleAt
 func (n *Notify) buildTasks(notification Notification, notifiables ...Notifiable) []*NotificationTask {
 	var tasks []*NotificationTask
 	for _, notifiable := range notifiables {
-		for _, ch := range notification.Channels() {
+		id := uuid.NewString() // shared by the channels tasks
+		for _, route := range n.routes(notification, notifiable) {
 			tasks = append(tasks, &NotificationTask{
-				NotificationType: getTypeName(notification),
-				NotifiableType:   getTypeName(notifiable),
+				ID:               id,
+				NotificationType: notificationTypeName(notification),
+				NotifiableType:   NotifiableType(notifiable),
 				NotifiableID:     notifiable.GetNotifiableID(),
-				Channel:          ch,
+				Channel:          route.Channel,
 				Data:             notification.Data(),
+				processAt:        route.At,
 			})
 		}
 	}
@@ -121,6 +141,10 @@ func (n *Notify) buildTasks(notification Notification, notifiables ...Notifiable
 
 // handleSendNotification - sends the notification task using the handler registered for the channel.
 func (n *Notify) handleSendNotification(ctx context.Context, task *NotificationTask) error {
+	if err := n.expandDigest(ctx, task); err != nil {
+		return err
+	}
+
 	if handler, ok := n.channels[task.Channel]; ok {
 		if err := handler(ctx, task); err != nil {
 			return err
@@ -150,9 +174,13 @@ func (n *Notify) dispatch(task *NotificationTask, scheduleAt *time.Time) error {
 		opts = append(opts, asynq.ProcessAt(*scheduleAt))
 	}
 
+	// Recorded before the enqueue, the worker may attempt it right away
+	n.recordQueued(context.Background(), task)
+
 	info, err := n.asynq.Enqueue(asynqTask, opts...)
 	if err != nil {
 		n.logErr(fmt.Sprintf("failed to enqueue task: %s", err))
+		n.recordAttempt(context.Background(), task, Attempt{Err: err, Final: true})
 		return fmt.Errorf("failed to enqueue task: %w", err)
 	}
 
@@ -160,6 +188,11 @@ func (n *Notify) dispatch(task *NotificationTask, scheduleAt *time.Time) error {
 	return nil
 }
 
+// NotifiableType returns the notifiable type stored with its notifications (e.g. "User")
+func NotifiableType(notifiable Notifiable) string {
+	return getTypeName(notifiable)
+}
+
 // getTypeName returns the name of the type of the given value.
 func getTypeName(v any) string {
 	t := reflect.TypeOf(v)
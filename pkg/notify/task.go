@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"taskgo/pkg/envelope"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -12,11 +14,15 @@ const TypeSendNotification = "send:notification" // Notification task type
 
 // NotificationTask implement chainq.Task interface also it's used as payload for task
 type NotificationTask struct {
+	ID               string         `json:"id"` // notification id (shared by its channels tasks)
 	NotificationType string         `json:"notification_type"`
 	NotifiableType   string         `json:"notifiable_type"`
 	NotifiableID     uint           `json:"notifiable_id"`
 	Data             map[string]any `json:"data"`
 	Channel          string         `json:"channel"`
+	Redelivery       int            `json:"redelivery,omitempty"` // resent count
+
+	processAt *time.Time // routed delay (not part of the payload)
 }
 
 func (t *NotificationTask) GetTaskType() string {
@@ -28,9 +34,9 @@ func (t *NotificationTask) GetPayload() any {
 }
 
 func (t *NotificationTask) CreateTask(opts ...asynq.Option) (*asynq.Task, error) {
-	payload, err := json.Marshal(t.GetPayload())
+	payload, err := envelope.Wrap(t.GetTaskType(), t.GetPayload())
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, err
 	}
 
 	return asynq.NewTask(t.GetTaskType(), payload, opts...), nil
@@ -54,9 +60,20 @@ func NewNotificationHandler(notify *Notify) *NotificationHandler {
 
 // Handler method for the payment task implement Handler interface
 func (h *NotificationHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	data, err := envelope.Unwrap(t.Type(), t.Payload())
+	if err != nil {
+		// A newer payload version is retried until a worker running the new code reads it
+		if envelope.IsRetryable(err) {
+			return fmt.Errorf("failed to read payload: %w", err)
+		}
+		return fmt.Errorf("failed to read payload: %w: %w", err, asynq.SkipRetry)
+	}
+
 	var notificationTask NotificationTask
-	if err := json.Unmarshal(t.Payload(), &notificationTask); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	if err := json.Unmarshal(data, &notificationTask); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w: %w", err, asynq.SkipRetry)
 	}
-	return h.Notify.handleSendNotification(ctx, &notificationTask)
+	err = h.Notify.handleSendNotification(ctx, &notificationTask)
+	h.Notify.recordAttempt(ctx, &notificationTask, Attempt{Err: err, Final: finalAttempt(ctx, err)})
+	return err
 }
@@ -0,0 +1,69 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"taskgo/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorded struct {
+	task    notify.NotificationTask
+	attempt notify.Attempt
+}
+
+type memoryRecorder struct {
+	mu       sync.Mutex
+	attempts []recorded
+}
+
+func (r *memoryRecorder) Queued(ctx context.Context, task *notify.NotificationTask) error {
+	return nil
+}
+
+func (r *memoryRecorder) Attempted(ctx context.Context, task *notify.NotificationTask, attempt notify.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, recorded{task: *task, attempt: attempt})
+	return nil
+}
+
+func TestNotify_DeliveryRecorder(t *testing.T) {
+	n := notify.New(nopLogger{}, nil)
+	n.RegisterChannels(map[string]notify.NotificationChannelHandler{
+		"database": func(ctx context.Context, task *notify.NotificationTask) error { return nil },
+		"sms":      func(ctx context.Context, task *notify.NotificationTask) error { return errors.New("gateway down") },
+	})
+
+	recorder := &memoryRecorder{}
+	n.UseDeliveryRecorder(recorder)
+
+	err := n.SendNow(welcome{}, &user{id: 1})
+	assert.EqualError(t, err, "gateway down")
+
+	require.Len(t, recorder.attempts, 2)
+	database, sms := recorder.attempts[0], recorder.attempts[1]
+
+	// The channels deliveries share the notification id
+	assert.NotEmpty(t, database.task.ID)
+	assert.Equal(t, database.task.ID, sms.task.ID)
+
+	assert.Equal(t, "database", database.task.Channel)
+	assert.NoError(t, database.attempt.Err)
+	assert.True(t, database.attempt.Final)
+
+	assert.Equal(t, "sms", sms.task.Channel)
+	assert.EqualError(t, sms.attempt.Err, "gateway down")
+	assert.True(t, sms.attempt.Final)
+
+	// Every send is a new notification
+	recorder.attempts = nil
+	_ = n.SendNow(welcome{}, &user{id: 1})
+	require.Len(t, recorder.attempts, 2)
+	assert.NotEqual(t, database.task.ID, recorder.attempts[0].task.ID)
+}
@@ -0,0 +1,94 @@
+package notify_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"taskgo/pkg/notify"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisDigestStore(t *testing.T) (*miniredis.Miniredis, *notify.RedisDigestStore) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, notify.NewRedisDigestStore(client, "notify:digests:", time.Hour)
+}
+
+func TestRedisDigestStore_AddsToTheOpenBatch(t *testing.T) {
+	server, store := newRedisDigestStore(t)
+	ctx := context.Background()
+
+	batch, opened, err := store.Add(ctx, "stock", map[string]any{"product": 1}, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, opened)
+	assert.NotEmpty(t, batch)
+
+	same, opened, err := store.Add(ctx, "stock", map[string]any{"product": 2}, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, opened)
+	assert.Equal(t, batch, same)
+
+	entries, err := store.Entries(ctx, batch)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"product": float64(1)}, {"product": float64(2)}}, entries)
+
+	// A new batch is opened after the window, the closed batch entries are kept for the retention
+	server.FastForward(time.Minute + time.Second)
+
+	next, opened, err := store.Add(ctx, "stock", map[string]any{"product": 3}, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, opened)
+	assert.NotEqual(t, batch, next)
+
+	entries, _ = store.Entries(ctx, batch)
+	assert.Len(t, entries, 2)
+	entries, _ = store.Entries(ctx, next)
+	assert.Len(t, entries, 1)
+
+	server.FastForward(time.Hour)
+	entries, _ = store.Entries(ctx, batch)
+	assert.Empty(t, entries)
+}
+
+func TestRedisDigestStore_ConcurrentAddsShareOneBatch(t *testing.T) {
+	_, store := newRedisDigestStore(t)
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		batches = map[string]bool{}
+		opened  int
+	)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			batch, isOpened, err := store.Add(ctx, "orders", map[string]any{"order": i}, time.Minute)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			batches[batch] = true
+			if isOpened {
+				opened++
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, opened)
+	for batch := range batches {
+		entries, err := store.Entries(ctx, batch)
+		require.NoError(t, err)
+		assert.Len(t, entries, 20)
+	}
+}
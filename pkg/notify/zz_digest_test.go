@@ -0,0 +1,149 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"taskgo/pkg/notify"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDigestStore struct {
+	mu      sync.Mutex
+	open    map[string]string
+	batches map[string][]map[string]any
+}
+
+func newMemoryDigestStore() *memoryDigestStore {
+	return &memoryDigestStore{open: map[string]string{}, batches: map[string][]map[string]any{}}
+}
+
+func (s *memoryDigestStore) Add(ctx context.Context, key string, entry map[string]any, window time.Duration) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, ok := s.open[key]
+	if !ok {
+		batch = fmt.Sprintf("batch-%d", len(s.open)+1)
+		s.open[key] = batch
+	}
+	s.batches[batch] = append(s.batches[batch], entry)
+	return batch, !ok, nil
+}
+
+func (s *memoryDigestStore) Entries(ctx context.Context, batch string) ([]map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches[batch], nil
+}
+
+type stockAlert struct{ product int }
+
+func (stockAlert) Channels() []string          { return []string{"database"} }
+func (stockAlert) ShouldQueue() bool           { return false }
+func (stockAlert) ScheduledAt() *time.Time     { return nil }
+func (stockAlert) DigestKey() string           { return "stock" }
+func (stockAlert) DigestWindow() time.Duration { return time.Hour }
+func (a stockAlert) Data() map[string]any      { return map[string]any{"product": a.product} }
+
+func digestTask(t *testing.T, batch string) *asynq.Task {
+	task := &notify.NotificationTask{
+		ID:               "notification-1",
+		NotificationType: "stockAlertDigest",
+		NotifiableType:   "user",
+		NotifiableID:     1,
+		Channel:          "database",
+		Data:             map[string]any{"digest": map[string]any{"key": "stock", "batch": batch}},
+	}
+	asynqTask, err := task.CreateTask()
+	require.NoError(t, err)
+	return asynqTask
+}
+
+func TestNotify_DigestAccumulatesTheBatch(t *testing.T) {
+	var sent []map[string]any
+	n := notify.New(nopLogger{}, nil)
+	n.RegisterChannels(map[string]notify.NotificationChannelHandler{
+		"database": func(ctx context.Context, task *notify.NotificationTask) error {
+			sent = append(sent, task.Data)
+			return nil
+		},
+	})
+
+	store := newMemoryDigestStore()
+	n.UseDigests(store, map[string]notify.DigestSummarizer{
+		"stock": func(entries []map[string]any) map[string]any {
+			return map[string]any{"products": len(entries)}
+		},
+	})
+
+	// The first alert opens the batch and schedules the digest (the queue is not initialized here)
+	err := n.Send(stockAlert{product: 1}, &user{id: 1})
+	assert.ErrorContains(t, err, "asynq client not initialized")
+
+	// The next alerts join the batch, nothing is sent on its own
+	require.NoError(t, n.Send(stockAlert{product: 2}, &user{id: 1}))
+	require.NoError(t, n.Send(stockAlert{product: 3}, &user{id: 1}))
+	assert.Empty(t, sent)
+
+	// Each notifiable has its own batch
+	assert.ErrorContains(t, n.Send(stockAlert{product: 4}, &user{id: 2}), "asynq client not initialized")
+	assert.Len(t, store.batches["batch-1"], 3)
+	assert.Len(t, store.batches["batch-2"], 1)
+
+	// The scheduled digest is sent as the summary of its batch
+	recorder := &memoryRecorder{}
+	n.UseDeliveryRecorder(recorder)
+
+	handler := notify.NewNotificationHandler(n)
+	require.NoError(t, handler.ProcessTask(context.Background(), digestTask(t, "batch-1")))
+
+	require.Len(t, sent, 1)
+	assert.Equal(t, 3, sent[0]["products"])
+	assert.Equal(t, map[string]any{"key": "stock", "count": 3}, sent[0]["digest"])
+
+	// The delivery records the summary (resent as is)
+	require.Len(t, recorder.attempts, 1)
+	assert.Equal(t, sent[0], recorder.attempts[0].task.Data)
+
+	encoded, err := json.Marshal(recorder.attempts[0].task.Data)
+	require.NoError(t, err)
+	var resent map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &resent))
+
+	task := &notify.NotificationTask{ID: "notification-1", NotifiableType: "user", NotifiableID: 1, Channel: "database", Data: resent}
+	asynqTask, err := task.CreateTask()
+	require.NoError(t, err)
+	require.NoError(t, handler.ProcessTask(context.Background(), asynqTask))
+	require.Len(t, sent, 2)
+	assert.Equal(t, float64(3), sent[1]["products"])
+}
+
+func TestNotify_ExpiredDigestIsNotRetried(t *testing.T) {
+	n := notify.New(nopLogger{}, nil)
+	n.RegisterChannels(map[string]notify.NotificationChannelHandler{
+		"database": func(ctx context.Context, task *notify.NotificationTask) error { return nil },
+	})
+	n.UseDigests(newMemoryDigestStore(), map[string]notify.DigestSummarizer{
+		"stock": func(entries []map[string]any) map[string]any { return map[string]any{} },
+	})
+
+	err := notify.NewNotificationHandler(n).ProcessTask(context.Background(), digestTask(t, "unknown"))
+	assert.ErrorIs(t, err, notify.ErrDigestExpired)
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestNotify_DigestWithoutStoreIsSentOnItsOwn(t *testing.T) {
+	var sent []string
+	n := newNotify(&sent)
+
+	require.NoError(t, n.Send(stockAlert{product: 1}, &user{id: 1}))
+	assert.Equal(t, []string{"database"}, sent)
+}
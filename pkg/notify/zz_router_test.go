@@ -0,0 +1,87 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskgo/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...any)  {}
+func (nopLogger) Error(msg string, fields ...any) {}
+func (nopLogger) Warn(msg string, fields ...any)  {}
+
+type user struct{ id uint }
+
+func (u *user) GetNotifiableID() uint { return u.id }
+
+type welcome struct{}
+
+func (welcome) Channels() []string      { return []string{"database", "sms"} }
+func (welcome) ShouldQueue() bool       { return false }
+func (welcome) Data() map[string]any    { return map[string]any{"message": "hello"} }
+func (welcome) ScheduledAt() *time.Time { return nil }
+
+type routerFunc func(notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error)
+
+func (f routerFunc) Route(ctx context.Context, notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error) {
+	return f(notification, notifiable, channels)
+}
+
+func newNotify(sent *[]string) *notify.Notify {
+	n := notify.New(nopLogger{}, nil)
+	handler := func(ctx context.Context, task *notify.NotificationTask) error {
+		*sent = append(*sent, task.Channel)
+		return nil
+	}
+	n.RegisterChannels(map[string]notify.NotificationChannelHandler{"database": handler, "sms": handler})
+	return n
+}
+
+func TestNotify_Router(t *testing.T) {
+	var sent []string
+	n := newNotify(&sent)
+
+	// All the channels without a router
+	require.NoError(t, n.Send(welcome{}, &user{id: 1}))
+	assert.Equal(t, []string{"database", "sms"}, sent)
+
+	// The router drops the disabled channels
+	sent = nil
+	n.UseRouter(routerFunc(func(notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error) {
+		assert.Equal(t, uint(2), notifiable.GetNotifiableID())
+		return []notify.Route{{Channel: "database"}}, nil
+	}))
+	require.NoError(t, n.Send(welcome{}, &user{id: 2}))
+	assert.Equal(t, []string{"database"}, sent)
+
+	// The failed routing sends all the channels
+	sent = nil
+	n.UseRouter(routerFunc(func(notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error) {
+		return nil, errors.New("preferences unavailable")
+	}))
+	require.NoError(t, n.Send(welcome{}, &user{id: 2}))
+	assert.Equal(t, []string{"database", "sms"}, sent)
+}
+
+func TestNotify_DelayedRouteIsQueued(t *testing.T) {
+	var sent []string
+	n := newNotify(&sent)
+
+	at := time.Now().Add(time.Hour)
+	n.UseRouter(routerFunc(func(notification notify.Notification, notifiable notify.Notifiable, channels []string) ([]notify.Route, error) {
+		return []notify.Route{{Channel: "database"}, {Channel: "sms", At: &at}}, nil
+	}))
+
+	// The delayed sms is dispatched to the queue (not initialized here) instead of being sent now
+	err := n.SendNow(welcome{}, &user{id: 1})
+	assert.ErrorContains(t, err, "asynq client not initialized")
+	assert.Equal(t, []string{"database"}, sent)
+}
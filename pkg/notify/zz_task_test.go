@@ -0,0 +1,27 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"taskgo/pkg/envelope"
+	"taskgo/pkg/notify"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotify_UnknownPayloadVersionIsRetried(t *testing.T) {
+	var sent []string
+	handler := notify.NewNotificationHandler(newNotify(&sent))
+
+	newer, _ := json.Marshal(map[string]any{"_v": 99, "_data": map[string]any{"id": "notification-1"}})
+	err := handler.ProcessTask(context.Background(), asynq.NewTask(notify.TypeSendNotification, newer))
+	assert.ErrorIs(t, err, envelope.ErrUnknownVersion)
+	assert.NotErrorIs(t, err, asynq.SkipRetry)
+
+	err = handler.ProcessTask(context.Background(), asynq.NewTask(notify.TypeSendNotification, []byte("not json")))
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+	assert.Empty(t, sent)
+}
@@ -0,0 +1,94 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FakeProvider records the sent messages instead of sending them (dev and tests)
+type FakeProvider struct {
+	mu       sync.Mutex
+	sent     []Message
+	sequence int
+	err      error
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Send records the message (or returns the failure set by FailWith)
+func (p *FakeProvider) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	p.sequence++
+	p.sent = append(p.sent, *msg)
+	return &Receipt{ID: fmt.Sprintf("fake-%d", p.sequence)}, nil
+}
+
+// ParseStatus parses the json status report(s) body: {"message_id": "fake-1", "status": "delivered"}
+func (p *FakeProvider) ParseStatus(r *http.Request) ([]StatusReport, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var reports []StatusReport
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &reports); err != nil {
+			return nil, err
+		}
+	} else {
+		var report StatusReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	for _, report := range reports {
+		if report.MessageID == "" {
+			return nil, fmt.Errorf("status report without message id")
+		}
+		switch report.Status {
+		case StatusSent, StatusDelivered, StatusFailed:
+		default:
+			return nil, fmt.Errorf("unknown status report status %q", report.Status)
+		}
+	}
+	return reports, nil
+}
+
+// Messages returns the recorded messages
+func (p *FakeProvider) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.sent...)
+}
+
+// FailWith makes the next sends fail with the error (nil to recover)
+func (p *FakeProvider) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Reset forgets the recorded messages and the failure
+func (p *FakeProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = nil
+	p.err = nil
+}
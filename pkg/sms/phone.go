@@ -0,0 +1,32 @@
+package sms
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Egyptian mobile numbers: 01[0125] + 8 digits (national), +20 country code
+var egyptianMobile = regexp.MustCompile(`^1[0125][0-9]{8}$`)
+
+// NormalizeEgyptian returns the Egyptian mobile number in E.164 format (+201XXXXXXXXX),
+// it accepts the national (01XXXXXXXXX) and the international (+20, 0020, 20) formats with spaces or dashes
+func NormalizeEgyptian(phone string) (string, error) {
+	number := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(number, "+20"):
+		number = number[3:]
+	case strings.HasPrefix(number, "0020"):
+		number = number[4:]
+	case strings.HasPrefix(number, "20") && len(number) == 12:
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = number[1:]
+	}
+
+	if !egyptianMobile.MatchString(number) {
+		return "", fmt.Errorf("%w: %q is not an Egyptian mobile number", ErrInvalidNumber, phone)
+	}
+	return "+20" + number, nil
+}
@@ -0,0 +1,148 @@
+package sms
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+/*
+|------------------------------------------
+|  Segments
+|------------------------------------------
+|	GSM-7: 160 chars in a single segment, 153 per segment when concatenated (the extension chars take 2)
+|	UCS-2: 70 code units in a single segment, 67 per segment when concatenated (any non GSM-7 char, e.g. Arabic)
+|
+|	A char taking 2 units is never split across segments
+|------------------------------------------
+*/
+
+type Encoding string
+
+const (
+	EncodingGSM7 Encoding = "GSM-7"
+	EncodingUCS2 Encoding = "UCS-2"
+)
+
+const (
+	gsmSingle = 160
+	gsmMulti  = 153
+	ucsSingle = 70
+	ucsMulti  = 67
+
+	ellipsis = "..."
+)
+
+const (
+	gsmBasic     = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsmExtension = "\f^{}\\[~]|€"
+)
+
+// Info is the encoding and the segments of a message body
+type Info struct {
+	Encoding Encoding `json:"encoding"`
+	Units    int      `json:"units"` // septets (GSM-7) or code units (UCS-2)
+	Segments int      `json:"segments"`
+}
+
+// Analyze returns the encoding and the segments of the body
+func Analyze(body string) Info {
+	encoding := encodingOf(body)
+
+	units := 0
+	for _, r := range body {
+		units += runeUnits(r, encoding)
+	}
+
+	return Info{Encoding: encoding, Units: units, Segments: segments(body, encoding)}
+}
+
+// Fit truncates the body (ending with "...") so it fits in the max segments (0 = unlimited)
+func Fit(body string, maxSegments int) string {
+	if maxSegments <= 0 {
+		return body
+	}
+
+	encoding := encodingOf(body)
+	if segments(body, encoding) <= maxSegments {
+		return body
+	}
+
+	limit := maxSegments * gsmMulti
+	if encoding == EncodingUCS2 {
+		limit = maxSegments * ucsMulti
+	}
+	if maxSegments == 1 {
+		limit = gsmSingle
+		if encoding == EncodingUCS2 {
+			limit = ucsSingle
+		}
+	}
+	limit -= len(ellipsis)
+
+	var b strings.Builder
+	for _, r := range body {
+		cost := runeUnits(r, encoding)
+		if cost > limit {
+			break
+		}
+		limit -= cost
+		b.WriteRune(r)
+	}
+
+	// The segments boundaries may waste units, shrink until it fits
+	result := strings.TrimRight(b.String(), " \n") + ellipsis
+	for segments(result, encoding) > maxSegments {
+		runes := []rune(strings.TrimSuffix(result, ellipsis))
+		result = string(runes[:len(runes)-1]) + ellipsis
+	}
+	return result
+}
+
+func encodingOf(body string) Encoding {
+	for _, r := range body {
+		if !strings.ContainsRune(gsmBasic, r) && !strings.ContainsRune(gsmExtension, r) {
+			return EncodingUCS2
+		}
+	}
+	return EncodingGSM7
+}
+
+func runeUnits(r rune, encoding Encoding) int {
+	if encoding == EncodingUCS2 {
+		return len(utf16.Encode([]rune{r}))
+	}
+	if strings.ContainsRune(gsmExtension, r) {
+		return 2
+	}
+	return 1
+}
+
+// segments counts the segments without splitting the chars taking 2 units
+func segments(body string, encoding Encoding) int {
+	single, multi := gsmSingle, gsmMulti
+	if encoding == EncodingUCS2 {
+		single, multi = ucsSingle, ucsMulti
+	}
+
+	total := 0
+	for _, r := range body {
+		total += runeUnits(r, encoding)
+	}
+	if total == 0 {
+		return 0
+	}
+	if total <= single {
+		return 1
+	}
+
+	count, used := 1, 0
+	for _, r := range body {
+		cost := runeUnits(r, encoding)
+		if used+cost > multi {
+			count++
+			used = 0
+		}
+		used += cost
+	}
+	return count
+}
@@ -0,0 +1,105 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+var (
+	ErrInvalidNumber = errors.New("invalid phone number")
+	ErrEmptyBody     = errors.New("empty sms body")
+)
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// Status is the delivery status reported by the provider
+type Status string
+
+const (
+	StatusSent      Status = "sent"      // accepted by the carrier
+	StatusDelivered Status = "delivered" // delivered to the handset
+	StatusFailed    Status = "failed"    // rejected or undelivered
+)
+
+// Message is an sms to send (To in E.164 format)
+type Message struct {
+	To   string
+	From string // sender id
+	Body string
+}
+
+// Receipt is the provider acknowledgement of a sent message
+type Receipt struct {
+	ID string // provider message id (matches the status reports)
+}
+
+// StatusReport is a delivery status callback of the provider
+type StatusReport struct {
+	MessageID string `json:"message_id"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Provider is an sms gateway
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) (*Receipt, error)
+	// ParseStatus parses the delivery status callback request of the provider
+	ParseStatus(r *http.Request) ([]StatusReport, error)
+}
+
+// Sent is a message accepted by the provider
+type Sent struct {
+	Message
+	Receipt
+	Info
+	Provider string
+}
+
+// Client sends the messages through the provider, fitting them in the max segments
+type Client struct {
+	provider    Provider
+	from        string
+	maxSegments int // 0 = unlimited
+}
+
+func NewClient(provider Provider, from string, maxSegments int) *Client {
+	return &Client{
+		provider:    provider,
+		from:        from,
+		maxSegments: maxSegments,
+	}
+}
+
+// Provider returns the sms provider
+func (c *Client) Provider() Provider {
+	return c.provider
+}
+
+// Send sends the body to the E.164 number (the body is truncated to the max segments)
+func (c *Client) Send(ctx context.Context, to, body string) (*Sent, error) {
+	if !e164Pattern.MatchString(to) {
+		return nil, fmt.Errorf("%w: %q is not in E.164 format", ErrInvalidNumber, to)
+	}
+	if body == "" {
+		return nil, ErrEmptyBody
+	}
+
+	body = Fit(body, c.maxSegments)
+	msg := Message{To: to, From: c.from, Body: body}
+
+	receipt, err := c.provider.Send(ctx, &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sent{
+		Message:  msg,
+		Receipt:  *receipt,
+		Info:     Analyze(body),
+		Provider: c.provider.Name(),
+	}, nil
+}
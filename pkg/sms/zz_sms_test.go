@@ -0,0 +1,135 @@
+package sms_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"taskgo/pkg/sms"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEgyptian(t *testing.T) {
+	valid := map[string]string{
+		"01012345678":      "+201012345678",
+		"010 1234 5678":    "+201012345678",
+		"0111-234-5678":    "+201112345678",
+		"+201212345678":    "+201212345678",
+		"00201512345678":   "+201512345678",
+		"201012345678":     "+201012345678",
+		"1012345678":       "+201012345678",
+		"(+20) 1012345678": "+201012345678",
+	}
+	for input, expected := range valid {
+		normalized, err := sms.NormalizeEgyptian(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, normalized, input)
+	}
+
+	for _, input := range []string{"", "0101234567", "01312345678", "+441012345678", "0223456789", "phone"} {
+		_, err := sms.NormalizeEgyptian(input)
+		assert.ErrorIs(t, err, sms.ErrInvalidNumber, input)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		body     string
+		encoding sms.Encoding
+		units    int
+		segments int
+	}{
+		{"", sms.EncodingGSM7, 0, 0},
+		{strings.Repeat("a", 160), sms.EncodingGSM7, 160, 1},
+		{strings.Repeat("a", 161), sms.EncodingGSM7, 161, 2},
+		{strings.Repeat("a", 306), sms.EncodingGSM7, 306, 2},
+		{strings.Repeat("a", 307), sms.EncodingGSM7, 307, 3},
+		{strings.Repeat("€", 80), sms.EncodingGSM7, 160, 1},        // extension chars take 2 septets
+		{strings.Repeat("a", 152) + "€", sms.EncodingGSM7, 154, 1}, // fits in a single segment
+		{strings.Repeat("a", 160) + "€", sms.EncodingGSM7, 162, 2}, // the € isn't split
+		{strings.Repeat("a", 152) + "€" + strings.Repeat("a", 9), sms.EncodingGSM7, 163, 2},
+		{strings.Repeat("ط", 70), sms.EncodingUCS2, 70, 1},
+		{strings.Repeat("ط", 71), sms.EncodingUCS2, 71, 2},
+		{"order shipped 📦", sms.EncodingUCS2, 16, 1}, // the emoji takes 2 code units
+	}
+
+	for _, c := range cases {
+		info := sms.Analyze(c.body)
+		assert.Equal(t, c.encoding, info.Encoding, c.body)
+		assert.Equal(t, c.units, info.Units, c.body)
+		assert.Equal(t, c.segments, info.Segments, c.body)
+	}
+}
+
+func TestFit(t *testing.T) {
+	short := "Your order #1 is confirmed"
+	assert.Equal(t, short, sms.Fit(short, 1))
+
+	long := strings.Repeat("a", 500)
+	assert.Equal(t, long, sms.Fit(long, 0))
+
+	fitted := sms.Fit(long, 2)
+	assert.Equal(t, 2, sms.Analyze(fitted).Segments)
+	assert.Equal(t, 306, len(fitted))
+	assert.True(t, strings.HasSuffix(fitted, "..."))
+
+	fitted = sms.Fit(long, 1)
+	assert.Equal(t, 160, len(fitted))
+
+	arabic := sms.Fit(strings.Repeat("ط", 200), 2)
+	info := sms.Analyze(arabic)
+	assert.Equal(t, sms.EncodingUCS2, info.Encoding)
+	assert.Equal(t, 2, info.Segments)
+	assert.Equal(t, 134, info.Units)
+}
+
+func TestClient_Send(t *testing.T) {
+	provider := sms.NewFakeProvider()
+	client := sms.NewClient(provider, "TaskGo", 1)
+
+	sent, err := client.Send(context.Background(), "+201012345678", strings.Repeat("a", 200))
+	require.NoError(t, err)
+	assert.Equal(t, "fake-1", sent.ID)
+	assert.Equal(t, "fake", sent.Provider)
+	assert.Equal(t, 1, sent.Segments)
+	assert.Equal(t, sms.EncodingGSM7, sent.Encoding)
+
+	messages := provider.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "TaskGo", messages[0].From)
+	assert.Len(t, messages[0].Body, 160)
+
+	_, err = client.Send(context.Background(), "01012345678", "not E.164")
+	assert.ErrorIs(t, err, sms.ErrInvalidNumber)
+
+	_, err = client.Send(context.Background(), "+201012345678", "")
+	assert.ErrorIs(t, err, sms.ErrEmptyBody)
+
+	provider.FailWith(errors.New("gateway down"))
+	_, err = client.Send(context.Background(), "+201012345678", "hello")
+	assert.EqualError(t, err, "gateway down")
+	assert.Len(t, provider.Messages(), 1)
+}
+
+func TestFakeProvider_ParseStatus(t *testing.T) {
+	provider := sms.NewFakeProvider()
+
+	reports, err := provider.ParseStatus(httptest.NewRequest("POST", "/", strings.NewReader(`{"message_id": "fake-1", "status": "delivered"}`)))
+	require.NoError(t, err)
+	assert.Equal(t, []sms.StatusReport{{MessageID: "fake-1", Status: sms.StatusDelivered}}, reports)
+
+	reports, err = provider.ParseStatus(httptest.NewRequest("POST", "/", strings.NewReader(`[{"message_id": "fake-1", "status": "sent"}, {"message_id": "fake-2", "status": "failed", "error": "absent subscriber"}]`)))
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+	assert.Equal(t, "absent subscriber", reports[1].Error)
+
+	_, err = provider.ParseStatus(httptest.NewRequest("POST", "/", strings.NewReader(`{"message_id": "fake-1", "status": "lost"}`)))
+	assert.Error(t, err)
+
+	_, err = provider.ParseStatus(httptest.NewRequest("POST", "/", strings.NewReader(`{"status": "sent"}`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Signed webhooks
+|------------------------------------------
+|	Every request carries:
+|	- X-Webhook-Id:        event id (the receivers use it to deduplicate the retried deliveries)
+|	- X-Webhook-Event:     event type
+|	- X-Webhook-Attempt:   delivery attempt (starts at 1)
+|	- X-Webhook-Signature: t=<unix timestamp>,v1=<hex hmac-sha256 of "<timestamp>.<body>" with the subscription secret>
+|
+|	The receivers should verify the signature and reject the old timestamps (replay protection)
+|------------------------------------------
+*/
+
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderAttempt   = "X-Webhook-Attempt"
+	HeaderSignature = "X-Webhook-Signature"
+
+	maxResponseBody = 4 << 10 // response body kept in the delivery log
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Message is a webhook request
+type Message struct {
+	ID      string // event id
+	Event   string // event type
+	Attempt int
+	Body    []byte
+}
+
+// Response is the receiver response
+type Response struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// Success checks if the receiver accepted the webhook (2xx)
+func (r *Response) Success() bool {
+	return r != nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Sender sends the signed webhooks
+type Sender struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewSender creates a new sender with the request timeout
+func NewSender(timeout time.Duration, userAgent string) *Sender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Sender{
+		client:    &http.Client{Timeout: timeout},
+		userAgent: userAgent,
+	}
+}
+
+// Send posts the signed message to the url, the response is returned even if it's not a success
+// (an error is returned only if the request couldn't be sent)
+func (s *Sender) Send(ctx context.Context, url, secret string, message Message) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(message.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, message.ID)
+	req.Header.Set(HeaderEvent, message.Event)
+	req.Header.Set(HeaderAttempt, strconv.Itoa(message.Attempt))
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), message.Body))
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
+
+	start := time.Now()
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+
+	return &Response{
+		StatusCode: res.StatusCode,
+		Body:       string(body),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// NewSecret generates a random signing secret
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// Sign returns the signature header value of the body
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// Verify verifies the signature header of the body, signatures older than tolerance are rejected (0 = no check)
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return fmt.Errorf("%w: timestamp too old", ErrInvalidSignature)
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
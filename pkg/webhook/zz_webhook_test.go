@@ -0,0 +1,86 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"taskgo/pkg/webhook"
+)
+
+func TestSendSignsTheWebhook(t *testing.T) {
+	secret := "partner-secret"
+	received := make(chan *http.Request, 1)
+	var body []byte
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+
+		if err := webhook.Verify(secret, r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer receiver.Close()
+
+	sender := webhook.NewSender(time.Second, "taskgo-webhooks")
+	res, err := sender.Send(context.Background(), receiver.URL, secret, webhook.Message{
+		ID:      "evt_1",
+		Event:   "order.shipped",
+		Attempt: 2,
+		Body:    []byte(`{"order_id":1}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !res.Success() || res.StatusCode != http.StatusAccepted || res.Body != `{"ok":true}` {
+		t.Fatalf("expected accepted response, got %d %s", res.StatusCode, res.Body)
+	}
+
+	r := <-received
+	if r.Header.Get(webhook.HeaderID) != "evt_1" || r.Header.Get(webhook.HeaderEvent) != "order.shipped" || r.Header.Get(webhook.HeaderAttempt) != "2" {
+		t.Fatalf("unexpected headers %v", r.Header)
+	}
+	if string(body) != `{"order_id":1}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
+
+func TestSendReturnsFailedResponses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	res, err := webhook.NewSender(time.Second, "").Send(context.Background(), receiver.URL, "secret", webhook.Message{ID: "evt_1", Body: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Success() || res.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected failed response, got %d", res.StatusCode)
+	}
+}
+
+func TestVerifyRejectsTamperedAndOldSignatures(t *testing.T) {
+	body := []byte(`{"order_id":1}`)
+
+	header := webhook.Sign("secret", time.Now(), body)
+	if err := webhook.Verify("secret", header, []byte(`{"order_id":2}`), time.Minute); err == nil {
+		t.Fatal("expected tampered body to be rejected")
+	}
+	if err := webhook.Verify("other", header, body, time.Minute); err == nil {
+		t.Fatal("expected wrong secret to be rejected")
+	}
+
+	old := webhook.Sign("secret", time.Now().Add(-time.Hour), body)
+	if err := webhook.Verify("secret", old, body, time.Minute); err == nil {
+		t.Fatal("expected old signature to be rejected")
+	}
+}
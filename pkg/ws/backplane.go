@@ -0,0 +1,126 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+|------------------------------------------
+|  Backplane
+|------------------------------------------
+|	The hubs of the server instances publish their messages through the backplane and deliver the messages
+|	received from it to their local clients (channel patterns are matched by each hub), the processes without
+|	websocket clients (e.g. the workers) publish to the backplane only.
+|------------------------------------------
+*/
+
+// Relay publishes the messages to the connected clients (a hub or a backplane)
+type Relay interface {
+	Publish(ctx context.Context, msg *WSMessage) error
+}
+
+// Backplane fans the messages out to the hubs of all the server instances
+type Backplane interface {
+	Relay
+	Subscribe(deliver func(msg *WSMessage)) error // [NON-BLOCKING]
+	Close() error
+}
+
+// RedisBackplane fans the messages out through a redis pub/sub channel
+type RedisBackplane struct {
+	redis   *redis.Client
+	channel string
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+}
+
+func NewRedisBackplane(client *redis.Client, channel string) *RedisBackplane {
+	return &RedisBackplane{
+		redis:   client,
+		channel: channel,
+	}
+}
+
+// Publish publishes the message to the backplane channel
+func (b *RedisBackplane) Publish(ctx context.Context, msg *WSMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal websocket message: %w", err)
+	}
+
+	if err := b.redis.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish websocket message to %s: %w", b.channel, err)
+	}
+	return nil
+}
+
+// Subscribe passes the messages published to the backplane channel to deliver until Close
+func (b *RedisBackplane) Subscribe(deliver func(msg *WSMessage)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel != nil {
+		return fmt.Errorf("websocket backplane is already subscribed to %s", b.channel)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pubsub := b.redis.Subscribe(ctx, b.channel)
+
+	// Wait for the subscription confirmation so no message published after Subscribe is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		cancel()
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+
+	b.cancel = cancel
+	b.done = make(chan struct{})
+
+	go func() {
+		defer close(b.done)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case received, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var msg WSMessage
+				if err := json.Unmarshal([]byte(received.Payload), &msg); err != nil {
+					log.Println("Invalid websocket backplane message:", err)
+					continue
+				}
+				deliver(&msg)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the subscription
+func (b *RedisBackplane) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel == nil {
+		return nil
+	}
+
+	b.cancel()
+	<-b.done
+	b.cancel = nil
+	return nil
+}
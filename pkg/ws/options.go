@@ -0,0 +1,49 @@
+package ws
+
+import "time"
+
+// Options of the hub clients connections
+type Options struct {
+	WriteWait        time.Duration // deadline of each write (messages, pings and close frames)
+	PongWait         time.Duration // the connection is dropped if no pong (or message) is read within it
+	PingPeriod       time.Duration // heartbeat interval, must be less than the pong wait
+	MaxMessageSize   int64         // the connection is closed if a client message is larger
+	SendBuffer       int           // messages buffered per client, a slow client is dropped when it's full
+	CloseGracePeriod time.Duration // how long the hub close waits for the clients to get their close frame
+}
+
+// DefaultOptions returns the default connections options
+func DefaultOptions() Options {
+	return Options{
+		WriteWait:        10 * time.Second,
+		PongWait:         60 * time.Second,
+		PingPeriod:       54 * time.Second,
+		MaxMessageSize:   8 * 1024,
+		SendBuffer:       256,
+		CloseGracePeriod: 5 * time.Second,
+	}
+}
+
+// normalize replaces the unset options by their defaults
+func (o Options) normalize() Options {
+	defaults := DefaultOptions()
+	if o.WriteWait <= 0 {
+		o.WriteWait = defaults.WriteWait
+	}
+	if o.PongWait <= 0 {
+		o.PongWait = defaults.PongWait
+	}
+	if o.PingPeriod <= 0 || o.PingPeriod >= o.PongWait {
+		o.PingPeriod = o.PongWait * 9 / 10
+	}
+	if o.MaxMessageSize <= 0 {
+		o.MaxMessageSize = defaults.MaxMessageSize
+	}
+	if o.SendBuffer <= 0 {
+		o.SendBuffer = defaults.SendBuffer
+	}
+	if o.CloseGracePeriod <= 0 {
+		o.CloseGracePeriod = defaults.CloseGracePeriod
+	}
+	return o
+}
@@ -15,3 +15,8 @@ func NewWsServer(hub *Hub, upgrader websocket.Upgrader) *Server {
 		Upgrader: upgrader,
 	}
 }
+
+// Shutdown stops the hub and closes its clients with a close frame (implements ioc.Shutdownable)
+func (s *Server) Shutdown() error {
+	return s.Hub.Close()
+}
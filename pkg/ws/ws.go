@@ -1,11 +1,13 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -16,6 +18,10 @@ type Client struct {
 	UserID   string
 	Subs     map[string]bool // subscription to channels like "orders", "dashboard"
 	subMutex sync.Mutex
+
+	opts         Options
+	closeFrame   []byte // written by the WritePump once Send is closed
+	disconnected bool   // set by the hub (under its mutex) once Send is closed, the client can't be registered or subscribed again
 }
 
 type ChannelPolicy struct {
@@ -25,10 +31,15 @@ type ChannelPolicy struct {
 }
 
 type Hub struct {
-	clients  map[*Client]bool
-	channels map[string]map[*Client]bool
-	policies []*ChannelPolicy
-	mutex    sync.RWMutex
+	clients   map[*Client]bool
+	channels  map[string]map[*Client]bool
+	policies  []*ChannelPolicy
+	backplane Backplane // optional, fans the messages out to the other server instances
+	mutex     sync.RWMutex
+
+	opts    Options
+	writers sync.WaitGroup // running WritePumps (waited by Close)
+	closed  bool
 }
 
 type WSMessage struct {
@@ -39,29 +50,75 @@ type WSMessage struct {
 }
 
 func NewHub() *Hub {
+	return NewHubWithOptions(DefaultOptions())
+}
+
+// NewHubWithOptions - create a hub with the clients connections options (the unset ones use the defaults)
+func NewHubWithOptions(opts Options) *Hub {
 	return &Hub{
 		clients:  make(map[*Client]bool),
 		channels: make(map[string]map[*Client]bool),
+		opts:     opts.normalize(),
+	}
+}
+
+// NewClient - create a client of the connection with the hub send buffer
+func (h *Hub) NewClient(conn *websocket.Conn, userID string) *Client {
+	return &Client{
+		Conn:   conn,
+		UserID: userID,
+		Send:   make(chan []byte, h.opts.SendBuffer),
+		Subs:   make(map[string]bool),
+		opts:   h.opts,
 	}
 }
 
 func (h *Hub) Register(c *Client) {
+	h.register(c, false)
+}
+
+// register - register the client (and its WritePump) if the hub isn't closed
+func (h *Hub) register(c *Client, writer bool) bool {
 	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.closed || c.disconnected {
+		return false
+	}
+
 	h.clients[c] = true
-	h.mutex.Unlock()
+	c.opts = h.opts
+	if writer {
+		h.writers.Add(1)
+	}
+	return true
 }
 
-// Unregister - unregister client from the hub
+// Unregister - unregister client from the hub (its connection is closed normally)
 func (h *Hub) Unregister(c *Client) {
-	log.Println("Unregistering client")
+	h.disconnect(c, websocket.CloseNormalClosure, "")
+}
+
+// disconnect - unregister the client then close its Send chan, its WritePump closes the connection with the close frame
+// (a client is disconnected once, e.g. dropped as too slow then its ReadPump stops)
+func (h *Hub) disconnect(c *Client, code int, text string) {
 	h.mutex.Lock()
+	if !h.clients[c] {
+		h.mutex.Unlock()
+		return
+	}
+
+	log.Println("Unregistering client")
 	delete(h.clients, c)
+	c.subMutex.Lock()
 	for ch := range c.Subs {
 		delete(h.channels[ch], c)
 		if len(h.channels[ch]) == 0 { // If there is user specific chan that would help like "user.{id}"
 			delete(h.channels, ch)
 		}
 	}
+	c.subMutex.Unlock()
+	c.closeFrame = websocket.FormatCloseMessage(code, text)
+	c.disconnected = true
 	h.mutex.Unlock()
 	close(c.Send)
 }
@@ -84,8 +141,13 @@ func (h *Hub) Unsubscribe(c *Client, channel string) {
 }
 
 // Subscribe - subscribe client to specific channel
+// (the client may be subscribed before it's registered by Listen but never once it's disconnected: its Send chan is closed)
 func (h *Hub) Subscribe(c *Client, channel string) {
 	h.mutex.Lock()
+	if c.disconnected {
+		h.mutex.Unlock()
+		return
+	}
 	if _, ok := h.channels[channel]; !ok {
 		h.channels[channel] = make(map[*Client]bool)
 	}
@@ -96,7 +158,67 @@ func (h *Hub) Subscribe(c *Client, channel string) {
 	h.mutex.Unlock()
 }
 
-// Broadcast  - broadcast message to specific channel
+// UseBackplane - publish the hub messages through the backplane and deliver the backplane messages to the hub clients
+func (h *Hub) UseBackplane(backplane Backplane) error {
+	if err := backplane.Subscribe(h.Broadcast); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	h.backplane = backplane
+	h.mutex.Unlock()
+	return nil
+}
+
+// Publish - broadcast message to the clients of all the server instances (local clients only without a backplane)
+func (h *Hub) Publish(ctx context.Context, msg *WSMessage) error {
+	h.mutex.RLock()
+	backplane := h.backplane
+	h.mutex.RUnlock()
+
+	if backplane == nil {
+		h.Broadcast(msg)
+		return nil
+	}
+	return backplane.Publish(ctx, msg)
+}
+
+// Close - stop receiving the backplane messages then close the clients connections with a going away close frame
+// (waits the close grace period for the clients to get their close frame)
+func (h *Hub) Close() error {
+	h.mutex.Lock()
+	h.closed = true
+	backplane := h.backplane
+	clients := make([]*Client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mutex.Unlock()
+
+	var err error
+	if backplane != nil {
+		err = backplane.Close()
+	}
+
+	for _, c := range clients {
+		h.disconnect(c, websocket.CloseGoingAway, "server shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(h.opts.CloseGracePeriod):
+		log.Println("Websocket clients not closed within the grace period")
+	}
+	return err
+}
+
+// Broadcast  - broadcast message to specific channel (local clients only)
 func (h *Hub) Broadcast(msg *WSMessage) {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
@@ -113,7 +235,7 @@ func (h *Hub) Broadcast(msg *WSMessage) {
 				select {
 				case c.Send <- payload:
 				default:
-					go h.Unregister(c)
+					go h.disconnect(c, websocket.ClosePolicyViolation, "client too slow")
 				}
 			}
 		}
@@ -161,14 +283,26 @@ func matchPattern(pattern, channel string) bool {
 }
 
 // ReadPump - read messages from the websockets connection
+// the connection is dropped if no pong (or message) is read within the pong wait or a message is too large
 func (c *Client) ReadPump(hub *Hub) {
 	defer hub.Unregister(c)
+
+	opts := c.options()
+	c.Conn.SetReadLimit(opts.MaxMessageSize)
+	_ = c.Conn.SetReadDeadline(time.Now().Add(opts.PongWait))
+	c.Conn.SetPongHandler(func(string) error {
+		return c.Conn.SetReadDeadline(time.Now().Add(opts.PongWait))
+	})
+
 	for {
 		msgType, msg, err := c.Conn.ReadMessage()
 		if err != nil {
-			log.Printf("Read error: %v | msgType: %d | msg: %v", err, msgType, msg)
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				log.Printf("Read error: %v | msgType: %d", err, msgType)
+			}
 			break
 		}
+		_ = c.Conn.SetReadDeadline(time.Now().Add(opts.PongWait))
 
 		if msgType != websocket.TextMessage {
 			continue
@@ -203,7 +337,9 @@ func (c *Client) ReadPump(hub *Hub) {
 				return
 			}
 
-			hub.Broadcast(wsMessage)
+			if err := hub.Publish(context.Background(), wsMessage); err != nil {
+				log.Println("Publish error:", err)
+			}
 
 		default:
 			log.Printf("Unknown message type: %s", m["type"])
@@ -232,20 +368,71 @@ func (h *Hub) receivedMessage(c *Client, m map[string]string) (*WSMessage, error
 	}, nil
 }
 
-// WritePump - listen to send chan and writes messages to the websocket connection
+// WritePump - listen to send chan and writes messages to the websocket connection, pings the client every ping period
+// and writes the close frame once the client is unregistered (each write has the write deadline)
 func (c *Client) WritePump() {
-	for msg := range c.Send {
-		if err := c.Conn.WriteMessage(websocket.TextMessage, msg); err != nil {
-			log.Println("Write error:", err)
-			break
+	opts := c.options()
+	ticker := time.NewTicker(opts.PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.Conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.Send:
+			_ = c.Conn.SetWriteDeadline(time.Now().Add(opts.WriteWait))
+			if !ok {
+				_ = c.Conn.WriteMessage(websocket.CloseMessage, c.closeFrame)
+				return
+			}
+
+			if err := c.Conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				log.Println("Write error:", err)
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.Conn.SetWriteDeadline(time.Now().Add(opts.WriteWait))
+			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
 		}
 	}
-	c.Conn.Close()
 }
 
 // Listen - registers client to hub and starts WritePump and ReadPump[BLOCKING]
 func (c *Client) Listen(hub *Hub) {
-	hub.Register(c)
-	go c.WritePump()
+	if !hub.register(c, true) {
+		// The hub is closed (server shutting down), drop the subscriptions made before listening
+		for _, channel := range c.subscriptions() {
+			hub.Unsubscribe(c, channel)
+		}
+		deadline := time.Now().Add(hub.opts.WriteWait)
+		_ = c.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), deadline)
+		c.Conn.Close()
+		return
+	}
+
+	go func() {
+		defer hub.writers.Done()
+		c.WritePump()
+	}()
 	c.ReadPump(hub)
 }
+
+// subscriptions - the client subscribed channels
+func (c *Client) subscriptions() []string {
+	c.subMutex.Lock()
+	defer c.subMutex.Unlock()
+	channels := make([]string, 0, len(c.Subs))
+	for channel := range c.Subs {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// options - the client connection options (the defaults if the client isn't registered to a hub)
+func (c *Client) options() Options {
+	return c.opts.normalize()
+}
@@ -0,0 +1,103 @@
+package ws_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"taskgo/pkg/ws"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackplane delivers the published messages to all the subscribed hubs
+type memoryBackplane struct {
+	mu          sync.Mutex
+	subscribers []func(msg *ws.WSMessage)
+}
+
+func (b *memoryBackplane) Publish(ctx context.Context, msg *ws.WSMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, deliver := range b.subscribers {
+		deliver(msg)
+	}
+	return nil
+}
+
+func (b *memoryBackplane) Subscribe(deliver func(msg *ws.WSMessage)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, deliver)
+	return nil
+}
+
+func (b *memoryBackplane) Close() error {
+	return nil
+}
+
+func newTestClient(hub *ws.Hub, userID string, channels ...string) *ws.Client {
+	client := &ws.Client{
+		UserID: userID,
+		Send:   make(chan []byte, 1),
+		Subs:   make(map[string]bool),
+	}
+	hub.Register(client)
+	for _, channel := range channels {
+		hub.Subscribe(client, channel)
+	}
+	return client
+}
+
+func receive(t *testing.T, client *ws.Client) ws.WSMessage {
+	t.Helper()
+	select {
+	case payload := <-client.Send:
+		var msg ws.WSMessage
+		require.NoError(t, json.Unmarshal(payload, &msg))
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("expected the client to receive a message")
+		return ws.WSMessage{}
+	}
+}
+
+func TestHub_PublishWithoutBackplaneBroadcastsLocally(t *testing.T) {
+	hub := ws.NewHub()
+	subscriber := newTestClient(hub, "1", "user.1")
+	other := newTestClient(hub, "2", "user.2")
+
+	require.NoError(t, hub.Publish(context.Background(), &ws.WSMessage{Type: "notification", Channel: "user.1", Data: "hello"}))
+
+	msg := receive(t, subscriber)
+	assert.Equal(t, "user.1", msg.Channel)
+	assert.Equal(t, "hello", msg.Data)
+	assert.Empty(t, other.Send)
+}
+
+func TestHub_PublishThroughBackplaneReachesAllInstances(t *testing.T) {
+	backplane := &memoryBackplane{}
+	first, second := ws.NewHub(), ws.NewHub()
+	require.NoError(t, first.UseBackplane(backplane))
+	require.NoError(t, second.UseBackplane(backplane))
+
+	onFirst := newTestClient(first, "1", "user.1")
+	onSecond := newTestClient(second, "2", "user.2")
+
+	// Published once by the first instance, delivered once on each instance (pattern matched by each hub)
+	require.NoError(t, first.Publish(context.Background(), &ws.WSMessage{Type: "announcement", Channel: "user.*", Data: "maintenance"}))
+
+	assert.Equal(t, "maintenance", receive(t, onFirst).Data)
+	assert.Equal(t, "maintenance", receive(t, onSecond).Data)
+	assert.Empty(t, onFirst.Send)
+	assert.Empty(t, onSecond.Send)
+
+	// A backplane publisher without hub (e.g. a worker) reaches the clients too
+	require.NoError(t, backplane.Publish(context.Background(), &ws.WSMessage{Type: "notification", Channel: "user.2", Data: "ready"}))
+
+	assert.Equal(t, "ready", receive(t, onSecond).Data)
+	assert.Empty(t, onFirst.Send)
+}
@@ -0,0 +1,156 @@
+package ws_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"taskgo/pkg/ws"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveHub accepts the websocket clients of the hub
+func serveHub(t *testing.T, hub *ws.Hub) string {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := hub.NewClient(conn, "1")
+		hub.Subscribe(client, "user.1")
+		client.Listen(hub)
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestClient_Heartbeat(t *testing.T) {
+	hub := ws.NewHubWithOptions(ws.Options{PingPeriod: 20 * time.Millisecond, PongWait: 100 * time.Millisecond})
+	conn := dial(t, serveHub(t, hub))
+
+	// The client is pinged (the pongs are answered by the default ping handler while reading)
+	pinged := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		pinged <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-pinged:
+		case <-time.After(time.Second):
+			t.Fatal("the client wasn't pinged")
+		}
+	}
+
+	// Still connected after several pong waits
+	require.NoError(t, hub.Publish(t.Context(), &ws.WSMessage{Type: "notification", Channel: "user.1", Data: "alive"}))
+}
+
+func TestClient_DroppedWithoutPong(t *testing.T) {
+	hub := ws.NewHubWithOptions(ws.Options{PingPeriod: 20 * time.Millisecond, PongWait: 50 * time.Millisecond})
+	conn := dial(t, serveHub(t, hub))
+
+	// Not reading: the pings are never answered
+	time.Sleep(200 * time.Millisecond)
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			assert.NotContains(t, err.Error(), "timeout", "the server should have closed the connection")
+			break
+		}
+	}
+}
+
+func TestClient_MessageTooLarge(t *testing.T) {
+	hub := ws.NewHubWithOptions(ws.Options{MaxMessageSize: 64})
+	conn := dial(t, serveHub(t, hub))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"message","data":"`+strings.Repeat("x", 128)+`"}`)))
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+}
+
+func TestHub_CloseSendsGoingAway(t *testing.T) {
+	hub := ws.NewHubWithOptions(ws.Options{CloseGracePeriod: time.Second})
+	url := serveHub(t, hub)
+	conn := dial(t, url)
+
+	// Wait for the client to be registered
+	require.Eventually(t, func() bool {
+		_ = hub.Publish(t.Context(), &ws.WSMessage{Type: "notification", Channel: "user.1", Data: "hello"})
+		_ = conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+		_, _, err := conn.ReadMessage()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, hub.Close())
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+			break
+		}
+	}
+
+	// The clients connecting after the close are turned away
+	late := dial(t, url)
+	_ = late.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := late.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
+
+func TestHub_SlowClientIsDisconnectedOnce(t *testing.T) {
+	hub := ws.NewHub()
+	client := newTestClient(hub, "1", "user.1")
+
+	// The full buffer drops the client, its unregister afterwards (ReadPump) doesn't close Send again
+	hub.Broadcast(&ws.WSMessage{Type: "notification", Channel: "user.1", Data: "first"})
+	hub.Broadcast(&ws.WSMessage{Type: "notification", Channel: "user.1", Data: "second"})
+
+	require.Eventually(t, func() bool { return len(hub.GetRegisteredChannels()) == 0 }, time.Second, 5*time.Millisecond)
+	assert.NotPanics(t, func() { hub.Unregister(client) })
+}
+
+func TestHub_DisconnectedClientIsNotSubscribedAgain(t *testing.T) {
+	hub := ws.NewHub()
+	client := hub.NewClient(nil, "1")
+
+	hub.Register(client)
+	hub.Unregister(client)
+
+	// e.g. the handler subscribes the client after its connection was dropped
+	hub.Subscribe(client, "user.1")
+	hub.Register(client)
+	assert.Empty(t, hub.GetRegisteredChannels())
+
+	assert.NotPanics(t, func() {
+		hub.Broadcast(&ws.WSMessage{Type: "notification", Channel: "user.1", Data: "hello"})
+		hub.Unregister(client)
+	})
+}
@@ -0,0 +1,185 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+|------------------------------------------
+|  Streaming XLSX writer
+|------------------------------------------
+|	Writes a single sheet workbook row by row (the rows are never kept in memory):
+|	- numbers are written as numeric cells, the other values as inline strings
+|	- the workbook parts are written first, the sheet is the last zip entry so it can be streamed
+|------------------------------------------
+*/
+
+const (
+	contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+	rootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+	workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	sheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	sheetFooter = `</sheetData></worksheet>`
+)
+
+// Writer writes the rows of a single sheet workbook
+type Writer struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	rows   int
+	closed bool
+}
+
+// NewWriter starts a new workbook with the sheet name
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	// excel sheet names are at most 31 characters
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	} else if len([]rune(sheetName)) > 31 {
+		sheetName = string([]rune(sheetName)[:31])
+	}
+
+	zw := zip.NewWriter(w)
+	parts := []struct{ path, content string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(workbook, escape(sheetName))},
+	}
+	for _, part := range parts {
+		fw, err := zw.Create(part.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{zip: zw, sheet: bufio.NewWriter(sheet)}
+	if _, err := writer.sheet.WriteString(sheetHeader); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteRow appends a row to the sheet
+func (w *Writer) WriteRow(values []any) error {
+	if w.closed {
+		return fmt.Errorf("xlsx: write to a closed writer")
+	}
+
+	w.rows++
+	if _, err := fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows); err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		ref := columnName(i) + strconv.Itoa(w.rows)
+		if err := w.writeCell(ref, value); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close ends the sheet and the workbook (the underlying writer is not closed)
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if _, err := w.sheet.WriteString(sheetFooter); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+func (w *Writer) writeCell(ref string, value any) error {
+	var number string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int:
+		number = strconv.Itoa(v)
+	case int32:
+		number = strconv.FormatInt(int64(v), 10)
+	case int64:
+		number = strconv.FormatInt(v, 10)
+	case uint:
+		number = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		number = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		number = strconv.FormatUint(v, 10)
+	case float32:
+		number = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		number = strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return w.writeString(ref, v.Format(time.RFC3339))
+	case string:
+		return w.writeString(ref, v)
+	default:
+		return w.writeString(ref, fmt.Sprint(v))
+	}
+
+	_, err := fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, number)
+	return err
+}
+
+func (w *Writer) writeString(ref, value string) error {
+	if _, err := fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err != nil {
+		return err
+	}
+	if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+		return err
+	}
+	_, err := w.sheet.WriteString(`</t></is></c>`)
+	return err
+}
+
+// columnName returns the column letters of the zero based index (0 = A, 26 = AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escape(value string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"taskgo/pkg/xlsx"
+)
+
+func TestWriterStreamsTheSheet(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := xlsx.NewWriter(&buf, "Sales")
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+	_ = w.WriteRow([]any{"product", "units", "revenue"})
+	_ = w.WriteRow([]any{"Cable <USB> & charger", 3, 149.5})
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("expected a valid zip: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range reader.File {
+		rc, _ := file.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="Sales"`) {
+		t.Fatalf("expected the sheet name in the workbook")
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, expected := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">product</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Cable &lt;USB&gt; &amp; charger</t></is></c>`,
+		`<c r="B2"><v>3</v></c>`,
+		`<c r="C2"><v>149.5</v></c>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Fatalf("expected %s in the sheet, got %s", expected, sheet)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"taskgo/internal/adapters"
+	"taskgo/internal/deps"
+	chainq "taskgo/pkg/asynq_chain"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainOrchestrator_PausedStepIsRescheduledOnce(t *testing.T) {
+	queue := "chain-pause"
+	t.Cleanup(func() { cleanupQueue(queue) })
+	ctx := context.Background()
+	client := deps.Queue().Client
+	logger := adapters.NewLoggerAdapter(deps.Log().Channel("queue_log"))
+
+	control := chainq.NewRedisControl(deps.Cache().Redis, time.Hour)
+	var calls atomic.Int32
+	orchestrator := chainq.NewChainOrchestrator(client, logger)
+	orchestrator.SetControl(control, time.Hour)
+	orchestrator.RegisterHandler("pause:step", asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	chain := chainq.NewChain(client, logger, &chainq.ChainOptions{DefaultQueue: queue}).Then(deadLetterStep{"pause:step"})
+	message, err := chain.Build()
+	require.NoError(t, err)
+	require.NoError(t, control.Pause(ctx, chain.ID()))
+	t.Cleanup(func() { _ = control.Cancel(ctx, chain.ID()) })
+
+	// The same step message is delivered twice while the chain is paused
+	for range 2 {
+		require.NoError(t, orchestrator.ProcessTask(ctx, message.Task()))
+	}
+	assert.Zero(t, calls.Load())
+
+	scheduled, err := deps.Queue().Inspector.ListScheduledTasks(queue)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 1)
+	assert.Equal(t, chainq.PausedStepTaskID(chainRunKey(t, message), 0, 1), scheduled[0].ID)
+
+	// The next poll is scheduled with its own task id
+	require.NoError(t, orchestrator.ProcessTask(ctx, asynq.NewTask(chainq.TypeChainOrchestrator, scheduled[0].Payload)))
+
+	scheduled, err = deps.Queue().Inspector.ListScheduledTasks(queue)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 2)
+
+	var payload chainq.ChainPayload
+	for _, task := range scheduled {
+		require.NoError(t, json.Unmarshal(task.Payload, &payload))
+		assert.Equal(t, chainq.PausedStepTaskID(payload.RunKey(), 0, payload.PausePolls), task.ID)
+	}
+}
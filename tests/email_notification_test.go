@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/notification"
+	"taskgo/internal/notification/handlers"
+	"taskgo/pkg/mail"
+	"taskgo/pkg/notify"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChannelHandler_ReportReady(t *testing.T) {
+	db := deps.Gorm().DB
+	outbox, ok := deps.Mailer().Transport().(*mail.MemoryTransport)
+	require.True(t, ok, "the testing mailer should use the memory transport")
+	outbox.Reset()
+
+	user := models.User{FirstName: "Mail", LastName: "User", Email: "mail@test.com", Password: "password", PhoneNumber: "01012345680", Role: "admin", IsActive: true}
+	db.Create(&user)
+
+	job := &models.ReportJob{UserID: user.ID, Type: enums.ReportTypeSales, Format: enums.ReportFormatCSV, Params: "{}", Status: enums.ReportJobStatusDone, FileName: "sales.csv"}
+	db.Create(job)
+
+	// The queued notification data is decoded from json
+	var data map[string]any
+	encoded, _ := json.Marshal(notification.NewReportReadyNotification(job).Data())
+	require.NoError(t, json.Unmarshal(encoded, &data))
+
+	task := &notify.NotificationTask{
+		NotificationType: "ReportReadyNotification",
+		NotifiableType:   notify.NotifiableType(&user),
+		NotifiableID:     user.ID,
+		Data:             data,
+		Channel:          string(enums.NotificationChannelEmail),
+	}
+	require.NoError(t, handlers.EmailChannelHandler(context.Background(), task))
+
+	sent := outbox.Outbox()
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{`"Mail User" <mail@test.com>`}, sent[0].To)
+	assert.Contains(t, sent[0].Subject, "is ready")
+	assert.Contains(t, sent[0].HTML, "sales.csv")
+	assert.Contains(t, sent[0].Text, "/api/v1/admin/reports/")
+
+	// Unknown notifiables are not retried
+	task.NotifiableID = user.ID + 1000
+	assert.Error(t, handlers.EmailChannelHandler(context.Background(), task))
+	assert.Len(t, outbox.Outbox(), 1)
+}
@@ -0,0 +1,363 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"taskgo/internal/adapters"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	pkgErrors "taskgo/pkg/errors"
+	"taskgo/pkg/utils"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runDeadLetterWorker runs a worker on the given queue that records the failed tasks like the worker ErrorHandler,
+// the returned func stops the worker (it's also stopped and the queue is deleted when the test ends)
+func runDeadLetterWorker(t *testing.T, queue string, handler asynq.Handler) (stop func()) {
+	redisOpt, err := utils.GetRedisQueueClientOptionsForAsynq("redis.connections.queue")
+	require.NoError(t, err)
+
+	failedTaskService := deps.App[*services.FailedTaskService]()
+	server := asynq.NewServer(redisOpt, asynq.Config{
+		Concurrency:              1,
+		Queues:                   map[string]int{queue: 1},
+		LogLevel:                 asynq.FatalLevel,
+		DelayedTaskCheckInterval: 100 * time.Millisecond,
+		RetryDelayFunc:           func(n int, err error, task *asynq.Task) time.Duration { return 0 },
+		ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+			assert.NoError(t, failedTaskService.RecordFailure(ctx, task, err))
+		}),
+	})
+	require.NoError(t, server.Start(handler))
+
+	stop = func() { server.Shutdown() }
+	t.Cleanup(func() {
+		stop()
+		cleanupQueue(queue)
+	})
+	return stop
+}
+
+func cleanupQueue(queue string) {
+	_ = deps.Queue().Inspector.DeleteQueue(queue, true)
+}
+
+// waitForFailedTask waits until the task is persisted as a failed task
+func waitForFailedTask(t *testing.T, taskID string) models.FailedTask {
+	var failedTask models.FailedTask
+	require.Eventually(t, func() bool {
+		return deps.Gorm().DB.Where("task_id = ?", taskID).Limit(1).Find(&failedTask).RowsAffected == 1
+	}, 10*time.Second, 50*time.Millisecond, "task %s wasn't persisted as a failed task", taskID)
+	return failedTask
+}
+
+func createArchivedFailedTask(t *testing.T, queue, taskID, payload string) models.FailedTask {
+	failedTask := models.FailedTask{
+		TaskID:          taskID,
+		TaskType:        "deadletter:test",
+		Queue:           queue,
+		Payload:         payload,
+		PayloadEncoding: enums.FailedTaskPayloadJson,
+		LastError:       "failed",
+		ErrorChain:      `["failed"]`,
+		Attempts:        `[]`,
+		MaxRetry:        2,
+		Status:          enums.FailedTaskStatusArchived,
+		FailedAt:        time.Now(),
+	}
+	require.NoError(t, deps.Gorm().DB.Create(&failedTask).Error)
+	return failedTask
+}
+
+func TestFailedTaskService_RecordFailure_PersistsTheTaskOnItsLastAttempt(t *testing.T) {
+	queue := "deadletter-last-attempt"
+
+	var calls atomic.Int32
+	runDeadLetterWorker(t, queue, asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		calls.Add(1)
+		return fmt.Errorf("failed to charge order: %w", errors.New("gateway timeout"))
+	}))
+
+	info, err := deps.Queue().Client.Enqueue(asynq.NewTask("deadletter:test", []byte(`{"order_id":1}`)), asynq.Queue(queue), asynq.MaxRetry(2))
+	require.NoError(t, err)
+
+	failedTask := waitForFailedTask(t, info.ID)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Only the archived task is persisted, not every failed attempt
+	var count int64
+	deps.Gorm().DB.Model(&models.FailedTask{}).Where("task_id = ?", info.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	assert.Equal(t, "deadletter:test", failedTask.TaskType)
+	assert.Equal(t, queue, failedTask.Queue)
+	assert.JSONEq(t, `{"order_id":1}`, failedTask.Payload)
+	assert.Equal(t, enums.FailedTaskPayloadJson, failedTask.PayloadEncoding)
+	assert.Equal(t, enums.FailedTaskStatusArchived, failedTask.Status)
+	assert.Equal(t, 2, failedTask.RetryCount)
+	assert.Equal(t, 2, failedTask.MaxRetry)
+	assert.Equal(t, "failed to charge order: gateway timeout", failedTask.LastError)
+	assert.JSONEq(t, `["failed to charge order: gateway timeout", "gateway timeout"]`, failedTask.ErrorChain)
+	assert.Empty(t, failedTask.ChainID)
+	assert.Nil(t, failedTask.ChainStep)
+
+	// The attempts history holds every failed attempt
+	var attempts []models.FailedTaskAttempt
+	require.NoError(t, json.Unmarshal([]byte(failedTask.Attempts), &attempts))
+	if assert.Len(t, attempts, 3) {
+		for i, attempt := range attempts {
+			assert.Equal(t, i+1, attempt.Attempt)
+			assert.Equal(t, "failed to charge order: gateway timeout", attempt.Error)
+		}
+	}
+
+	truncateTables()
+}
+
+func TestFailedTaskService_RecordFailure_PersistsTheSkippedRetryTask(t *testing.T) {
+	queue := "deadletter-skip-retry"
+
+	var calls atomic.Int32
+	stop := runDeadLetterWorker(t, queue, asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		calls.Add(1)
+		return fmt.Errorf("malformed payload: %w", asynq.SkipRetry)
+	}))
+
+	// The payload isn't a valid json, it's kept as is so it can be replayed
+	rawPayload := []byte("order=1;\xff")
+	info, err := deps.Queue().Client.Enqueue(asynq.NewTask("deadletter:test", rawPayload), asynq.Queue(queue), asynq.MaxRetry(5))
+	require.NoError(t, err)
+
+	failedTask := waitForFailedTask(t, info.ID)
+	stop()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 0, failedTask.RetryCount)
+	assert.Equal(t, 5, failedTask.MaxRetry)
+	assert.Equal(t, enums.FailedTaskPayloadBase64, failedTask.PayloadEncoding)
+
+	payload, err := failedTask.RawPayload()
+	require.NoError(t, err)
+	assert.Equal(t, rawPayload, payload)
+
+	// The replayed task gets the original payload bytes
+	results, err := deps.App[*services.FailedTaskService]().ReplayFailedTasks(context.Background(), []uint{failedTask.ID}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+
+	task, err := deps.Queue().Inspector.GetTaskInfo(queue, info.ID)
+	require.NoError(t, err)
+	assert.Equal(t, asynq.TaskStatePending, task.State)
+	assert.Equal(t, rawPayload, task.Payload)
+
+	truncateTables()
+}
+
+func TestFailedTaskService_ReplayFailedTasks(t *testing.T) {
+	queue := "deadletter-bulk-replay"
+	t.Cleanup(func() { cleanupQueue(queue) })
+	failedTaskService := deps.App[*services.FailedTaskService]()
+	db := deps.Gorm().DB
+
+	first := createArchivedFailedTask(t, queue, "deadletter-bulk-1", `{"order_id":1}`)
+	second := createArchivedFailedTask(t, queue, "deadletter-bulk-2", `{"order_id":2}`)
+	discarded := createArchivedFailedTask(t, queue, "deadletter-bulk-3", `{"order_id":3}`)
+	db.Model(&discarded).Update("status", enums.FailedTaskStatusDiscarded)
+
+	results, err := failedTaskService.ReplayFailedTasks(context.Background(), []uint{first.ID, second.ID, discarded.ID}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// Every task is replayed on its own, a task that can't be replayed doesn't stop the others
+	byID := map[uint]services.ReplayResult{}
+	for _, result := range results {
+		byID[result.ID] = result
+	}
+	assert.Equal(t, services.ReplayResult{ID: first.ID, TaskID: "deadletter-bulk-1"}, byID[first.ID])
+	assert.Equal(t, services.ReplayResult{ID: second.ID, TaskID: "deadletter-bulk-2"}, byID[second.ID])
+	assert.Equal(t, "failed task is discarded and can't be replayed", byID[discarded.ID].Error)
+
+	for _, failedTask := range []models.FailedTask{first, second} {
+		var stored models.FailedTask
+		db.First(&stored, failedTask.ID)
+		assert.Equal(t, enums.FailedTaskStatusReplayed, stored.Status)
+		assert.Equal(t, 1, stored.ReplayCount)
+		assert.Equal(t, failedTask.TaskID, stored.ReplayedTaskID)
+		assert.NotNil(t, stored.ReplayedAt)
+
+		// Replayed with the same task id, queue, retries and payload
+		task, err := deps.Queue().Inspector.GetTaskInfo(queue, failedTask.TaskID)
+		require.NoError(t, err)
+		assert.Equal(t, "deadletter:test", task.Type)
+		assert.Equal(t, 2, task.MaxRetry)
+		assert.JSONEq(t, failedTask.Payload, string(task.Payload))
+	}
+
+	// The replayed tasks can't be replayed twice
+	results, err = failedTaskService.ReplayFailedTasks(context.Background(), []uint{first.ID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "failed task is replayed and can't be replayed", results[0].Error)
+
+	truncateTables()
+}
+
+func TestFailedTaskService_ReplayFailedTasks_EditedPayload(t *testing.T) {
+	queue := "deadletter-edited-replay"
+	t.Cleanup(func() { cleanupQueue(queue) })
+	failedTaskService := deps.App[*services.FailedTaskService]()
+
+	failedTask := createArchivedFailedTask(t, queue, "deadletter-edited-1", `{"order_id":"1"}`)
+	other := createArchivedFailedTask(t, queue, "deadletter-edited-2", `{"order_id":2}`)
+
+	// The payload can only be edited for a single task and must be a valid json
+	_, err := failedTaskService.ReplayFailedTasks(context.Background(), []uint{failedTask.ID, other.ID}, json.RawMessage(`{"order_id":1}`))
+	validationErr, ok := pkgErrors.AsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "Payload can only be edited when replaying a single task", validationErr.Errors["payload"])
+
+	_, err = failedTaskService.ReplayFailedTasks(context.Background(), []uint{failedTask.ID}, json.RawMessage(`{"order_id":`))
+	validationErr, ok = pkgErrors.AsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "Payload must be a valid JSON", validationErr.Errors["payload"])
+
+	results, err := failedTaskService.ReplayFailedTasks(context.Background(), []uint{failedTask.ID}, json.RawMessage(`{"order_id":1}`))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+
+	task, err := deps.Queue().Inspector.GetTaskInfo(queue, failedTask.TaskID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"order_id":1}`, string(task.Payload))
+
+	// The other task is left archived
+	var stored models.FailedTask
+	deps.Gorm().DB.First(&stored, other.ID)
+	assert.Equal(t, enums.FailedTaskStatusArchived, stored.Status)
+
+	truncateTables()
+}
+
+func TestFailedTaskService_DiscardFailedTasks(t *testing.T) {
+	queue := "deadletter-discard"
+	failedTaskService := deps.App[*services.FailedTaskService]()
+
+	stop := runDeadLetterWorker(t, queue, asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		return fmt.Errorf("invalid order: %w", asynq.SkipRetry)
+	}))
+
+	info, err := deps.Queue().Client.Enqueue(asynq.NewTask("deadletter:test", []byte(`{"order_id":1}`)), asynq.Queue(queue), asynq.MaxRetry(3))
+	require.NoError(t, err)
+
+	failedTask := waitForFailedTask(t, info.ID)
+	stop()
+
+	task, err := deps.Queue().Inspector.GetTaskInfo(queue, info.ID)
+	require.NoError(t, err)
+	assert.Equal(t, asynq.TaskStateArchived, task.State)
+
+	results, err := failedTaskService.DiscardFailedTasks(context.Background(), []uint{failedTask.ID})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+
+	// The archived task is removed from the queue
+	_, err = deps.Queue().Inspector.GetTaskInfo(queue, info.ID)
+	assert.ErrorIs(t, err, asynq.ErrTaskNotFound)
+
+	var stored models.FailedTask
+	deps.Gorm().DB.First(&stored, failedTask.ID)
+	assert.Equal(t, enums.FailedTaskStatusDiscarded, stored.Status)
+	assert.NotNil(t, stored.DiscardedAt)
+
+	// The discarded tasks can't be replayed
+	results, err = failedTaskService.ReplayFailedTasks(context.Background(), []uint{failedTask.ID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "failed task is discarded and can't be replayed", results[0].Error)
+
+	truncateTables()
+}
+
+func TestFailedTaskService_ReplaysTheChainFromTheFailedStep(t *testing.T) {
+	queue := "deadletter-chain"
+	client := deps.Queue().Client
+
+	var firstCalls, secondCalls, thirdCalls atomic.Int32
+	var fixed atomic.Bool
+
+	orchestrator := chainq.NewChainOrchestrator(client, adapters.NewLoggerAdapter(deps.Log().Channel("queue_log")))
+	orchestrator.RegisterHandler("deadletter:first", asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		firstCalls.Add(1)
+		return nil
+	}))
+	orchestrator.RegisterHandler("deadletter:second", asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		secondCalls.Add(1)
+		if !fixed.Load() {
+			return errors.New("stock service is down")
+		}
+		return nil
+	}))
+	orchestrator.RegisterHandler("deadletter:third", asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		thirdCalls.Add(1)
+		return nil
+	}))
+
+	runDeadLetterWorker(t, queue, orchestrator)
+
+	chain := chainq.NewChain(client, adapters.NewLoggerAdapter(deps.Log().Channel("queue_log")), &chainq.ChainOptions{DefaultQueue: queue}).
+		MaxRetries(0).
+		Then(deadLetterStep{"deadletter:first"}).
+		Then(deadLetterStep{"deadletter:second"}).
+		Then(deadLetterStep{"deadletter:third"})
+
+	message, err := chain.Build()
+	require.NoError(t, err)
+	_, err = client.Enqueue(message.Task())
+	require.NoError(t, err)
+
+	// The chain is archived on its second step
+	failedTask := waitForFailedTask(t, chainq.StepTaskID(chainRunKey(t, message), 1))
+	assert.Equal(t, chainq.TypeChainOrchestrator, failedTask.TaskType)
+	assert.Equal(t, chain.ID(), failedTask.ChainID)
+	if assert.NotNil(t, failedTask.ChainStep) {
+		assert.Equal(t, 1, *failedTask.ChainStep)
+	}
+	assert.Contains(t, failedTask.LastError, "chain failed at step 2 (deadletter:second): stock service is down")
+
+	// The replayed chain resumes from the failed step, the completed steps don't run again
+	fixed.Store(true)
+	results, err := deps.App[*services.FailedTaskService]().ReplayFailedTasks(context.Background(), []uint{failedTask.ID}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Error)
+
+	require.Eventually(t, func() bool { return thirdCalls.Load() == 1 }, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, int32(1), firstCalls.Load())
+	assert.Equal(t, int32(2), secondCalls.Load())
+
+	truncateTables()
+}
+
+// deadLetterStep is a chain step without a payload
+type deadLetterStep struct{ taskType string }
+
+func (s deadLetterStep) CreateTask() (*asynq.Task, error) { return asynq.NewTask(s.taskType, nil), nil }
+func (s deadLetterStep) GetTaskType() string              { return s.taskType }
+func (s deadLetterStep) GetPayload() any                  { return map[string]any{} }
+
+func chainRunKey(t *testing.T, message *chainq.TaskMessage) string {
+	var payload chainq.ChainPayload
+	require.NoError(t, json.Unmarshal(message.Payload, &payload))
+	return payload.RunKey()
+}
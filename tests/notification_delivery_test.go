@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/services"
+	"taskgo/pkg/notify"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDeliveries_FailedAndResend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := deps.App[*services.NotificationDeliveryService]()
+	handler := deps.App[*handlers.AdminNotificationDeliveryHandler]()
+	db := deps.Gorm().DB
+	ctx := context.Background()
+
+	task := &notify.NotificationTask{
+		ID:               "5f0c4a8e-1d2b-4c3a-9e8f-000000000047",
+		NotificationType: "ReportReadyNotification",
+		NotifiableType:   "User",
+		NotifiableID:     1,
+		Data:             map[string]any{"report_id": 1},
+		Channel:          "email",
+	}
+
+	// Queued, retried then out of retries
+	require.NoError(t, recorder.Queued(ctx, task))
+	require.NoError(t, recorder.Attempted(ctx, task, notify.Attempt{Err: errors.New("smtp timeout")}))
+	require.NoError(t, recorder.Attempted(ctx, task, notify.Attempt{Err: errors.New("smtp refused"), Final: true}))
+
+	var delivery models.NotificationDelivery
+	require.NoError(t, db.Where("notification_id = ? AND channel = ?", task.ID, "email").First(&delivery).Error)
+	assert.Equal(t, enums.NotificationStatusFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, "smtp refused", delivery.LastError)
+	assert.NotNil(t, delivery.FailedAt)
+
+	// Another channel of the same notification is delivered
+	sent := *task
+	sent.Channel = "database"
+	require.NoError(t, recorder.Attempted(ctx, &sent, notify.Attempt{Final: true}))
+
+	// The failed deliveries only
+	var list struct {
+		Data struct {
+			Deliveries []map[string]any `json:"deliveries"`
+		} `json:"data"`
+	}
+	w, c := createTestContext("GET", "/admin/notifications/deliveries?status=failed", nil)
+	middleware.HandleErrors(handler.ListDeliveries)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data.Deliveries, 1)
+	assert.Equal(t, "email", list.Data.Deliveries[0]["channel"])
+
+	resend := func(id uint) int {
+		w, c := createTestContext("POST", fmt.Sprintf("/admin/notifications/deliveries/%d/resend", id), nil)
+		c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(id)}}
+		middleware.HandleErrors(handler.ResendDelivery)(c)
+		return w.Code
+	}
+
+	// Resent to the queue, pending again
+	assert.Equal(t, http.StatusOK, resend(delivery.ID))
+	db.First(&delivery, delivery.ID)
+	assert.Equal(t, enums.NotificationStatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Redeliveries)
+
+	// The pending (or sent) deliveries can't be resent
+	assert.Equal(t, http.StatusUnprocessableEntity, resend(delivery.ID))
+	assert.Equal(t, http.StatusNotFound, resend(delivery.ID+1000))
+}
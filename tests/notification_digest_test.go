@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/notification"
+	"taskgo/pkg/notify"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDigests_RedisBatches(t *testing.T) {
+	ctx := context.Background()
+	client := deps.Cache().Redis
+	store := notify.NewRedisDigestStore(client, "testing:digests:", time.Minute)
+	t.Cleanup(func() {
+		keys, _ := client.Keys(ctx, "testing:digests:*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+	})
+
+	low := func(productID uint, quantity int) map[string]any {
+		return notification.NewLowStockNotification(&models.Inventory{ProductID: productID, Quantity: quantity, ReorderPoint: 5}).Data()
+	}
+
+	// The first entry opens the batch, the next ones join it
+	batch, opened, err := store.Add(ctx, "low_stock:User:1", low(1, 4), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, opened)
+
+	joined, opened, err := store.Add(ctx, "low_stock:User:1", low(2, 3), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, opened)
+	assert.Equal(t, batch, joined)
+
+	_, _, err = store.Add(ctx, "low_stock:User:1", low(1, 2), time.Minute)
+	require.NoError(t, err)
+
+	// Each recipient has its own batch
+	other, opened, err := store.Add(ctx, "low_stock:User:2", low(1, 4), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, opened)
+	assert.NotEqual(t, batch, other)
+
+	// The entries are kept for every channel of the digest
+	entries, err := store.Entries(ctx, batch)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	entries, err = store.Entries(ctx, batch)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	// The summary keeps the latest stock of each product
+	summary := notification.DigestSummarizers()[notification.DigestLowStock](entries)
+	encoded, err := json.Marshal(summary)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Products []struct {
+			ProductID uint `json:"product_id"`
+			Quantity  int  `json:"quantity"`
+		} `json:"products"`
+		ChannelMessages map[string]string `json:"channel_messages"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Len(t, decoded.Products, 2)
+	assert.Equal(t, uint(1), decoded.Products[0].ProductID)
+	assert.Equal(t, 2, decoded.Products[0].Quantity)
+	assert.Contains(t, decoded.ChannelMessages["database"], "2 products are low on stock")
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/notification"
+	"taskgo/internal/services"
+	"taskgo/pkg/notify"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferences_Routing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := deps.App[*handlers.NotificationPreferenceHandler]()
+	router := deps.App[*services.NotificationPreferenceService]()
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Prefs", LastName: "User", Email: "prefs@test.com", Password: "password", PhoneNumber: "01012345681", Role: "customer", IsActive: true}
+	db.Create(&user)
+
+	update := func(notificationType string, body map[string]any) int {
+		w, c := createTestContext("PUT", "/notifications/preferences/"+notificationType, body)
+		c.Set(string(enums.ContextKeyAuthId), fmt.Sprint(user.ID))
+		c.Params = gin.Params{{Key: "type", Value: notificationType}}
+		middleware.HandleErrors(handler.UpdatePreference)(c)
+		return w.Code
+	}
+	channels := func(routes []notify.Route) []string {
+		var names []string
+		for _, route := range routes {
+			names = append(names, route.Channel)
+		}
+		return names
+	}
+
+	orderCreated := notification.NewOrderCreatedNotification(1)
+	all := []string{"database", "ws", "email", "sms"}
+
+	// All the channels by default
+	routes, err := router.Route(context.Background(), orderCreated, &user, all)
+	require.NoError(t, err)
+	assert.Equal(t, all, channels(routes))
+
+	// Disable the order sms
+	assert.Equal(t, http.StatusOK, update("order", map[string]any{"sms": false}))
+	routes, err = router.Route(context.Background(), orderCreated, &user, all)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"database", "ws", "email"}, channels(routes))
+
+	// Quiet hours covering now delay the email only
+	now := time.Now().UTC()
+	assert.Equal(t, http.StatusOK, update("order", map[string]any{
+		"quiet_hours_start": now.Add(-time.Hour).Format("15:04"),
+		"quiet_hours_end":   now.Add(2 * time.Hour).Format("15:04"),
+		"timezone":          "UTC",
+	}))
+	routes, err = router.Route(context.Background(), orderCreated, &user, all)
+	require.NoError(t, err)
+	require.Equal(t, []string{"database", "ws", "email"}, channels(routes))
+	assert.Nil(t, routes[0].At)
+	assert.Nil(t, routes[1].At)
+	require.NotNil(t, routes[2].At)
+	assert.WithinDuration(t, now.Add(2*time.Hour), *routes[2].At, 2*time.Minute)
+
+	// The other types keep their defaults
+	var list struct {
+		Data struct {
+			Preferences []map[string]any `json:"preferences"`
+		} `json:"data"`
+	}
+	w, c := createTestContext("GET", "/notifications/preferences", nil)
+	c.Set(string(enums.ContextKeyAuthId), fmt.Sprint(user.ID))
+	middleware.HandleErrors(handler.ListPreferences)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Data.Preferences, len(enums.NotificationTypes))
+
+	// Invalid changes
+	assert.Equal(t, http.StatusUnprocessableEntity, update("security", map[string]any{"email": false}))
+	assert.Equal(t, http.StatusNotFound, update("unknown", map[string]any{"email": false}))
+	assert.Equal(t, http.StatusUnprocessableEntity, update("payment", map[string]any{"quiet_hours_start": "22:00"}))
+	assert.Equal(t, http.StatusUnprocessableEntity, update("payment", map[string]any{"timezone": "Mars/Base"}))
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserNotificationHandler_Inbox(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := deps.App[*handlers.UserNotificationHandler]()
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Inbox", LastName: "User", Email: "inbox@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	other := models.User{FirstName: "Other", LastName: "User", Email: "other@test.com", Password: "password", PhoneNumber: "01012345679", Role: "customer", IsActive: true}
+	db.Create(&user)
+	db.Create(&other)
+
+	readAt := time.Now()
+	first := models.Notification{Type: "OrderCreatedNotification", Data: `{"order_id": 1}`, NotifiableID: user.ID, NotifiableType: "User"}
+	second := models.Notification{Type: "OrderCreatedNotification", Data: `{"order_id": 2}`, NotifiableID: user.ID, NotifiableType: "User"}
+	read := models.Notification{Type: "OrderCreatedNotification", Data: `{"order_id": 3}`, NotifiableID: user.ID, NotifiableType: "User", ReadAt: &readAt}
+	othersNotification := models.Notification{Type: "OrderCreatedNotification", Data: `{"order_id": 4}`, NotifiableID: other.ID, NotifiableType: "User"}
+	db.Create(&first)
+	db.Create(&second)
+	db.Create(&read)
+	db.Create(&othersNotification)
+
+	authContext := func(method, url string, params ...gin.Param) (*httptest.ResponseRecorder, *gin.Context) {
+		w, c := createTestContext(method, url, nil)
+		c.Set(string(enums.ContextKeyAuthId), fmt.Sprint(user.ID))
+		c.Params = params
+		return w, c
+	}
+
+	var list struct {
+		Data struct {
+			Notifications []map[string]any `json:"notifications"`
+			UnreadCount   int64            `json:"unread_count"`
+		} `json:"data"`
+	}
+
+	// The unread notifications of the user only
+	w, c := authContext("GET", "/notifications?unread=true")
+	middleware.HandleErrors(handler.ListNotifications)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Data.Notifications, 2)
+	assert.Equal(t, int64(2), list.Data.UnreadCount)
+	assert.Equal(t, float64(second.ID), list.Data.Notifications[0]["id"])
+
+	// Mark one read
+	w, c = authContext("PUT", fmt.Sprintf("/notifications/%d/read", first.ID), gin.Param{Key: "id", Value: fmt.Sprint(first.ID)})
+	middleware.HandleErrors(handler.MarkNotificationRead)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var reloaded models.Notification
+	db.First(&reloaded, first.ID)
+	assert.NotNil(t, reloaded.ReadAt)
+
+	// The other user notifications can't be read
+	w, c = authContext("PUT", fmt.Sprintf("/notifications/%d/read", othersNotification.ID), gin.Param{Key: "id", Value: fmt.Sprint(othersNotification.ID)})
+	middleware.HandleErrors(handler.MarkNotificationRead)(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Mark all read
+	w, c = authContext("PUT", "/notifications/read-all")
+	middleware.HandleErrors(handler.MarkAllNotificationsRead)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var unread struct {
+		Data struct {
+			UnreadCount int64 `json:"unread_count"`
+		} `json:"data"`
+	}
+	w, c = authContext("GET", "/notifications/unread-count")
+	middleware.HandleErrors(handler.UnreadCount)(c)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &unread))
+	assert.Equal(t, int64(0), unread.Data.UnreadCount)
+
+	// Delete
+	w, c = authContext("DELETE", fmt.Sprintf("/notifications/%d", read.ID), gin.Param{Key: "id", Value: fmt.Sprint(read.ID)})
+	middleware.HandleErrors(handler.DeleteNotification)(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	db.Model(&models.Notification{}).Where("notifiable_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	truncateTables()
+}
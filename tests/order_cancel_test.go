@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	chainq "taskgo/pkg/asynq_chain"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHandler_CancelOrderCancelsItsChain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := deps.App[*handlers.OrderHandler]()
+	chainService := deps.App[*services.ChainService]()
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Cancel", LastName: "User", Email: "cancel@test.com", Password: "password", PhoneNumber: "01012345691", Role: "customer", IsActive: true}
+	db.Create(&user)
+	other := models.User{FirstName: "Other", LastName: "User", Email: "cancel-other@test.com", Password: "password", PhoneNumber: "01012345692", Role: "customer", IsActive: true}
+	db.Create(&other)
+
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusPending, TotalAmount: 100, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	db.Create(&order)
+
+	cancel := func(userID uint) int {
+		w, c := createTestContext("PUT", fmt.Sprintf("/orders/%d/cancel", order.ID), nil)
+		c.Set(string(enums.ContextKeyAuthId), fmt.Sprint(userID))
+		c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(order.ID)}}
+		middleware.HandleErrors(handler.CancelOrder)(c)
+		return w.Code
+	}
+
+	// The orders of the other users are not found
+	assert.Equal(t, http.StatusNotFound, cancel(other.ID))
+
+	// Cancelled with its processing chain (the payment step will not run)
+	assert.Equal(t, http.StatusOK, cancel(user.ID))
+	db.First(&order, order.ID)
+	assert.Equal(t, enums.OrderStatusCancelled, order.Status)
+
+	state, _, err := chainService.GetChainState(context.Background(), tasks.OrderProcessingChainID(order.ID))
+	require.NoError(t, err)
+	assert.Equal(t, chainq.ChainStateCancelled, state)
+}
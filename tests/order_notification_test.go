@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/notification"
+	"taskgo/internal/notification/handlers"
+	"taskgo/pkg/mail"
+	"taskgo/pkg/notify"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStatusNotifications(t *testing.T) {
+	order := &models.Order{Base: models.Base{ID: 49}, UserID: 1, Status: enums.OrderStatusShipped, TotalAmount: 120.5, TrackingNumber: "TRN-49"}
+
+	cases := []struct {
+		status   enums.OrderStatus
+		reason   string
+		expected string
+		sms      bool
+	}{
+		{enums.OrderStatusConfirmed, "payment captured", "OrderConfirmedNotification", false},
+		{enums.OrderStatusShipped, "", "OrderShippedNotification", true},
+		{enums.OrderStatusDelivered, "", "OrderDeliveredNotification", true},
+		{enums.OrderStatusCancelled, "out of stock", "OrderCancelledNotification", false},
+		{enums.OrderStatusCancelled, notification.PaymentFailedReason, "OrderPaymentFailedNotification", true},
+		{enums.OrderStatusRefunded, "", "OrderRefundedNotification", false},
+	}
+	for _, tc := range cases {
+		n := notification.NewOrderStatusNotification(order, tc.status, tc.reason)
+		require.NotNil(t, n, tc.status)
+		assert.Equal(t, tc.expected, reflect.TypeOf(n).Elem().Name())
+		assert.Equal(t, tc.sms, slices.Contains(n.Channels(), "sms"), tc.expected)
+
+		messages := n.Data()["channel_messages"].(map[string]string)
+		for _, channel := range n.Channels() {
+			assert.NotEmpty(t, messages[channel], "%s %s message", tc.expected, channel)
+		}
+	}
+
+	// The processing orders are not notified
+	assert.Nil(t, notification.NewOrderStatusNotification(order, enums.OrderStatusProcessing, ""))
+
+	// The shipped notification carries the tracking number
+	shipped := notification.NewOrderStatusNotification(order, enums.OrderStatusShipped, "").Data()
+	assert.Equal(t, "TRN-49", shipped["tracking_number"])
+	assert.Contains(t, shipped["channel_messages"].(map[string]string)["sms"], "TRN-49")
+}
+
+func TestOrderStatusNotifications_ShippedEmail(t *testing.T) {
+	db := deps.Gorm().DB
+	outbox, ok := deps.Mailer().Transport().(*mail.MemoryTransport)
+	require.True(t, ok, "the testing mailer should use the memory transport")
+	outbox.Reset()
+
+	user := models.User{FirstName: "Ship", LastName: "User", Email: "ship@test.com", Password: "password", PhoneNumber: "01012345690", Role: "customer", IsActive: true}
+	db.Create(&user)
+	order := &models.Order{Base: models.Base{ID: 490}, UserID: user.ID, Status: enums.OrderStatusShipped, TrackingNumber: "TRN-490"}
+
+	// The queued notification data is decoded from json
+	var data map[string]any
+	encoded, _ := json.Marshal(notification.NewOrderStatusNotification(order, enums.OrderStatusShipped, "").Data())
+	require.NoError(t, json.Unmarshal(encoded, &data))
+
+	task := &notify.NotificationTask{
+		NotificationType: "OrderShippedNotification",
+		NotifiableType:   notify.NotifiableType(&user),
+		NotifiableID:     user.ID,
+		Data:             data,
+		Channel:          string(enums.NotificationChannelEmail),
+	}
+	require.NoError(t, handlers.EmailChannelHandler(context.Background(), task))
+
+	sent := outbox.Outbox()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "Your order #490 is on its way", sent[0].Subject)
+	assert.Contains(t, sent[0].Text, "TRN-490")
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/notification"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	chainq "taskgo/pkg/asynq_chain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type declinedPaymentGateway struct{}
+
+func (declinedPaymentGateway) Charge(ctx context.Context, order *models.Order, idempotencyKey string) error {
+	return errors.New("card declined")
+}
+
+func TestProcessPaymentHandler_CancelsTheOrderWhenThePaymentFails(t *testing.T) {
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Payment", LastName: "User", Email: "payment@test.com", Password: "password", PhoneNumber: "01012345693", Role: "customer", IsActive: true}
+	db.Create(&user)
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusPending, TotalAmount: 100, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	db.Create(&order)
+
+	// Capture the customer notification of the order status changes
+	var (
+		mu       sync.Mutex
+		notified []string
+	)
+	deps.App[*services.OrderStateMachine]().OnTransition(func(ctx context.Context, change services.OrderStatusChange) error {
+		if change.Order.ID != order.ID {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if n := notification.NewOrderStatusNotification(change.Order, change.To, change.Reason); n != nil {
+			notified = append(notified, reflect.TypeOf(n).Elem().Name())
+		}
+		return nil
+	})
+
+	handler := tasks.NewProcessPaymentHandler(
+		deps.App[*services.OrderService](),
+		deps.App[*repository.OrderRepository](),
+		declinedPaymentGateway{},
+	)
+
+	// Outside of a worker the first attempt is the last one
+	task, err := tasks.NewProcessPaymentTask(order.ID).CreateTask()
+	require.NoError(t, err)
+	assert.ErrorContains(t, handler.ProcessTask(context.Background(), task), "card declined")
+
+	db.First(&order, order.ID)
+	assert.Equal(t, enums.OrderStatusCancelled, order.Status)
+
+	mu.Lock()
+	assert.Equal(t, []string{"OrderPaymentFailedNotification"}, notified)
+	mu.Unlock()
+
+	// The remaining steps of the order chain are dropped
+	state, _, err := deps.App[*services.ChainService]().GetChainState(context.Background(), tasks.OrderProcessingChainID(order.ID))
+	require.NoError(t, err)
+	assert.Equal(t, chainq.ChainStateCancelled, state)
+
+	truncateTables()
+}
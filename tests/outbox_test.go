@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"taskgo/internal/api/requests"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	chainq "taskgo/pkg/asynq_chain"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createOutboxOrderRequest(t *testing.T) *requests.CreateOrderRequest {
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Outbox", LastName: "User", Email: "outbox@test.com", Password: "password", PhoneNumber: "01012345694", Role: "customer", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	product := models.Product{Name: "Phone", Price: 100}
+	require.NoError(t, db.Create(&product).Error)
+	require.NoError(t, db.Create(&models.Inventory{ProductID: product.ID, Quantity: 10}).Error)
+
+	return &requests.CreateOrderRequest{
+		UserId:          user.ID,
+		Items:           []requests.OrderItemRequest{{ProductId: product.ID, Quantity: 1}},
+		ShippingAddress: "Cairo, Egypt",
+		BillingAddress:  "Cairo, Egypt",
+		PaymentMethod:   "cash_on_delivery",
+	}
+}
+
+// addOutboxMessage writes a message to the outbox like the services do in their transactions
+func addOutboxMessage(t *testing.T, queue, taskID string) *models.OutboxMessage {
+	var message *models.OutboxMessage
+	err := deps.Gorm().DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		message, err = deps.App[*services.OutboxService]().Add(tx, &chainq.TaskMessage{
+			Type:     "outbox:test",
+			Payload:  []byte(fmt.Sprintf(`{"task_id":%q}`, taskID)),
+			Queue:    queue,
+			TaskID:   taskID,
+			MaxRetry: 3,
+		})
+		return err
+	})
+	require.NoError(t, err)
+	return message
+}
+
+func findOutboxMessage(id uint) models.OutboxMessage {
+	var message models.OutboxMessage
+	deps.Gorm().DB.First(&message, id)
+	return message
+}
+
+func TestOrderService_CreateOrder_WritesTheTaskToTheOutbox(t *testing.T) {
+	queue := "outbox-order"
+	t.Cleanup(func() { cleanupQueue(queue) })
+
+	var taskID string
+	order, err := deps.App[*services.OrderService]().CreateOrder(context.Background(), createOutboxOrderRequest(t), func(order *models.Order) (*chainq.TaskMessage, error) {
+		// The order is already written in the transaction
+		require.NotZero(t, order.ID)
+		taskID = fmt.Sprintf("outbox-order:%d", order.ID)
+		return &chainq.TaskMessage{Type: "outbox:order", Payload: []byte(`{}`), Queue: queue, TaskID: taskID}, nil
+	})
+	require.NoError(t, err)
+
+	var message models.OutboxMessage
+	require.NoError(t, deps.Gorm().DB.Where("task_id = ?", taskID).First(&message).Error)
+	assert.Equal(t, "outbox:order", message.TaskType)
+
+	// The message is enqueued right after the commit
+	assert.Equal(t, enums.OutboxStatusDispatched, message.Status)
+	assert.Equal(t, 1, message.Attempts)
+	assert.NotNil(t, message.DispatchedAt)
+
+	task, err := deps.Queue().Inspector.GetTaskInfo(queue, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, "outbox:order", task.Type)
+	assert.Equal(t, fmt.Sprintf("outbox-order:%d", order.ID), task.ID)
+
+	truncateTables()
+}
+
+func TestOrderService_CreateOrder_RollsBackTheOrderWithItsTask(t *testing.T) {
+	db := deps.Gorm().DB
+
+	_, err := deps.App[*services.OrderService]().CreateOrder(context.Background(), createOutboxOrderRequest(t), func(order *models.Order) (*chainq.TaskMessage, error) {
+		return nil, errors.New("failed to build the order chain")
+	})
+	assert.Error(t, err)
+
+	// Neither the order nor its task is written
+	var orders, messages int64
+	db.Model(&models.Order{}).Count(&orders)
+	db.Model(&models.OutboxMessage{}).Count(&messages)
+	assert.Zero(t, orders)
+	assert.Zero(t, messages)
+
+	truncateTables()
+}
+
+func TestOutboxService_Relay_DispatchesTheDueMessages(t *testing.T) {
+	queue := "outbox-relay"
+	t.Cleanup(func() { cleanupQueue(queue) })
+	db := deps.Gorm().DB
+	outboxService := deps.App[*services.OutboxService]()
+
+	pending := addOutboxMessage(t, queue, "outbox-relay-1")
+
+	notDue := addOutboxMessage(t, queue, "outbox-relay-2")
+	db.Model(notDue).Update("available_at", time.Now().Add(time.Hour))
+
+	// Claimed by another relay that is still enqueuing it
+	claimed := addOutboxMessage(t, queue, "outbox-relay-3")
+	db.Model(claimed).Updates(map[string]any{"status": enums.OutboxStatusDispatching, "available_at": time.Now().Add(time.Minute)})
+
+	// Claimed by a relay that crashed before enqueuing it (lease expired)
+	expired := addOutboxMessage(t, queue, "outbox-relay-4")
+	db.Model(expired).Updates(map[string]any{"status": enums.OutboxStatusDispatching, "available_at": time.Now().Add(-time.Second)})
+
+	dispatched, err := outboxService.Relay(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dispatched)
+
+	for _, message := range []*models.OutboxMessage{pending, expired} {
+		stored := findOutboxMessage(message.ID)
+		assert.Equal(t, enums.OutboxStatusDispatched, stored.Status)
+		assert.NotNil(t, stored.DispatchedAt)
+
+		task, err := deps.Queue().Inspector.GetTaskInfo(queue, message.TaskID)
+		require.NoError(t, err)
+		assert.Equal(t, "outbox:test", task.Type)
+		assert.Equal(t, 3, task.MaxRetry)
+		assert.Equal(t, message.Payload, task.Payload)
+	}
+
+	assert.Equal(t, enums.OutboxStatusPending, findOutboxMessage(notDue.ID).Status)
+	assert.Equal(t, enums.OutboxStatusDispatching, findOutboxMessage(claimed.ID).Status)
+
+	_, err = deps.Queue().Inspector.GetTaskInfo(queue, claimed.TaskID)
+	assert.ErrorIs(t, err, asynq.ErrTaskNotFound)
+
+	truncateTables()
+}
+
+func TestOutboxService_Relay_RetriesTheFailedEnqueues(t *testing.T) {
+	queue := "outbox-retry"
+	t.Cleanup(func() { cleanupQueue(queue) })
+	db := deps.Gorm().DB
+
+	// The queue is down
+	downClient := asynq.NewClient(asynq.RedisClientOpt{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer downClient.Close()
+	downService := services.NewOutboxService(deps.App[*repository.OutboxRepository](), downClient, 2, time.Minute, time.Minute)
+
+	message := addOutboxMessage(t, queue, "outbox-retry-1")
+
+	dispatched, err := downService.Relay(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, dispatched)
+
+	// The failure is recorded and the message is released for a later attempt
+	stored := findOutboxMessage(message.ID)
+	assert.Equal(t, enums.OutboxStatusPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.NotEmpty(t, stored.LastError)
+	assert.True(t, stored.AvailableAt.After(time.Now().Add(50*time.Second)))
+
+	// It isn't retried before its retry delay
+	dispatched, _ = downService.Relay(context.Background(), 10)
+	assert.Zero(t, dispatched)
+	assert.Equal(t, 1, findOutboxMessage(message.ID).Attempts)
+
+	// The queue is back
+	db.Model(message).Update("available_at", time.Now())
+	dispatched, err = deps.App[*services.OutboxService]().Relay(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	stored = findOutboxMessage(message.ID)
+	assert.Equal(t, enums.OutboxStatusDispatched, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.Empty(t, stored.LastError)
+
+	_, err = deps.Queue().Inspector.GetTaskInfo(queue, message.TaskID)
+	assert.NoError(t, err)
+
+	// The message is marked as failed after the max attempts
+	failed := addOutboxMessage(t, queue, "outbox-retry-2")
+	for range 2 {
+		db.Model(failed).Update("available_at", time.Now())
+		_, err = downService.Relay(context.Background(), 10)
+		require.NoError(t, err)
+	}
+
+	stored = findOutboxMessage(failed.ID)
+	assert.Equal(t, enums.OutboxStatusFailed, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+
+	truncateTables()
+}
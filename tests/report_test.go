@@ -0,0 +1,304 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/filters"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedSalesReportOrders creates the orders of the 2026-03-01 -> 2026-03-07 sales report
+func seedSalesReportOrders() {
+	db := deps.Gorm().DB
+
+	user := models.User{FirstName: "Report", LastName: "User", Email: "report@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+
+	phone := models.Product{Name: "Phone", Price: 100, Category: "phones"}
+	cable := models.Product{Name: "Cable", Price: 50}
+	db.Create(&phone)
+	db.Create(&cable)
+
+	createOrder := func(status enums.OrderStatus, createdAt time.Time, product models.Product, quantity int) {
+		total := product.Price * float64(quantity)
+		order := models.Order{UserID: user.ID, Status: status, TotalAmount: total, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+		order.CreatedAt = createdAt
+		db.Create(&order)
+		db.Create(&models.OrderItem{OrderID: order.ID, ProductID: product.ID, Quantity: quantity, UnitPrice: product.Price, TotalPrice: total})
+	}
+
+	createOrder(enums.OrderStatusDelivered, time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), phone, 2)
+	createOrder(enums.OrderStatusConfirmed, time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC), cable, 1)
+	createOrder(enums.OrderStatusCancelled, time.Date(2026, 3, 3, 11, 0, 0, 0, time.UTC), phone, 1)
+	createOrder(enums.OrderStatusRefunded, time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), phone, 1)
+	createOrder(enums.OrderStatusDelivered, time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC), phone, 5) // out of range
+}
+
+// The report runs over the raw orders (the rollups are disabled by default)
+func TestAdminOrderHandler_SalesReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := deps.App[*handlers.AdminOrderHandler]()
+	seedSalesReportOrders()
+
+	w, c := createTestContext("GET", "/admin/reports/daily?from=2026-03-01&to=2026-03-07&group_by=day&timezone=UTC", nil)
+	middleware.HandleErrors(handler.DailySalesReport)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Report struct {
+				Summary struct {
+					Revenue           float64 `json:"revenue"`
+					OrdersCount       int64   `json:"orders_count"`
+					AverageOrderValue float64 `json:"average_order_value"`
+					UnitsSold         int64   `json:"units_sold"`
+					CancelledCount    int64   `json:"cancelled_count"`
+					RefundedCount     int64   `json:"refunded_count"`
+					RefundedAmount    float64 `json:"refunded_amount"`
+				} `json:"summary"`
+				Periods    []map[string]any `json:"periods"`
+				Categories []map[string]any `json:"categories"`
+			} `json:"report"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	report := response.Data.Report
+	assert.Equal(t, 250.0, report.Summary.Revenue)
+	assert.Equal(t, int64(2), report.Summary.OrdersCount)
+	assert.Equal(t, 125.0, report.Summary.AverageOrderValue)
+	assert.Equal(t, int64(3), report.Summary.UnitsSold)
+	assert.Equal(t, int64(1), report.Summary.CancelledCount)
+	assert.Equal(t, int64(1), report.Summary.RefundedCount)
+	assert.Equal(t, 100.0, report.Summary.RefundedAmount)
+	assert.Len(t, report.Periods, 7)
+	assert.Len(t, report.Categories, 2)
+	assert.Equal(t, "phones", report.Categories[0]["category"])
+
+	truncateTables()
+}
+
+// The report read from the rebuilt rollups matches the raw orders one
+func TestReportService_SalesReportFromRollups(t *testing.T) {
+	seedSalesReportOrders()
+	ctx := context.Background()
+	assert.NoError(t, deps.App[*services.SalesRollupService]().Rebuild(ctx, nil))
+
+	newReportService := func(useRollups bool) *services.ReportService {
+		return services.NewReportService(
+			deps.App[*repository.ReportRepository](),
+			deps.App[*repository.ReportJobRepository](),
+			deps.App[*repository.SalesRollupRepository](),
+			nil,
+			nil,
+			"UTC",
+			366,
+			useRollups,
+		)
+	}
+
+	reportFilters := filters.SalesReportFilters{From: "2026-03-01", To: "2026-03-07", GroupBy: "day", Timezone: "UTC"}
+	fromOrders, err := newReportService(false).SalesReport(ctx, &reportFilters)
+	assert.NoError(t, err)
+	fromRollups, err := newReportService(true).SalesReport(ctx, &reportFilters)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 250.0, fromRollups.Summary.Revenue)
+	assert.Equal(t, fromOrders.Summary, fromRollups.Summary)
+	assert.Equal(t, fromOrders.Periods, fromRollups.Periods)
+	assert.Equal(t, fromOrders.Categories, fromRollups.Categories)
+
+	truncateTables()
+}
+
+func TestAdminOrderHandler_SalesReport_InvalidGroupBy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := deps.App[*handlers.AdminOrderHandler]()
+
+	w, c := createTestContext("GET", "/admin/reports/daily?group_by=year", nil)
+	middleware.HandleErrors(handler.DailySalesReport)(c)
+
+	assertValidationError(t, w, map[string]string{
+		"group_by": "Group by must be one of day, week, month",
+	})
+}
+
+func TestSalesRollupService_ApplyOrder(t *testing.T) {
+	rollupService := deps.App[*services.SalesRollupService]()
+	db := deps.Gorm().DB
+	ctx := context.Background()
+
+	user := models.User{FirstName: "Rollup", LastName: "User", Email: "rollup@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+
+	product := models.Product{Name: "Phone", Price: 100, Category: "phones"}
+	db.Create(&product)
+
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusConfirmed, TotalAmount: 200, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	order.CreatedAt = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	db.Create(&order)
+	db.Create(&models.OrderItem{OrderID: order.ID, ProductID: product.ID, Quantity: 2, UnitPrice: 100, TotalPrice: 200})
+
+	day := func() (total models.SalesDailyTotal, productRow models.SalesDailyProduct) {
+		db.Where("day = ?", "2026-03-02").First(&total)
+		db.Where("day = ? AND product_id = ?", "2026-03-02", product.ID).First(&productRow)
+		return total, productRow
+	}
+
+	// Applying the order twice counts it once
+	assert.NoError(t, rollupService.ApplyOrder(ctx, order.ID))
+	assert.NoError(t, rollupService.ApplyOrder(ctx, order.ID))
+
+	total, productRow := day()
+	assert.Equal(t, 200.0, total.Revenue)
+	assert.Equal(t, int64(1), total.OrdersCount)
+	assert.Equal(t, int64(2), total.UnitsSold)
+	assert.Equal(t, int64(2), productRow.Units)
+	assert.Equal(t, "phones", productRow.Category)
+
+	// The refunded order is moved from the sold totals to the refunds
+	db.Model(&order).Update("status", enums.OrderStatusRefunded)
+	assert.NoError(t, rollupService.ApplyOrder(ctx, order.ID))
+	assert.NoError(t, rollupService.ApplyOrder(ctx, order.ID))
+
+	total, productRow = day()
+	assert.Equal(t, 0.0, total.Revenue)
+	assert.Equal(t, int64(0), total.OrdersCount)
+	assert.Equal(t, int64(0), total.UnitsSold)
+	assert.Equal(t, int64(1), total.RefundedCount)
+	assert.Equal(t, 200.0, total.RefundedAmount)
+	assert.Equal(t, int64(0), productRow.Units)
+
+	truncateTables()
+}
+
+func TestOrderService_UpdateStatus_AppliesTheSalesRollupsThroughTheOutbox(t *testing.T) {
+	db := deps.Gorm().DB
+	ctx := context.Background()
+
+	user := models.User{FirstName: "Rollup", LastName: "User", Email: "rollup@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+	product := models.Product{Name: "Phone", Price: 100, Category: "phones"}
+	db.Create(&product)
+
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusPending, TotalAmount: 200, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	db.Create(&order)
+	db.Create(&models.OrderItem{OrderID: order.ID, ProductID: product.ID, Quantity: 2, UnitPrice: 100, TotalPrice: 200})
+
+	_, err := deps.App[*services.OrderService]().UpdateStatus(ctx, order.ID, enums.OrderStatusConfirmed, "")
+	require.NoError(t, err)
+
+	// The rollup task is written with the status change and left to the relay
+	var message models.OutboxMessage
+	require.NoError(t, db.Where("task_type = ?", tasks.TypeApplySalesRollup).First(&message).Error)
+	assert.Equal(t, tasks.QueueLow, message.Queue)
+
+	handler := deps.App[*tasks.ApplySalesRollupHandler]()
+	require.NoError(t, handler.ProcessTask(ctx, asynq.NewTask(message.TaskType, message.Payload)))
+
+	location, err := time.LoadLocation(deps.App[*services.SalesRollupService]().Timezone())
+	require.NoError(t, err)
+
+	var total models.SalesDailyTotal
+	db.Where("day = ?", order.CreatedAt.In(location).Format("2006-01-02")).First(&total)
+	assert.Equal(t, 200.0, total.Revenue)
+	assert.Equal(t, int64(1), total.OrdersCount)
+
+	truncateTables()
+}
+
+func TestOrderService_UpdateStatus_RollsBackTheStatusWhenATxHookFails(t *testing.T) {
+	db := deps.Gorm().DB
+
+	stateMachine := services.NewOrderStateMachine()
+	stateMachine.OnEnterTx(enums.OrderStatusConfirmed, func(ctx context.Context, tx *gorm.DB, change services.OrderStatusChange) error {
+		message, err := tasks.NewApplySalesRollupTask(change.Order.ID).Message()
+		if err != nil {
+			return err
+		}
+		if _, err := deps.App[*services.OutboxService]().Add(tx, message); err != nil {
+			return err
+		}
+		return errors.New("outbox is unavailable")
+	})
+
+	orderService := services.NewOrderService(
+		deps.App[*services.InventoryService](),
+		deps.App[*services.OutboxService](),
+		deps.App[*services.AdminNotificationService](),
+		deps.App[*services.OrderNotificationService](),
+		stateMachine,
+		deps.App[*repository.OrderRepository](),
+		deps.App[*repository.ProductRepository](),
+	)
+
+	user := models.User{FirstName: "Rollup", LastName: "User", Email: "rollup@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusPending, TotalAmount: 200, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	db.Create(&order)
+
+	_, err := orderService.UpdateStatus(context.Background(), order.ID, enums.OrderStatusConfirmed, "")
+	assert.Error(t, err)
+
+	// Neither the status nor the task written by the hook is committed
+	db.First(&order, order.ID)
+	assert.Equal(t, enums.OrderStatusPending, order.Status)
+
+	var messages int64
+	db.Model(&models.OutboxMessage{}).Count(&messages)
+	assert.Zero(t, messages)
+
+	truncateTables()
+}
+
+func TestNightlyReportHandler_QueuesTheReportOfEveryActiveAdmin(t *testing.T) {
+	handler := deps.App[*tasks.NightlyReportHandler]()
+	db := deps.Gorm().DB
+
+	admin := models.User{FirstName: "Nightly", LastName: "Admin", Email: "nightly@test.com", Password: "password", PhoneNumber: "01012345678", Role: enums.RoleAdmin, IsActive: true}
+	inactive := models.User{FirstName: "Inactive", LastName: "Admin", Email: "inactive@test.com", Password: "password", PhoneNumber: "01012345679", Role: enums.RoleAdmin}
+	db.Create(&admin)
+	db.Create(&inactive)
+	db.Model(&inactive).Update("is_active", false)
+
+	task, err := tasks.NewNightlyReportTask("csv").CreateTask()
+	assert.NoError(t, err)
+	assert.NoError(t, handler.ProcessTask(context.Background(), task))
+
+	var jobs []models.ReportJob
+	db.Find(&jobs)
+	if assert.Len(t, jobs, 1) {
+		assert.Equal(t, admin.ID, jobs[0].UserID)
+		assert.Equal(t, enums.ReportFormatCSV, jobs[0].Format)
+
+		var params struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(jobs[0].Params), &params))
+
+		yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+		assert.Equal(t, yesterday, params.From)
+		assert.Equal(t, yesterday, params.To)
+	}
+
+	truncateTables()
+}
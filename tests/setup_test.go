@@ -27,6 +27,9 @@ func TestingSetup() {
 		LoadValidator().
 		LoadRedisCache().
 		LoadRedisQueue().
+		LoadMailer().
+		LoadSMS().
+		LoadNotify().
 		Boot()
 
 	// Run database migrations for testing
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"taskgo/internal/api/handlers"
+	"taskgo/internal/api/middleware"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	notificationHandlers "taskgo/internal/notification/handlers"
+	"taskgo/pkg/notify"
+	"taskgo/pkg/sms"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSChannelHandler_DeliveryStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := deps.Gorm().DB
+	provider, ok := deps.SMS().Provider().(*sms.FakeProvider)
+	require.True(t, ok, "the testing sms client should use the fake provider")
+	provider.Reset()
+
+	user := models.User{FirstName: "Sms", LastName: "User", Email: "sms@test.com", Password: "password", PhoneNumber: "01112345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+
+	task := &notify.NotificationTask{
+		NotificationType: "OrderShippedNotification",
+		NotifiableType:   notify.NotifiableType(&user),
+		NotifiableID:     user.ID,
+		Data: map[string]any{
+			"order_id":         1,
+			"channel_messages": map[string]any{"sms": "Your order #1 has been shipped"},
+		},
+		Channel: string(enums.NotificationChannelSMS),
+	}
+	require.NoError(t, notificationHandlers.SMSChannelHandler(context.Background(), task))
+
+	sent := provider.Messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "+201112345678", sent[0].To)
+	assert.Equal(t, "Your order #1 has been shipped", sent[0].Body)
+
+	var message models.SmsMessage
+	require.NoError(t, db.Where("notifiable_id = ?", user.ID).First(&message).Error)
+	assert.Equal(t, enums.NotificationStatusSent, message.Status)
+	assert.Equal(t, "fake", message.Provider)
+	assert.Equal(t, 1, message.Segments)
+
+	handler := deps.App[*handlers.SmsCallbackHandler]()
+	callback := func(token string, body any) int {
+		w, c := createTestContext("POST", "/callbacks/sms/status", body)
+		c.Request.Header.Set("X-Callback-Token", token)
+		middleware.HandleErrors(handler.StatusCallback)(c)
+		return w.Code
+	}
+
+	// The token is required
+	assert.Equal(t, http.StatusUnauthorized, callback("wrong", map[string]any{"message_id": message.ProviderMessageID, "status": "delivered"}))
+
+	assert.Equal(t, http.StatusOK, callback("testing-sms-token", map[string]any{"message_id": message.ProviderMessageID, "status": "delivered"}))
+	db.First(&message, message.ID)
+	assert.Equal(t, enums.NotificationStatusDelivered, message.Status)
+	assert.NotNil(t, message.DeliveredAt)
+
+	// A late failure report doesn't move the delivered message back
+	assert.Equal(t, http.StatusOK, callback("testing-sms-token", map[string]any{"message_id": message.ProviderMessageID, "status": "failed", "error": "late"}))
+	db.First(&message, message.ID)
+	assert.Equal(t, enums.NotificationStatusDelivered, message.Status)
+
+	// The invalid stored numbers are not retried
+	db.Model(&user).Update("phone_number", "12345")
+	assert.Error(t, notificationHandlers.SMSChannelHandler(context.Background(), task))
+	assert.Len(t, provider.Messages(), 1)
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"taskgo/internal/database/models"
+	"taskgo/internal/deps"
+	"taskgo/internal/enums"
+	"taskgo/internal/repository"
+	"taskgo/internal/services"
+	"taskgo/internal/tasks"
+	"taskgo/pkg/webhook"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_Deliver_DisablesTheFailingSubscription(t *testing.T) {
+	db := deps.Gorm().DB
+	webhookRepo := deps.App[*repository.WebhookRepository]()
+	webhookService := services.NewWebhookService(webhookRepo, webhook.NewSender(5*time.Second, "taskgo-test"), 3)
+	ctx := context.Background()
+
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("receiver is down"))
+	}))
+	defer receiver.Close()
+
+	subscription := models.WebhookSubscription{Name: "Partner", URL: receiver.URL, Secret: "secret", Events: `["order.*"]`, Active: true}
+	db.Create(&subscription)
+
+	message := func(attempt int) webhook.Message {
+		return webhook.Message{ID: "event-1", Event: "order.shipped", Attempt: attempt, Body: []byte(`{"id":"event-1"}`)}
+	}
+
+	// Every failed attempt is recorded and counted
+	for attempt := 1; attempt <= 2; attempt++ {
+		assert.Error(t, webhookService.Deliver(ctx, subscription.ID, message(attempt)))
+	}
+
+	var stored models.WebhookSubscription
+	db.First(&stored, subscription.ID)
+	assert.Equal(t, 2, stored.FailureCount)
+	assert.True(t, stored.Active)
+
+	// The subscription is disabled on the max failures
+	assert.Error(t, webhookService.Deliver(ctx, subscription.ID, message(3)))
+
+	db.First(&stored, subscription.ID)
+	assert.Equal(t, 3, stored.FailureCount)
+	assert.False(t, stored.Active)
+	assert.NotNil(t, stored.DisabledAt)
+	assert.Equal(t, "disabled after 3 consecutive failed deliveries", stored.DisabledReason)
+
+	// The disabled subscription isn't called anymore
+	assert.NoError(t, webhookService.Deliver(ctx, subscription.ID, message(4)))
+	assert.Equal(t, int32(3), received.Load())
+
+	var deliveries []models.WebhookDelivery
+	db.Where("subscription_id = ?", subscription.ID).Order("attempt").Find(&deliveries)
+	if assert.Len(t, deliveries, 3) {
+		for i, delivery := range deliveries {
+			assert.Equal(t, i+1, delivery.Attempt)
+			assert.Equal(t, "event-1", delivery.EventID)
+			assert.Equal(t, "order.shipped", delivery.EventType)
+			assert.Equal(t, http.StatusInternalServerError, delivery.StatusCode)
+			assert.Equal(t, "receiver is down", delivery.ResponseBody)
+			assert.False(t, delivery.Success)
+		}
+	}
+
+	truncateTables()
+}
+
+func TestWebhookService_Deliver_SuccessResetsTheFailures(t *testing.T) {
+	db := deps.Gorm().DB
+	webhookService := services.NewWebhookService(deps.App[*repository.WebhookRepository](), webhook.NewSender(5*time.Second, "taskgo-test"), 3)
+
+	var fail atomic.Bool
+	fail.Store(true)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	subscription := models.WebhookSubscription{Name: "Partner", URL: receiver.URL, Secret: "secret", Events: `["*"]`, Active: true}
+	db.Create(&subscription)
+
+	message := webhook.Message{ID: "event-2", Event: "order.shipped", Attempt: 1, Body: []byte(`{}`)}
+	assert.Error(t, webhookService.Deliver(context.Background(), subscription.ID, message))
+
+	fail.Store(false)
+	message.Attempt = 2
+	assert.NoError(t, webhookService.Deliver(context.Background(), subscription.ID, message))
+
+	var stored models.WebhookSubscription
+	db.First(&stored, subscription.ID)
+	assert.Equal(t, 0, stored.FailureCount)
+	assert.True(t, stored.Active)
+	assert.NotNil(t, stored.LastDeliveryAt)
+
+	var delivery models.WebhookDelivery
+	db.Where("subscription_id = ? AND attempt = ?", subscription.ID, 2).First(&delivery)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+
+	truncateTables()
+}
+
+func TestWebhookSubscription_Subscribes(t *testing.T) {
+	tests := []struct {
+		events    string
+		eventType string
+		expected  bool
+	}{
+		{`["*"]`, "order.shipped", true},
+		{`["order.*"]`, "order.shipped", true},
+		{`["order.*"]`, "stock.low", false},
+		{`["order.shipped"]`, "order.shipped", true},
+		{`["order.shipped"]`, "order.delivered", false},
+		{`["stock.low", "order.*"]`, "order.delivered", true},
+		{`[]`, "order.shipped", false},
+	}
+
+	for _, test := range tests {
+		subscription := models.WebhookSubscription{Events: test.events}
+		assert.Equal(t, test.expected, subscription.Subscribes(test.eventType), "%s subscribes to %s", test.events, test.eventType)
+	}
+}
+
+func TestOrderService_UpdateStatus_WritesTheWebhookDeliveriesToTheOutbox(t *testing.T) {
+	db := deps.Gorm().DB
+	ctx := context.Background()
+
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	subscription := models.WebhookSubscription{Name: "Partner", URL: receiver.URL, Secret: "secret", Events: `["order.*"]`, Active: true}
+	db.Create(&subscription)
+	db.Create(&models.WebhookSubscription{Name: "Stock", URL: receiver.URL, Secret: "secret", Events: `["stock.low"]`, Active: true})
+
+	user := models.User{FirstName: "Webhook", LastName: "User", Email: "webhook@test.com", Password: "password", PhoneNumber: "01012345678", Role: "customer", IsActive: true}
+	db.Create(&user)
+	order := models.Order{UserID: user.ID, Status: enums.OrderStatusPending, TotalAmount: 100, ShippingAddress: "Cairo, Egypt", BillingAddress: "Cairo, Egypt"}
+	db.Create(&order)
+
+	_, err := deps.App[*services.OrderService]().UpdateStatus(ctx, order.ID, enums.OrderStatusConfirmed, "")
+	require.NoError(t, err)
+
+	// Only the subscription receiving the event gets a delivery, written with the status change and left to the relay
+	var messages []models.OutboxMessage
+	db.Where("task_type = ?", tasks.TypeWebhookDelivery).Find(&messages)
+	require.Len(t, messages, 1)
+	assert.Equal(t, tasks.QueueWebhooks, messages[0].Queue)
+	assert.True(t, strings.HasPrefix(messages[0].TaskID, fmt.Sprintf("webhook:%d:", subscription.ID)))
+
+	handler := deps.App[*tasks.WebhookDeliveryHandler]()
+	require.NoError(t, handler.ProcessTask(ctx, asynq.NewTask(messages[0].TaskType, messages[0].Payload)))
+	assert.Equal(t, int32(1), received.Load())
+
+	truncateTables()
+}